@@ -1,20 +1,45 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
+	"os"
 	"time"
+	_ "time/tzdata"
 	"zadanie-6105/internal/app"
+	"zadanie-6105/internal/config"
+	"zadanie-6105/internal/database"
 )
 
+const exitDatabaseUnavailable = 3
+
 func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.TextFormatter{
 		TimestampFormat: time.DateTime,
 		FullTimestamp:   true,
 	})
-	application := app.NewApp(logger)
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			config.PrintDefaults(os.Stderr)
+			return
+		}
+		logger.Fatal(err)
+	}
+	if cfg.PrintConfig {
+		fmt.Print(cfg.String())
+		return
+	}
+	application := app.NewApp(logger, cfg)
 	if err := application.Start(); err != nil {
+		if errors.Is(err, database.ErrUnavailable) {
+			logger.Error(err)
+			os.Exit(exitDatabaseUnavailable)
+		}
 		logger.Fatal(err)
 	}
 }
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	_ "github.com/lib/pq"
+	"os"
+	"strconv"
+	"zadanie-6105/internal/config"
+	"zadanie-6105/internal/migrations"
+	"zadanie-6105/internal/seed"
+)
+
+const usage = `Usage: tenderadm [flags] <command> [args]
+
+Commands:
+  migrate up              apply all pending migrations
+  migrate down [N|all]    roll back N migrations (default 1) or all of them
+  migrate goto V          migrate up or down to version V
+  migrate status          show current version, dirty flag and available migrations
+  migrate force V         set version V and clear the dirty flag without running migrations
+  seed                    insert demo organizations, employees and responsibles
+
+Flags are the same as for the server, e.g. -config, -postgres-conn, -migrations-source.
+`
+
+func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprint(os.Stderr, usage+"\nFlags:\n")
+			config.PrintDefaults(os.Stderr)
+			os.Exit(0)
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(cfg.Args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch cfg.Args[0] {
+	case "migrate":
+		err = runMigrate(cfg, cfg.Args[1:])
+	case "seed":
+		err = runSeed(cfg)
+	default:
+		err = errUsage
+	}
+	if err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+var errUsage = errors.New("usage")
+
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	m, err := migrations.New(cfg.Migrations.Source, cfg.Postgres.Conn)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch args[0] {
+	case "up":
+		if err = m.Up(); err != nil {
+			return err
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if args[1] == "all" {
+				steps = 0
+			} else if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		if err = m.Down(steps); err != nil {
+			return err
+		}
+	case "goto":
+		if len(args) < 2 {
+			return errUsage
+		}
+		version, err := strconv.ParseUint(args[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err = m.To(uint(version)); err != nil {
+			return err
+		}
+	case "force":
+		if len(args) < 2 {
+			return errUsage
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err = m.Force(version); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return errUsage
+	}
+	return printStatus(m)
+}
+
+func printStatus(m *migrations.Migrator) error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("version: %d\ndirty: %t\n", status.Version, status.Dirty)
+	for _, v := range status.Available {
+		state := "pending"
+		if v <= status.Version {
+			state = "applied"
+		}
+		fmt.Printf("  %06d  %s\n", v, state)
+	}
+	return nil
+}
+
+func runSeed(cfg *config.Config) error {
+	db, err := sql.Open("postgres", cfg.Postgres.Conn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	res, err := seed.Run(db)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created organizations: %d, employees: %d, responsibles: %d\n",
+		res.Organizations, res.Employees, res.Responsibles)
+	return nil
+}
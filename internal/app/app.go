@@ -3,74 +3,128 @@ package app
 import (
 	"context"
 	"database/sql"
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/golang-migrate/migrate/v4/source/github"
 	"github.com/gorilla/mux"
-	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
+	"zadanie-6105/internal/config"
+	"zadanie-6105/internal/database"
+	"zadanie-6105/internal/migrations"
 	handlerBid "zadanie-6105/internal/pkg/bids/delivery/http"
 	repoBid "zadanie-6105/internal/pkg/bids/repo"
 	usecaseBid "zadanie-6105/internal/pkg/bids/usecase"
+	handlerClarification "zadanie-6105/internal/pkg/clarifications/delivery/http"
+	repoClarification "zadanie-6105/internal/pkg/clarifications/repo"
+	usecaseClarification "zadanie-6105/internal/pkg/clarifications/usecase"
+	"zadanie-6105/internal/pkg/clock"
+	handlerContract "zadanie-6105/internal/pkg/contracts/delivery/http"
+	repoContract "zadanie-6105/internal/pkg/contracts/repo"
+	usecaseContract "zadanie-6105/internal/pkg/contracts/usecase"
+	handlerEvaluation "zadanie-6105/internal/pkg/evaluation/delivery/http"
+	repoEvaluation "zadanie-6105/internal/pkg/evaluation/repo"
+	usecaseEvaluation "zadanie-6105/internal/pkg/evaluation/usecase"
 	"zadanie-6105/internal/pkg/middleware"
+	"zadanie-6105/internal/pkg/outbox"
+	repoOutbox "zadanie-6105/internal/pkg/outbox/repo"
+	"zadanie-6105/internal/pkg/outbox/sink"
+	handlerQualification "zadanie-6105/internal/pkg/qualifications/delivery/http"
+	repoQualification "zadanie-6105/internal/pkg/qualifications/repo"
+	usecaseQualification "zadanie-6105/internal/pkg/qualifications/usecase"
+	"zadanie-6105/internal/pkg/ratelimit"
+	repoRateLimit "zadanie-6105/internal/pkg/ratelimit/repo"
+	"zadanie-6105/internal/pkg/scheduler"
+	repoScheduler "zadanie-6105/internal/pkg/scheduler/repo"
+	"zadanie-6105/internal/pkg/sealing"
+	"zadanie-6105/internal/pkg/stream"
+	handlerStream "zadanie-6105/internal/pkg/stream/delivery/http"
+	repoStream "zadanie-6105/internal/pkg/stream/repo"
+	usecaseStream "zadanie-6105/internal/pkg/stream/usecase"
+	"zadanie-6105/internal/pkg/tenders"
 	handlerTender "zadanie-6105/internal/pkg/tenders/delivery/http"
 	repoTender "zadanie-6105/internal/pkg/tenders/repo"
 	usecaseTender "zadanie-6105/internal/pkg/tenders/usecase"
 	repoUser "zadanie-6105/internal/pkg/users/repo"
+	"zadanie-6105/internal/pkg/utils"
+	handlerWebhook "zadanie-6105/internal/pkg/webhooks/delivery/http"
+	repoWebhook "zadanie-6105/internal/pkg/webhooks/repo"
+	"zadanie-6105/internal/pkg/webhooks/sender"
+	usecaseWebhook "zadanie-6105/internal/pkg/webhooks/usecase"
 )
 
 type App struct {
 	log *logrus.Logger
+	cfg *config.Config
 }
 
-func NewApp(log *logrus.Logger) *App {
-	return &App{log: log}
+func NewApp(log *logrus.Logger, cfg *config.Config) *App {
+	return &App{log: log, cfg: cfg}
 }
 
 func (a *App) Start() error {
-	_ = godotenv.Load()
-	db, err := sql.Open("postgres", os.Getenv("POSTGRES_CONN"))
+	utils.SetPagination(int32(a.cfg.Pagination.DefaultLimit), int32(a.cfg.Pagination.MaxLimit))
+
+	connectCtx, stopConnect := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	db, err := database.Connect(connectCtx, a.cfg.Postgres, a.log)
+	stopConnect()
 	if err != nil {
-		a.log.Error("failed to connect database ", err.Error())
-	}
-	if err = db.Ping(); err != nil {
-		a.log.Error("failed to ping database ", err.Error())
+		return err
 	}
 	defer db.Close()
 
-	m, err := migrate.New("file://schema", os.Getenv("POSTGRES_CONN"))
-	if err != nil {
-		a.log.Error("failed to create migrate: ", err.Error())
-	}
-	if err == nil {
-		if err = m.Up(); err != nil && err != migrate.ErrNoChange {
-			log.Fatalf("Failed to run migrations: %v", err)
-		}
+	if a.cfg.Migrations.Skip {
+		a.log.Info("auto-migration is disabled")
+	} else if err = a.migrate(); err != nil {
+		return err
 	}
 
 	r := mux.NewRouter().PathPrefix("/api").Subrouter()
+	if a.cfg.RateLimit.Enabled {
+		r.Use(a.rateLimiter(db).Limit)
+	}
+
+	handler := middleware.Chain(r,
+		middleware.Recovery(a.log),
+		middleware.MaxBodySize(int64(a.cfg.Server.MaxBodyBytes)),
+		middleware.RequireJSON,
+	)
 
 	srv := &http.Server{
-		Addr:         os.Getenv("SERVER_ADDRESS"),
-		Handler:      r,
-		ReadTimeout:  50 * time.Second,
-		WriteTimeout: 50 * time.Second,
+		Addr:         a.cfg.Server.Address,
+		Handler:      handler,
+		ReadTimeout:  a.cfg.Server.ReadTimeout,
+		WriteTimeout: a.cfg.Server.WriteTimeout,
 	}
 
 	r.HandleFunc("/ping", ping).Methods(http.MethodGet)
 
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	var bg sync.WaitGroup
+	defer func() {
+		stopBackground()
+		bg.Wait()
+	}()
+
+	dispatcher := outbox.NewDispatcher(repoOutbox.NewRepository(db), a.cfg.Outbox.PollInterval, a.cfg.Outbox.BatchSize,
+		a.log, sink.NewLogSink(a.log))
+
+	clk := clock.Real{}
+
 	uRepo := repoUser.NewRepository(db)
 	md := middleware.NewMiddleware(uRepo)
 
+	var sealer tenders.Sealer
+	if a.cfg.Sealing.MasterKey != "" {
+		if sealer, err = sealing.NewSealer(a.cfg.Sealing.MasterKey); err != nil {
+			return err
+		}
+	}
+
 	tRepo := repoTender.NewRepository(db)
-	tUsecase := usecaseTender.NewUsecase(tRepo)
+	tUsecase := usecaseTender.NewUsecase(tRepo, clk, sealer)
 	tHandler := handlerTender.NewHandler(tUsecase)
 
 	r.HandleFunc("/tenders", tHandler.GetTendersList).Methods(http.MethodGet)
@@ -79,9 +133,37 @@ func (a *App) Start() error {
 	r.Handle("/tenders/{tenderId}/status", md.UserExistsMiddleware(http.HandlerFunc(tHandler.GetTenderStatus))).Methods(http.MethodGet)
 	r.Handle("/tenders/{tenderId}/status", md.UserExistsMiddleware(http.HandlerFunc(tHandler.EditTenderStatus))).Methods(http.MethodPut)
 	r.Handle("/tenders/{tenderId}/edit", md.UserExistsMiddleware(http.HandlerFunc(tHandler.EditTender))).Methods(http.MethodPatch)
+	r.Handle("/tenders/{tenderId}/cancel", md.UserExistsMiddleware(http.HandlerFunc(tHandler.CancelTender))).Methods(http.MethodPut)
+	r.Handle("/tenders/{tenderId}/publication", md.UserExistsMiddleware(http.HandlerFunc(tHandler.SchedulePublication))).Methods(http.MethodPut)
+	r.Handle("/tenders/{tenderId}/publication", md.UserExistsMiddleware(http.HandlerFunc(tHandler.CancelPublication))).Methods(http.MethodDelete)
+	r.HandleFunc("/tenders/{tenderId}/items", tHandler.GetTenderItems).Methods(http.MethodGet)
+	r.Handle("/tenders/{tenderId}/items", md.UserExistsMiddleware(http.HandlerFunc(tHandler.ReplaceTenderItems))).Methods(http.MethodPut)
+	r.Handle("/tenders/{tenderId}/auction", md.UserExistsMiddleware(http.HandlerFunc(tHandler.ConfigureAuction))).Methods(http.MethodPut)
+	r.HandleFunc("/tenders/{tenderId}/lots", tHandler.GetTenderLots).Methods(http.MethodGet)
+	r.Handle("/tenders/{tenderId}/lots", md.UserExistsMiddleware(http.HandlerFunc(tHandler.ReplaceTenderLots))).Methods(http.MethodPut)
+	r.Handle("/tenders/{tenderId}/lots/{lotId}/cancel", md.UserExistsMiddleware(http.HandlerFunc(tHandler.CancelLot))).Methods(http.MethodPut)
+	r.Handle("/tenders/{tenderId}/invitations", md.UserExistsMiddleware(http.HandlerFunc(tHandler.GetTenderInvitations))).Methods(http.MethodGet)
+	r.Handle("/tenders/{tenderId}/invitations", md.UserExistsMiddleware(http.HandlerFunc(tHandler.InviteToTender))).Methods(http.MethodPost)
+	r.Handle("/tenders/{tenderId}/invitations/{invitationId}", md.UserExistsMiddleware(http.HandlerFunc(tHandler.RevokeInvitation))).Methods(http.MethodDelete)
+	r.Handle("/invitations/my", md.UserExistsMiddleware(http.HandlerFunc(tHandler.GetUserInvitations))).Methods(http.MethodGet)
+	r.Handle("/invitations/{invitationId}/accept", md.UserExistsMiddleware(http.HandlerFunc(tHandler.AcceptInvitation))).Methods(http.MethodPut)
+	r.Handle("/invitations/{invitationId}/decline", md.UserExistsMiddleware(http.HandlerFunc(tHandler.DeclineInvitation))).Methods(http.MethodPut)
+	r.HandleFunc("/tenders/{tenderId}", tHandler.GetTender).Methods(http.MethodGet)
+	r.HandleFunc("/tenders/{tenderId}/award", tHandler.GetAward).Methods(http.MethodGet)
+
+	qUsecase := usecaseQualification.NewUsecase(repoQualification.NewRepository(db), tRepo, clk)
+	qHandler := handlerQualification.NewHandler(qUsecase)
+
+	r.HandleFunc("/tenders/{tenderId}/requirements", qHandler.GetRequirements).Methods(http.MethodGet)
+	r.Handle("/tenders/{tenderId}/requirements", md.UserExistsMiddleware(http.HandlerFunc(qHandler.ReplaceRequirements))).Methods(http.MethodPut)
+	r.Handle("/tenders/{tenderId}/prequalification", md.UserExistsMiddleware(http.HandlerFunc(qHandler.GetApplications))).Methods(http.MethodGet)
+	r.Handle("/tenders/{tenderId}/prequalification", md.UserExistsMiddleware(http.HandlerFunc(qHandler.Apply))).Methods(http.MethodPost)
+	r.Handle("/prequalification/{applicationId}/approve", md.UserExistsMiddleware(http.HandlerFunc(qHandler.ApproveApplication))).Methods(http.MethodPut)
+	r.Handle("/prequalification/{applicationId}/reject", md.UserExistsMiddleware(http.HandlerFunc(qHandler.RejectApplication))).Methods(http.MethodPut)
 
 	bRepo := repoBid.NewRepository(db)
-	bUsecase := usecaseBid.NewBidUsecase(bRepo)
+	eUsecase := usecaseEvaluation.NewUsecase(repoEvaluation.NewRepository(db), tRepo, bRepo, clk)
+	bUsecase := usecaseBid.NewBidUsecase(bRepo, tRepo, eUsecase, qUsecase, sealer, clk)
 	bHandler := handlerBid.NewHandler(bUsecase)
 
 	r.HandleFunc("/bids/new", bHandler.CreateNewBid).Methods(http.MethodPost)
@@ -89,8 +171,125 @@ func (a *App) Start() error {
 	r.Handle("/bids/{tenderId}/list", md.UserExistsMiddleware(http.HandlerFunc(bHandler.GetTenderBids))).Methods(http.MethodGet)
 	r.Handle("/bids/{bidId}/status", md.UserExistsMiddleware(http.HandlerFunc(bHandler.GetBidStatus))).Methods(http.MethodGet)
 	r.Handle("/bids/{bidId}/status", md.UserExistsMiddleware(http.HandlerFunc(bHandler.EditBidStatus))).Methods(http.MethodPut)
+	r.Handle("/bids/{bidId}/items", md.UserExistsMiddleware(http.HandlerFunc(bHandler.GetBidItems))).Methods(http.MethodGet)
 	r.Handle("/bids/{bidId}/edit", md.UserExistsMiddleware(http.HandlerFunc(bHandler.EditBid))).Methods(http.MethodPatch)
+	r.Handle("/bids/{bidId}/withdraw", md.UserExistsMiddleware(http.HandlerFunc(bHandler.WithdrawBid))).Methods(http.MethodPut)
 	r.Handle("/bids/{bidId}/submit_decision", md.UserExistsMiddleware(http.HandlerFunc(bHandler.SubmitDecision))).Methods(http.MethodPut)
+	r.Handle("/tenders/{tenderId}/opening_protocol", md.UserExistsMiddleware(http.HandlerFunc(bHandler.GetOpeningProtocol))).Methods(http.MethodGet)
+	r.Handle("/bids/{bidId}/technical_review", md.UserExistsMiddleware(http.HandlerFunc(bHandler.ReviewTechnical))).Methods(http.MethodPut)
+	r.Handle("/bids/{tenderId}/technical", md.UserExistsMiddleware(http.HandlerFunc(bHandler.GetTechnicalEnvelopes))).Methods(http.MethodGet)
+	r.Handle("/bids/{tenderId}/financial", md.UserExistsMiddleware(http.HandlerFunc(bHandler.GetFinancialEnvelopes))).Methods(http.MethodGet)
+	r.Handle("/bids/{tenderId}/phase", md.UserExistsMiddleware(http.HandlerFunc(bHandler.AdvancePhase))).Methods(http.MethodPut)
+	r.Handle("/tenders/{tenderId}/auction", md.UserExistsMiddleware(http.HandlerFunc(bHandler.GetAuction))).Methods(http.MethodGet)
+	r.Handle("/bids/{bidId}/auction_price", md.UserExistsMiddleware(http.HandlerFunc(bHandler.PlaceAuctionPrice))).Methods(http.MethodPost)
+	r.Handle("/bids/{bidId}/lots", md.UserExistsMiddleware(http.HandlerFunc(bHandler.GetBidLots))).Methods(http.MethodGet)
+	r.Handle("/bids/{bidId}/lots/{lotId}/submit_decision", md.UserExistsMiddleware(http.HandlerFunc(bHandler.SubmitLotDecision))).Methods(http.MethodPut)
+	r.Handle("/bids/{bidId}", md.UserExistsMiddleware(http.HandlerFunc(bHandler.GetBid))).Methods(http.MethodGet)
+
+	eHandler := handlerEvaluation.NewHandler(eUsecase)
+
+	r.HandleFunc("/tenders/{tenderId}/criteria", eHandler.GetCriteria).Methods(http.MethodGet)
+	r.Handle("/tenders/{tenderId}/criteria", md.UserExistsMiddleware(http.HandlerFunc(eHandler.ReplaceCriteria))).Methods(http.MethodPut)
+	r.Handle("/tenders/{tenderId}/ranking", md.UserExistsMiddleware(http.HandlerFunc(eHandler.GetRanking))).Methods(http.MethodGet)
+	r.Handle("/tenders/{tenderId}/ranking/{rankingId}", md.UserExistsMiddleware(http.HandlerFunc(eHandler.GetRankingSnapshot))).Methods(http.MethodGet)
+	r.Handle("/bids/{bidId}/scores", md.UserExistsMiddleware(http.HandlerFunc(eHandler.GetBidScores))).Methods(http.MethodGet)
+	r.Handle("/bids/{bidId}/scores", md.UserExistsMiddleware(http.HandlerFunc(eHandler.ScoreBid))).Methods(http.MethodPut)
+
+	cUsecase := usecaseClarification.NewUsecase(repoClarification.NewRepository(db), tRepo, clk)
+	cHandler := handlerClarification.NewHandler(cUsecase)
+
+	r.HandleFunc("/tenders/{tenderId}/questions", cHandler.GetQuestions).Methods(http.MethodGet)
+	r.Handle("/tenders/{tenderId}/questions", md.UserExistsMiddleware(http.HandlerFunc(cHandler.AskQuestion))).Methods(http.MethodPost)
+	r.Handle("/tenders/{tenderId}/questions/{questionId}/answer", md.UserExistsMiddleware(http.HandlerFunc(cHandler.AnswerQuestion))).Methods(http.MethodPut)
+
+	ctUsecase := usecaseContract.NewUsecase(repoContract.NewRepository(db), tRepo, clk)
+	ctHandler := handlerContract.NewHandler(ctUsecase)
+
+	r.Handle("/tenders/{tenderId}/contract", md.UserExistsMiddleware(http.HandlerFunc(ctHandler.GetTenderContract))).Methods(http.MethodGet)
+	r.Handle("/tenders/{tenderId}/contract", md.UserExistsMiddleware(http.HandlerFunc(ctHandler.CreateContract))).Methods(http.MethodPost)
+	r.Handle("/tenders/{tenderId}/lots/{lotId}/contract", md.UserExistsMiddleware(http.HandlerFunc(ctHandler.GetTenderContract))).Methods(http.MethodGet)
+	r.Handle("/tenders/{tenderId}/lots/{lotId}/contract", md.UserExistsMiddleware(http.HandlerFunc(ctHandler.CreateContract))).Methods(http.MethodPost)
+	r.Handle("/contracts/{contractId}", md.UserExistsMiddleware(http.HandlerFunc(ctHandler.GetContract))).Methods(http.MethodGet)
+	r.Handle("/contracts/{contractId}/milestones", md.UserExistsMiddleware(http.HandlerFunc(ctHandler.AddMilestone))).Methods(http.MethodPost)
+	r.Handle("/contracts/{contractId}/milestones/{milestoneId}/progress", md.UserExistsMiddleware(http.HandlerFunc(ctHandler.UpdateProgress))).Methods(http.MethodPut)
+	r.Handle("/contracts/{contractId}/milestones/{milestoneId}/accept", md.UserExistsMiddleware(http.HandlerFunc(ctHandler.AcceptMilestone))).Methods(http.MethodPut)
+	r.Handle("/contracts/{contractId}/milestones/{milestoneId}/reject", md.UserExistsMiddleware(http.HandlerFunc(ctHandler.RejectMilestone))).Methods(http.MethodPut)
+
+	whRepo := repoWebhook.NewRepository(db)
+	whUsecase := usecaseWebhook.NewUsecase(whRepo, a.cfg.Webhooks.AllowPrivateTargets)
+	whHandler := handlerWebhook.NewHandler(whUsecase)
+
+	r.Handle("/organizations/{organizationId}/webhooks", md.UserExistsMiddleware(http.HandlerFunc(whHandler.CreateWebhook))).Methods(http.MethodPost)
+	r.Handle("/organizations/{organizationId}/webhooks", md.UserExistsMiddleware(http.HandlerFunc(whHandler.GetWebhooks))).Methods(http.MethodGet)
+	r.Handle("/webhooks/{webhookId}", md.UserExistsMiddleware(http.HandlerFunc(whHandler.DeleteWebhook))).Methods(http.MethodDelete)
+	r.Handle("/webhooks/{webhookId}/deliveries", md.UserExistsMiddleware(http.HandlerFunc(whHandler.GetDeliveries))).Methods(http.MethodGet)
+	r.Handle("/webhooks/deliveries/{deliveryId}/attempts", md.UserExistsMiddleware(http.HandlerFunc(whHandler.GetAttempts))).Methods(http.MethodGet)
+	r.Handle("/webhooks/deliveries/{deliveryId}/redeliver", md.UserExistsMiddleware(http.HandlerFunc(whHandler.Redeliver))).Methods(http.MethodPost)
+
+	if a.cfg.Webhooks.Enabled {
+		dispatcher.AddSink(sender.NewSink(whRepo))
+		worker := sender.NewWorker(whRepo, sender.Options{
+			PollInterval:   a.cfg.Webhooks.PollInterval,
+			BatchSize:      a.cfg.Webhooks.BatchSize,
+			Timeout:        a.cfg.Webhooks.Timeout,
+			MaxAttempts:    a.cfg.Webhooks.MaxAttempts,
+			InitialBackoff: a.cfg.Webhooks.InitialBackoff,
+			MaxBackoff:     a.cfg.Webhooks.MaxBackoff,
+			AllowPrivate:   a.cfg.Webhooks.AllowPrivateTargets,
+		}, a.log)
+		a.background(bgCtx, &bg, worker.Run)
+	}
+
+	if a.cfg.Stream.Enabled {
+		sRepo := repoStream.NewRepository(db)
+		broker := stream.NewBroker()
+		sUsecase := usecaseStream.NewUsecase(sRepo, broker, a.cfg.Stream.ReplayLimit)
+		sHandler := handlerStream.NewHandler(sUsecase, a.cfg.Stream.Heartbeat)
+
+		r.Handle("/events/stream", md.UserExistsMiddleware(http.HandlerFunc(sHandler.Stream))).Methods(http.MethodGet)
+		srv.RegisterOnShutdown(sHandler.Shutdown)
+
+		dispatcher.AddSink(stream.NewNotifySink(sRepo))
+		a.background(bgCtx, &bg, stream.NewListener(a.cfg.Postgres.Conn, sRepo, broker, a.log).Run)
+	}
+
+	if a.cfg.Scheduler.Enabled {
+		sched := scheduler.NewScheduler(repoScheduler.NewAdvisoryLocker(db), clk, a.cfg.Scheduler.Interval, a.log)
+		sched.AddJob(scheduler.NewJob("close-expired-tenders", func(ctx context.Context, now time.Time) error {
+			closed, err := tUsecase.CloseExpiredTenders(now)
+			if err != nil {
+				return err
+			}
+			for _, tender := range closed {
+				a.log.WithField("tender", tender.Id).Info("tender closed after submission deadline")
+			}
+			return nil
+		}))
+		sched.AddJob(scheduler.NewJob("publish-scheduled-tenders", func(ctx context.Context, now time.Time) error {
+			published, err := tUsecase.PublishScheduledTenders(now)
+			for _, tender := range published {
+				a.log.WithField("tender", tender.Id).Info("tender published on schedule")
+			}
+			return err
+		}))
+		sched.AddJob(scheduler.NewJob("open-sealed-bids", func(ctx context.Context, now time.Time) error {
+			opened, err := bUsecase.OpenSealedBids(now)
+			for _, protocol := range opened {
+				a.log.WithField("tender", protocol.TenderId).Info("sealed bids opened")
+			}
+			return err
+		}))
+		sched.AddJob(scheduler.NewJob("mark-overdue-milestones", func(ctx context.Context, now time.Time) error {
+			overdue, err := ctUsecase.MarkOverdueMilestones(now)
+			for _, milestone := range overdue {
+				a.log.WithField("contract", milestone.ContractId).WithField("milestone", milestone.Id).Info("milestone overdue")
+			}
+			return err
+		}))
+		a.background(bgCtx, &bg, sched.Run)
+	}
+
+	a.background(bgCtx, &bg, dispatcher.Run)
 
 	signalCh := make(chan os.Signal, 1)
 	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGTERM)
@@ -105,7 +304,7 @@ func (a *App) Start() error {
 	sig := <-signalCh
 	a.log.Info("Received signal: ", sig)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
@@ -114,6 +313,40 @@ func (a *App) Start() error {
 	return nil
 }
 
+func (a *App) background(ctx context.Context, wg *sync.WaitGroup, run func(ctx context.Context)) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		run(ctx)
+	}()
+}
+
+func (a *App) migrate() error {
+	m, err := migrations.New(a.cfg.Migrations.Source, a.cfg.Postgres.Conn)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	return m.Up()
+}
+
+func (a *App) rateLimiter(db *sql.DB) *middleware.RateLimitMiddleware {
+	var store ratelimit.Store = ratelimit.NewMemoryStore()
+	if a.cfg.RateLimit.Store == "postgres" {
+		store = repoRateLimit.NewRepository(db)
+	}
+	routes := make(map[string]ratelimit.Limit, len(a.cfg.RateLimit.Routes))
+	for route, rule := range a.cfg.RateLimit.Routes {
+		routes[route] = toLimit(rule)
+	}
+	return middleware.NewRateLimitMiddleware(store, toLimit(a.cfg.RateLimit.Default), routes,
+		repoUser.NewRepository(db), a.cfg.RateLimit.TrustProxyHeaders, clock.Real{}, a.log)
+}
+
+func toLimit(rule config.RateLimitRule) ratelimit.Limit {
+	return ratelimit.Limit{Requests: rule.Requests, Per: rule.Per, Burst: rule.Burst}
+}
+
 func ping(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("ok"))
 }
@@ -0,0 +1,535 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Server     ServerConfig     `yaml:"server"`
+	Postgres   PostgresConfig   `yaml:"postgres"`
+	Pagination PaginationConfig `yaml:"pagination"`
+	Migrations MigrationsConfig `yaml:"migrations"`
+	RateLimit  RateLimitConfig  `yaml:"rateLimit"`
+	Outbox     OutboxConfig     `yaml:"outbox"`
+	Webhooks   WebhooksConfig   `yaml:"webhooks"`
+	Stream     StreamConfig     `yaml:"stream"`
+	Scheduler  SchedulerConfig  `yaml:"scheduler"`
+	Sealing    SealingConfig    `yaml:"sealing"`
+
+	Args        []string `yaml:"-"`
+	ConfigPath  string   `yaml:"-"`
+	PrintConfig bool     `yaml:"-"`
+}
+
+type ServerConfig struct {
+	Address         string        `yaml:"address"`
+	ReadTimeout     time.Duration `yaml:"readTimeout"`
+	WriteTimeout    time.Duration `yaml:"writeTimeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	MaxBodyBytes    int           `yaml:"maxBodyBytes"`
+}
+
+type PostgresConfig struct {
+	Conn                string        `yaml:"conn"`
+	ConnectTimeout      time.Duration `yaml:"connectTimeout"`
+	RetryInitialBackoff time.Duration `yaml:"retryInitialBackoff"`
+	RetryMaxBackoff     time.Duration `yaml:"retryMaxBackoff"`
+	MaxOpenConns        int           `yaml:"maxOpenConns"`
+	MaxIdleConns        int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime     time.Duration `yaml:"connMaxLifetime"`
+}
+
+type PaginationConfig struct {
+	DefaultLimit int `yaml:"defaultLimit"`
+	MaxLimit     int `yaml:"maxLimit"`
+}
+
+type MigrationsConfig struct {
+	Source string `yaml:"source"`
+	Skip   bool   `yaml:"skip"`
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration `yaml:"pollInterval"`
+	BatchSize    int           `yaml:"batchSize"`
+}
+
+type WebhooksConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	PollInterval   time.Duration `yaml:"pollInterval"`
+	BatchSize      int           `yaml:"batchSize"`
+	Timeout        time.Duration `yaml:"timeout"`
+	MaxAttempts    int           `yaml:"maxAttempts"`
+	InitialBackoff time.Duration `yaml:"initialBackoff"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff"`
+	// AllowPrivateTargets lets webhooks reach loopback and private networks,
+	// which is only meant for local development.
+	AllowPrivateTargets bool `yaml:"allowPrivateTargets"`
+}
+
+type StreamConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	Heartbeat   time.Duration `yaml:"heartbeat"`
+	ReplayLimit int           `yaml:"replayLimit"`
+}
+
+type SchedulerConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// SealingConfig holds the base64-encoded 32-byte key that wraps per-tender
+// bid encryption keys. Sealed tenders are rejected while it is empty.
+type SealingConfig struct {
+	MasterKey string `yaml:"masterKey"`
+}
+
+type RateLimitConfig struct {
+	Enabled           bool                     `yaml:"enabled"`
+	Store             string                   `yaml:"store"`
+	TrustProxyHeaders bool                     `yaml:"trustProxyHeaders"`
+	Default           RateLimitRule            `yaml:"default"`
+	Routes            map[string]RateLimitRule `yaml:"routes"`
+}
+
+// RateLimitRule allows Requests per Per on average with bursts of up to Burst requests.
+type RateLimitRule struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
+}
+
+func (r RateLimitRule) validate(name string) error {
+	if r.Requests <= 0 || r.Per <= 0 || r.Burst <= 0 {
+		return fmt.Errorf("rateLimit %s: requests, per and burst must be positive", name)
+	}
+	return nil
+}
+
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Address:         "0.0.0.0:8080",
+			ReadTimeout:     50 * time.Second,
+			WriteTimeout:    50 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+			MaxBodyBytes:    1 << 20,
+		},
+		Postgres: PostgresConfig{
+			ConnectTimeout:      time.Minute,
+			RetryInitialBackoff: 500 * time.Millisecond,
+			RetryMaxBackoff:     10 * time.Second,
+			MaxOpenConns:        25,
+			MaxIdleConns:        10,
+			ConnMaxLifetime:     30 * time.Minute,
+		},
+		Pagination: PaginationConfig{
+			DefaultLimit: 5,
+			MaxLimit:     50,
+		},
+		Migrations: MigrationsConfig{
+			Source: "file://schema",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   "memory",
+			Default: RateLimitRule{Requests: 20, Per: time.Second, Burst: 40},
+			Routes: map[string]RateLimitRule{
+				"POST /api/bids/new":    {Requests: 10, Per: time.Minute, Burst: 5},
+				"POST /api/tenders/new": {Requests: 10, Per: time.Minute, Burst: 5},
+				"GET /api/tenders":      {Requests: 60, Per: time.Minute, Burst: 20},
+			},
+		},
+		Outbox: OutboxConfig{
+			PollInterval: time.Second,
+			BatchSize:    100,
+		},
+		Webhooks: WebhooksConfig{
+			Enabled:        true,
+			PollInterval:   2 * time.Second,
+			BatchSize:      20,
+			Timeout:        10 * time.Second,
+			MaxAttempts:    8,
+			InitialBackoff: 30 * time.Second,
+			MaxBackoff:     time.Hour,
+		},
+		Stream: StreamConfig{
+			Enabled:     true,
+			Heartbeat:   15 * time.Second,
+			ReplayLimit: 500,
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:  true,
+			Interval: 30 * time.Second,
+		},
+	}
+}
+
+// Load builds the config with increasing priority: defaults, YAML file,
+// environment (including .env) and command line flags.
+func Load(args []string) (*Config, error) {
+	_ = godotenv.Load()
+
+	parsed := flag.NewFlagSet("tenders", flag.ContinueOnError)
+	parsed.SetOutput(io.Discard)
+	bindFlags(parsed, Default())
+	if err := parsed.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	cfg.ConfigPath = os.Getenv("CONFIG_PATH")
+	if f := parsed.Lookup("config"); f != nil && f.Value.String() != "" {
+		cfg.ConfigPath = f.Value.String()
+	}
+	if cfg.ConfigPath != "" {
+		if err := cfg.loadFile(cfg.ConfigPath); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	final := flag.NewFlagSet("tenders", flag.ContinueOnError)
+	bindFlags(final, cfg)
+	var setErr error
+	parsed.Visit(func(f *flag.Flag) {
+		if err := final.Set(f.Name, f.Value.String()); err != nil && setErr == nil {
+			setErr = fmt.Errorf("flag -%s: %w", f.Name, err)
+		}
+	})
+	if setErr != nil {
+		return nil, setErr
+	}
+
+	cfg.Args = parsed.Args()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// PrintDefaults writes the flag reference; Load leaves printing help to the
+// caller so each command shows it once.
+func PrintDefaults(w io.Writer) {
+	usage := flag.NewFlagSet("tenders", flag.ContinueOnError)
+	bindFlags(usage, Default())
+	usage.SetOutput(w)
+	usage.PrintDefaults()
+}
+
+func bindFlags(fs *flag.FlagSet, cfg *Config) {
+	fs.StringVar(&cfg.ConfigPath, "config", cfg.ConfigPath, "path to YAML config file")
+	fs.BoolVar(&cfg.PrintConfig, "print-config", cfg.PrintConfig, "print effective config and exit")
+	fs.StringVar(&cfg.Server.Address, "addr", cfg.Server.Address, "HTTP listen address")
+	fs.DurationVar(&cfg.Server.ReadTimeout, "read-timeout", cfg.Server.ReadTimeout, "HTTP read timeout")
+	fs.DurationVar(&cfg.Server.WriteTimeout, "write-timeout", cfg.Server.WriteTimeout, "HTTP write timeout")
+	fs.DurationVar(&cfg.Server.ShutdownTimeout, "shutdown-timeout", cfg.Server.ShutdownTimeout, "graceful shutdown period")
+	fs.IntVar(&cfg.Server.MaxBodyBytes, "max-body-bytes", cfg.Server.MaxBodyBytes, "maximum request body size in bytes")
+	fs.StringVar(&cfg.Postgres.Conn, "postgres-conn", cfg.Postgres.Conn, "PostgreSQL connection string")
+	fs.DurationVar(&cfg.Postgres.ConnectTimeout, "postgres-connect-timeout", cfg.Postgres.ConnectTimeout, "how long to retry the initial database connection")
+	fs.DurationVar(&cfg.Postgres.RetryInitialBackoff, "postgres-retry-initial-backoff", cfg.Postgres.RetryInitialBackoff, "initial delay between connection attempts")
+	fs.DurationVar(&cfg.Postgres.RetryMaxBackoff, "postgres-retry-max-backoff", cfg.Postgres.RetryMaxBackoff, "maximum delay between connection attempts")
+	fs.IntVar(&cfg.Postgres.MaxOpenConns, "postgres-max-open-conns", cfg.Postgres.MaxOpenConns, "maximum open connections")
+	fs.IntVar(&cfg.Postgres.MaxIdleConns, "postgres-max-idle-conns", cfg.Postgres.MaxIdleConns, "maximum idle connections")
+	fs.DurationVar(&cfg.Postgres.ConnMaxLifetime, "postgres-conn-max-lifetime", cfg.Postgres.ConnMaxLifetime, "maximum connection lifetime")
+	fs.IntVar(&cfg.Pagination.DefaultLimit, "page-default-limit", cfg.Pagination.DefaultLimit, "default page size")
+	fs.IntVar(&cfg.Pagination.MaxLimit, "page-max-limit", cfg.Pagination.MaxLimit, "maximum page size")
+	fs.StringVar(&cfg.Migrations.Source, "migrations-source", cfg.Migrations.Source, "migrations source URL")
+	fs.BoolVar(&cfg.Migrations.Skip, "skip-migrations", cfg.Migrations.Skip, "do not apply migrations on startup")
+	fs.DurationVar(&cfg.Outbox.PollInterval, "outbox-poll-interval", cfg.Outbox.PollInterval, "how often the outbox dispatcher polls for events")
+	fs.IntVar(&cfg.Outbox.BatchSize, "outbox-batch-size", cfg.Outbox.BatchSize, "maximum events dispatched per batch")
+	fs.BoolVar(&cfg.Webhooks.Enabled, "webhooks", cfg.Webhooks.Enabled, "enable outgoing webhooks")
+	fs.DurationVar(&cfg.Webhooks.Timeout, "webhooks-timeout", cfg.Webhooks.Timeout, "timeout of a single webhook request")
+	fs.IntVar(&cfg.Webhooks.MaxAttempts, "webhooks-max-attempts", cfg.Webhooks.MaxAttempts, "attempts before a delivery is dead-lettered")
+	fs.BoolVar(&cfg.Stream.Enabled, "stream", cfg.Stream.Enabled, "enable the server-sent events stream")
+	fs.BoolVar(&cfg.Scheduler.Enabled, "scheduler", cfg.Scheduler.Enabled, "run scheduled jobs in this process")
+	fs.DurationVar(&cfg.Scheduler.Interval, "scheduler-interval", cfg.Scheduler.Interval, "how often scheduled jobs run")
+	fs.BoolVar(&cfg.RateLimit.Enabled, "rate-limit", cfg.RateLimit.Enabled, "enable rate limiting")
+	fs.StringVar(&cfg.RateLimit.Store, "rate-limit-store", cfg.RateLimit.Store, "rate limit bucket store: memory or postgres")
+	fs.BoolVar(&cfg.RateLimit.TrustProxyHeaders, "rate-limit-trust-proxy", cfg.RateLimit.TrustProxyHeaders, "take client IP from X-Forwarded-For")
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer f.Close()
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	var err error
+	setString(&c.Server.Address, "SERVER_ADDRESS")
+	setString(&c.Postgres.Conn, "POSTGRES_CONN")
+	if err = setDuration(&c.Server.ReadTimeout, "SERVER_READ_TIMEOUT"); err != nil {
+		return err
+	}
+	if err = setDuration(&c.Server.WriteTimeout, "SERVER_WRITE_TIMEOUT"); err != nil {
+		return err
+	}
+	if err = setDuration(&c.Server.ShutdownTimeout, "SERVER_SHUTDOWN_TIMEOUT"); err != nil {
+		return err
+	}
+	if err = setInt(&c.Server.MaxBodyBytes, "SERVER_MAX_BODY_BYTES"); err != nil {
+		return err
+	}
+	if err = setDuration(&c.Postgres.ConnectTimeout, "POSTGRES_CONNECT_TIMEOUT"); err != nil {
+		return err
+	}
+	if err = setDuration(&c.Postgres.RetryInitialBackoff, "POSTGRES_RETRY_INITIAL_BACKOFF"); err != nil {
+		return err
+	}
+	if err = setDuration(&c.Postgres.RetryMaxBackoff, "POSTGRES_RETRY_MAX_BACKOFF"); err != nil {
+		return err
+	}
+	if err = setInt(&c.Postgres.MaxOpenConns, "POSTGRES_MAX_OPEN_CONNS"); err != nil {
+		return err
+	}
+	if err = setInt(&c.Postgres.MaxIdleConns, "POSTGRES_MAX_IDLE_CONNS"); err != nil {
+		return err
+	}
+	if err = setDuration(&c.Postgres.ConnMaxLifetime, "POSTGRES_CONN_MAX_LIFETIME"); err != nil {
+		return err
+	}
+	if err = setInt(&c.Pagination.DefaultLimit, "PAGINATION_DEFAULT_LIMIT"); err != nil {
+		return err
+	}
+	if err = setInt(&c.Pagination.MaxLimit, "PAGINATION_MAX_LIMIT"); err != nil {
+		return err
+	}
+	setString(&c.Migrations.Source, "MIGRATIONS_SOURCE")
+	setString(&c.Sealing.MasterKey, "SEALING_MASTER_KEY")
+	if err = setBool(&c.Migrations.Skip, "SKIP_MIGRATIONS"); err != nil {
+		return err
+	}
+	if err = setDuration(&c.Outbox.PollInterval, "OUTBOX_POLL_INTERVAL"); err != nil {
+		return err
+	}
+	if err = setInt(&c.Outbox.BatchSize, "OUTBOX_BATCH_SIZE"); err != nil {
+		return err
+	}
+	if err = setBool(&c.Webhooks.Enabled, "WEBHOOKS_ENABLED"); err != nil {
+		return err
+	}
+	if err = setDuration(&c.Webhooks.Timeout, "WEBHOOKS_TIMEOUT"); err != nil {
+		return err
+	}
+	if err = setInt(&c.Webhooks.MaxAttempts, "WEBHOOKS_MAX_ATTEMPTS"); err != nil {
+		return err
+	}
+	if err = setBool(&c.Webhooks.AllowPrivateTargets, "WEBHOOKS_ALLOW_PRIVATE_TARGETS"); err != nil {
+		return err
+	}
+	if err = setBool(&c.Stream.Enabled, "STREAM_ENABLED"); err != nil {
+		return err
+	}
+	if err = setBool(&c.Scheduler.Enabled, "SCHEDULER_ENABLED"); err != nil {
+		return err
+	}
+	if err = setDuration(&c.Scheduler.Interval, "SCHEDULER_INTERVAL"); err != nil {
+		return err
+	}
+	if err = setBool(&c.RateLimit.Enabled, "RATE_LIMIT_ENABLED"); err != nil {
+		return err
+	}
+	setString(&c.RateLimit.Store, "RATE_LIMIT_STORE")
+	if err = setBool(&c.RateLimit.TrustProxyHeaders, "RATE_LIMIT_TRUST_PROXY"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func setString(dst *string, key string) {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		*dst = v
+	}
+}
+
+func setDuration(dst *time.Duration, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("env %s: %w", key, err)
+	}
+	*dst = d
+	return nil
+}
+
+func setInt(dst *int, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("env %s: %w", key, err)
+	}
+	*dst = n
+	return nil
+}
+
+func setBool(dst *bool, key string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("env %s: %w", key, err)
+	}
+	*dst = b
+	return nil
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+	if _, _, err := net.SplitHostPort(c.Server.Address); err != nil {
+		errs = append(errs, fmt.Errorf("server.address: %w", err))
+	}
+	if c.Server.ReadTimeout <= 0 {
+		errs = append(errs, errors.New("server.readTimeout must be positive"))
+	}
+	if c.Server.WriteTimeout <= 0 {
+		errs = append(errs, errors.New("server.writeTimeout must be positive"))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdownTimeout must be positive"))
+	}
+	if c.Server.MaxBodyBytes <= 0 {
+		errs = append(errs, errors.New("server.maxBodyBytes must be positive"))
+	}
+	if c.Postgres.Conn == "" {
+		errs = append(errs, errors.New("postgres.conn is required"))
+	} else if strings.Contains(c.Postgres.Conn, "://") {
+		u, err := url.Parse(c.Postgres.Conn)
+		if err != nil {
+			errs = append(errs, errors.New("postgres.conn is not a valid URL"))
+		} else if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+			errs = append(errs, fmt.Errorf("postgres.conn: unsupported scheme %q", u.Scheme))
+		}
+	}
+	if c.Postgres.ConnectTimeout <= 0 {
+		errs = append(errs, errors.New("postgres.connectTimeout must be positive"))
+	}
+	if c.Postgres.RetryInitialBackoff <= 0 || c.Postgres.RetryMaxBackoff < c.Postgres.RetryInitialBackoff {
+		errs = append(errs, errors.New("postgres retry backoff must be positive and retryMaxBackoff >= retryInitialBackoff"))
+	}
+	if c.Postgres.MaxOpenConns <= 0 {
+		errs = append(errs, errors.New("postgres.maxOpenConns must be positive"))
+	}
+	if c.Postgres.MaxIdleConns < 0 || c.Postgres.MaxIdleConns > c.Postgres.MaxOpenConns {
+		errs = append(errs, errors.New("postgres.maxIdleConns must be between 0 and postgres.maxOpenConns"))
+	}
+	if c.Postgres.ConnMaxLifetime < 0 {
+		errs = append(errs, errors.New("postgres.connMaxLifetime must not be negative"))
+	}
+	if c.Pagination.MaxLimit <= 0 {
+		errs = append(errs, errors.New("pagination.maxLimit must be positive"))
+	}
+	if c.Pagination.DefaultLimit <= 0 || c.Pagination.DefaultLimit > c.Pagination.MaxLimit {
+		errs = append(errs, errors.New("pagination.defaultLimit must be between 1 and pagination.maxLimit"))
+	}
+	if c.Migrations.Source == "" {
+		errs = append(errs, errors.New("migrations.source is required"))
+	}
+	if c.Outbox.PollInterval <= 0 || c.Outbox.BatchSize <= 0 {
+		errs = append(errs, errors.New("outbox.pollInterval and outbox.batchSize must be positive"))
+	}
+	if c.Webhooks.PollInterval <= 0 || c.Webhooks.BatchSize <= 0 || c.Webhooks.Timeout <= 0 || c.Webhooks.MaxAttempts <= 0 {
+		errs = append(errs, errors.New("webhooks.pollInterval, batchSize, timeout and maxAttempts must be positive"))
+	}
+	if c.Webhooks.InitialBackoff <= 0 || c.Webhooks.MaxBackoff < c.Webhooks.InitialBackoff {
+		errs = append(errs, errors.New("webhooks backoff must be positive and maxBackoff >= initialBackoff"))
+	}
+	if c.Stream.Heartbeat <= 0 || c.Stream.ReplayLimit <= 0 {
+		errs = append(errs, errors.New("stream.heartbeat and stream.replayLimit must be positive"))
+	}
+	if c.Scheduler.Interval <= 0 {
+		errs = append(errs, errors.New("scheduler.interval must be positive"))
+	}
+	if c.Sealing.MasterKey != "" {
+		key, err := base64.StdEncoding.DecodeString(c.Sealing.MasterKey)
+		if err != nil || len(key) != 32 {
+			errs = append(errs, errors.New("sealing.masterKey must be 32 bytes encoded as base64"))
+		}
+	}
+	if c.RateLimit.Store != "memory" && c.RateLimit.Store != "postgres" {
+		errs = append(errs, fmt.Errorf("rateLimit.store: unknown store %q", c.RateLimit.Store))
+	}
+	if err := c.RateLimit.Default.validate("default"); err != nil {
+		errs = append(errs, err)
+	}
+	for route, rule := range c.RateLimit.Routes {
+		if err := rule.validate(route); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Redacted returns a copy of the config with secrets masked.
+func (c *Config) Redacted() Config {
+	out := *c
+	out.Postgres.Conn = redactConn(c.Postgres.Conn)
+	if out.Sealing.MasterKey != "" {
+		out.Sealing.MasterKey = "xxxxx"
+	}
+	return out
+}
+
+func (c *Config) String() string {
+	redacted := c.Redacted()
+	data, err := yaml.Marshal(&redacted)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+func redactConn(conn string) string {
+	if conn == "" {
+		return ""
+	}
+	if strings.Contains(conn, "://") {
+		u, err := url.Parse(conn)
+		if err != nil {
+			return "xxxxx"
+		}
+		return u.Redacted()
+	}
+	fields := strings.Fields(conn)
+	for i, f := range fields {
+		if strings.HasPrefix(f, "password=") {
+			fields[i] = "password=xxxxx"
+		}
+	}
+	return strings.Join(fields, " ")
+}
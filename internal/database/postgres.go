@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"zadanie-6105/internal/config"
+)
+
+var ErrUnavailable = errors.New("database is unavailable")
+
+// Connect opens a connection pool and pings the database with exponential
+// backoff until it answers or cfg.ConnectTimeout expires.
+func Connect(ctx context.Context, cfg config.PostgresConfig, log *logrus.Logger) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.Conn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+
+	backoff := cfg.RetryInitialBackoff
+	for attempt := 1; ; attempt++ {
+		err = db.PingContext(ctx)
+		if err == nil {
+			log.Infof("connected to database after %d attempt(s)", attempt)
+			return db, nil
+		}
+		log.Warnf("database is not ready (attempt %d): %v, retrying in %s", attempt, err, backoff)
+
+		select {
+		case <-ctx.Done():
+			_ = db.Close()
+			return nil, fmt.Errorf("%w: gave up after %d attempt(s): %v", ErrUnavailable, attempt, err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > cfg.RetryMaxBackoff {
+			backoff = cfg.RetryMaxBackoff
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/golang-migrate/migrate/v4/source/github"
+)
+
+type Migrator struct {
+	m         *migrate.Migrate
+	sourceURL string
+}
+
+type Status struct {
+	Version   uint
+	Dirty     bool
+	Available []uint
+}
+
+func New(sourceURL, databaseURL string) (*Migrator, error) {
+	m, err := migrate.New(sourceURL, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("create migrate: %w", err)
+	}
+	return &Migrator{m: m, sourceURL: sourceURL}, nil
+}
+
+func (m *Migrator) Up() error {
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migrate up: %w", err)
+	}
+	return nil
+}
+
+// Down rolls back the given number of migrations, or all of them when steps <= 0.
+func (m *Migrator) Down(steps int) error {
+	var err error
+	if steps <= 0 {
+		err = m.m.Down()
+	} else {
+		err = m.m.Steps(-steps)
+	}
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migrate down: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) To(version uint) error {
+	if err := m.m.Migrate(version); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migrate to %d: %w", version, err)
+	}
+	return nil
+}
+
+func (m *Migrator) Force(version int) error {
+	if err := m.m.Force(version); err != nil {
+		return fmt.Errorf("force version %d: %w", version, err)
+	}
+	return nil
+}
+
+func (m *Migrator) Status() (*Status, error) {
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("read version: %w", err)
+	}
+	available, err := m.available()
+	if err != nil {
+		return nil, err
+	}
+	return &Status{Version: version, Dirty: dirty, Available: available}, nil
+}
+
+func (m *Migrator) available() ([]uint, error) {
+	src, err := source.Open(m.sourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("open source: %w", err)
+	}
+	defer src.Close()
+
+	var versions []uint
+	v, err := src.First()
+	for err == nil {
+		versions = append(versions, v)
+		v, err = src.Next(v)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+	return versions, nil
+}
+
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
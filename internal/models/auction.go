@@ -0,0 +1,100 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const (
+	DefaultAuctionExtensionWindow = 120
+	DefaultAuctionExtension       = 120
+)
+
+type AuctionStatus string
+
+const (
+	AuctionScheduled AuctionStatus = "Scheduled"
+	AuctionActive    AuctionStatus = "Active"
+	AuctionFinished  AuctionStatus = "Finished"
+)
+
+// AuctionRequest configures a reverse auction. Extension values are in seconds:
+// a price placed less than ExtensionWindow before the end moves the end to
+// Extension seconds after the placement. Omitted values take the defaults,
+// zero disables the extension.
+type AuctionRequest struct {
+	StartsAt        time.Time       `json:"startsAt"`
+	EndsAt          time.Time       `json:"endsAt"`
+	MinDecrement    decimal.Decimal `json:"minDecrement"`
+	ExtensionWindow *int            `json:"extensionWindowSeconds,omitempty"`
+	Extension       *int            `json:"extensionSeconds,omitempty"`
+}
+
+type Auction struct {
+	TenderId        uuid.UUID       `json:"tenderId"`
+	StartsAt        time.Time       `json:"startsAt"`
+	EndsAt          time.Time       `json:"endsAt"`
+	MinDecrement    decimal.Decimal `json:"minDecrement"`
+	ExtensionWindow int             `json:"extensionWindowSeconds"`
+	Extension       int             `json:"extensionSeconds"`
+}
+
+func (a *Auction) Status(now time.Time) AuctionStatus {
+	switch {
+	case now.Before(a.StartsAt):
+		return AuctionScheduled
+	case now.Before(a.EndsAt):
+		return AuctionActive
+	default:
+		return AuctionFinished
+	}
+}
+
+// ValidDecrement requires the new price to undercut the current one by at
+// least the minimum step; the first price of a bid only has to be positive.
+func (a *Auction) ValidDecrement(current *decimal.Decimal, price decimal.Decimal) bool {
+	if current == nil {
+		return true
+	}
+	return !price.GreaterThan(current.Sub(a.MinDecrement))
+}
+
+// Extend pushes the end of the auction when a price arrives in its final
+// window and reports whether it did.
+func (a *Auction) Extend(now time.Time) bool {
+	if a.EndsAt.Sub(now) >= time.Duration(a.ExtensionWindow)*time.Second {
+		return false
+	}
+	end := now.Add(time.Duration(a.Extension) * time.Second)
+	if !end.After(a.EndsAt) {
+		return false
+	}
+	a.EndsAt = end
+	return true
+}
+
+type AuctionPriceRequest struct {
+	Price decimal.Decimal `json:"price"`
+}
+
+// AuctionRank is a position in the auction; competitors stay anonymous and
+// only the caller's own bids are marked.
+type AuctionRank struct {
+	Rank     int             `json:"rank"`
+	Price    decimal.Decimal `json:"price"`
+	Currency string          `json:"currency,omitempty"`
+	BidId    *uuid.UUID      `json:"bidId,omitempty"`
+	Own      bool            `json:"own,omitempty"`
+}
+
+type AuctionState struct {
+	*Auction
+	Status AuctionStatus  `json:"status"`
+	Ranks  []*AuctionRank `json:"ranks"`
+}
+
+type AuctionPricePayload struct {
+	Bid    *BidResponse `json:"bid"`
+	EndsAt time.Time    `json:"endsAt"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type AwardStatus string
+
+const (
+	AwardStatusAwarded    AwardStatus = "Awarded"
+	AwardStatusNotAwarded AwardStatus = "NotAwarded"
+)
+
+// Award records the winning bid of a tender, or of one of its lots, at the
+// moment it was approved.
+type Award struct {
+	TenderId   uuid.UUID        `json:"tenderId"`
+	LotId      *uuid.UUID       `json:"lotId,omitempty"`
+	BidId      uuid.UUID        `json:"bidId"`
+	AuthorType TypeAuthor       `json:"authorType"`
+	AuthorId   uuid.UUID        `json:"authorId"`
+	Price      *decimal.Decimal `json:"price,omitempty"`
+	Currency   string           `json:"currency,omitempty"`
+	AwardedAt  time.Time        `json:"awardedAt"`
+	AwardedBy  string           `json:"awardedBy"`
+	RankingId  *int64           `json:"rankingId,omitempty"`
+}
+
+// TenderAward is the outcome of a tender: the award itself for a tender
+// without lots, or the awards of its lots, in lot order.
+type TenderAward struct {
+	*Award
+	Lots []*Award `json:"lots,omitempty"`
+}
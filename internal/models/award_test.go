@@ -0,0 +1,28 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/satori/uuid"
+)
+
+func TestTenderAwardJSON(t *testing.T) {
+	bidId := uuid.NewV4()
+	plain, err := json.Marshal(&TenderAward{Award: &Award{BidId: bidId}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A tender without lots keeps the flat award object.
+	if !strings.Contains(string(plain), `"bidId":"`+bidId.String()+`"`) || strings.Contains(string(plain), `"lots"`) {
+		t.Fatalf("plain award = %s", plain)
+	}
+	lots, err := json.Marshal(&TenderAward{Lots: []*Award{{BidId: bidId}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(lots), `{"lots":[{`) {
+		t.Fatalf("lot awards = %s", lots)
+	}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
 	"time"
 )
 
@@ -14,27 +15,69 @@ const (
 	User         TypeAuthor = "User"
 )
 
+const (
+	DecisionApproved TypeDecision = "Approved"
+	DecisionRejected TypeDecision = "Rejected"
+)
+
+type BidSort string
+
+const (
+	BidSortName      BidSort = "name"
+	BidSortPriceAsc  BidSort = "price"
+	BidSortPriceDesc BidSort = "-price"
+)
+
 type BidRequest struct {
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	TenderId    uuid.UUID  `json:"tenderId"`
-	AuthorType  TypeAuthor `json:"authorType"`
-	AuthorId    uuid.UUID  `json:"authorId"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	TenderId    uuid.UUID        `json:"tenderId"`
+	AuthorType  TypeAuthor       `json:"authorType"`
+	AuthorId    uuid.UUID        `json:"authorId"`
+	Price       *decimal.Decimal `json:"price,omitempty"`
+	Currency    string           `json:"currency,omitempty"`
+	ValidUntil  *time.Time       `json:"validUntil,omitempty"`
+	Items       []BidItemRequest `json:"items,omitempty"`
+	LotIds      []uuid.UUID      `json:"lotIds,omitempty"`
 }
 
 type BidResponse struct {
-	Id          uuid.UUID  `json:"id"`
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	Status      TypeStatus `json:"status"`
-	TenderId    uuid.UUID  `json:"tenderId"`
-	AuthorType  TypeAuthor `json:"authorType"`
-	AuthorId    uuid.UUID  `json:"authorId"`
-	Version     int        `json:"version"`
-	CreatedAt   time.Time  `json:"createdAt"`
+	Id          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Status      TypeStatus       `json:"status"`
+	TenderId    uuid.UUID        `json:"tenderId"`
+	AuthorType  TypeAuthor       `json:"authorType"`
+	AuthorId    uuid.UUID        `json:"authorId"`
+	Version     int              `json:"version"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	Price       *decimal.Decimal `json:"price,omitempty"`
+	Currency    string           `json:"currency,omitempty"`
+	ValidUntil  *time.Time       `json:"validUntil,omitempty"`
+	Items       []*BidItem       `json:"items,omitempty"`
+	LotIds      []uuid.UUID      `json:"lotIds,omitempty"`
+
+	Sealed            bool            `json:"sealed,omitempty"`
+	TechnicalResult   TechnicalResult `json:"technicalResult,omitempty"`
+	TechnicalComment  string          `json:"technicalComment,omitempty"`
+	Decision          TypeDecision    `json:"decision,omitempty"`
+	DecisionRankingId *int64          `json:"decisionRankingId,omitempty"`
+	DecisionRank      *int            `json:"decisionRank,omitempty"`
+	WithdrawnAt       *time.Time      `json:"withdrawnAt,omitempty"`
+	WithdrawalReason  string          `json:"withdrawalReason,omitempty"`
+	CanceledBy        CanceledBy      `json:"canceledBy,omitempty"`
+	AwardStatus       AwardStatus     `json:"awardStatus,omitempty"`
+}
+
+type WithdrawRequest struct {
+	Reason string `json:"reason,omitempty"`
 }
 
 type BidEditRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Price       *decimal.Decimal `json:"price,omitempty"`
+	Currency    string           `json:"currency,omitempty"`
+	ValidUntil  *time.Time       `json:"validUntil,omitempty"`
+	Items       []BidItemRequest `json:"items,omitempty"`
 }
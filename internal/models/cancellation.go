@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"time"
+)
+
+type CancelRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CanceledBy tells who closed a cancelled bid: its author by withdrawing it,
+// or the organiser by cancelling the whole tender.
+type CanceledBy string
+
+const (
+	CanceledByAuthor CanceledBy = "Author"
+	CanceledByTender CanceledBy = "Tender"
+)
+
+// BidCancellation notifies a bidder that their bid was closed together with
+// the cancelled tender.
+type BidCancellation struct {
+	BidId      uuid.UUID `json:"bidId"`
+	TenderId   uuid.UUID `json:"tenderId"`
+	Reason     string    `json:"reason"`
+	CanceledAt time.Time `json:"canceledAt"`
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type ContractStatus string
+
+const (
+	ContractActive    ContractStatus = "Active"
+	ContractCompleted ContractStatus = "Completed"
+)
+
+type MilestoneStatus string
+
+const (
+	MilestonePending    MilestoneStatus = "Pending"
+	MilestoneInProgress MilestoneStatus = "InProgress"
+	MilestoneDelivered  MilestoneStatus = "Delivered"
+	MilestoneAccepted   MilestoneStatus = "Accepted"
+	MilestoneRejected   MilestoneStatus = "Rejected"
+)
+
+// Reportable reports whether the supplier may still post progress on the
+// milestone: delivered milestones wait for the buyer's review.
+func (s MilestoneStatus) Reportable() bool {
+	return s == MilestonePending || s == MilestoneInProgress || s == MilestoneRejected
+}
+
+type MilestoneRequest struct {
+	Description string           `json:"description"`
+	DueDate     time.Time        `json:"dueDate"`
+	Amount      *decimal.Decimal `json:"amount,omitempty"`
+}
+
+type ContractRequest struct {
+	Milestones []*MilestoneRequest `json:"milestones"`
+}
+
+// ProgressRequest is a supplier's report on a milestone; progress 100 hands
+// the milestone over for review.
+type ProgressRequest struct {
+	Progress int    `json:"progress"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type ReviewRequest struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+// Contract tracks the execution of the awarded bid of a tender.
+type Contract struct {
+	Id             uuid.UUID        `json:"id"`
+	TenderId       uuid.UUID        `json:"tenderId"`
+	LotId          *uuid.UUID       `json:"lotId,omitempty"`
+	BidId          uuid.UUID        `json:"bidId"`
+	OrganizationId uuid.UUID        `json:"organizationId"`
+	SupplierType   TypeAuthor       `json:"supplierType"`
+	SupplierId     uuid.UUID        `json:"supplierId"`
+	Price          *decimal.Decimal `json:"price,omitempty"`
+	Currency       string           `json:"currency,omitempty"`
+	Status         ContractStatus   `json:"status"`
+	CreatedBy      string           `json:"createdBy"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	CompletedAt    *time.Time       `json:"completedAt,omitempty"`
+	Milestones     []*Milestone     `json:"milestones"`
+}
+
+type Milestone struct {
+	Id            uuid.UUID          `json:"id"`
+	ContractId    uuid.UUID          `json:"contractId"`
+	Position      int                `json:"position"`
+	Description   string             `json:"description"`
+	DueDate       time.Time          `json:"dueDate"`
+	Amount        *decimal.Decimal   `json:"amount,omitempty"`
+	Status        MilestoneStatus    `json:"status"`
+	Progress      int                `json:"progress"`
+	OverdueAt     *time.Time         `json:"overdueAt,omitempty"`
+	ReviewedBy    string             `json:"reviewedBy,omitempty"`
+	ReviewedAt    *time.Time         `json:"reviewedAt,omitempty"`
+	ReviewComment string             `json:"reviewComment,omitempty"`
+	UpdatedAt     time.Time          `json:"updatedAt"`
+	Updates       []*MilestoneUpdate `json:"updates,omitempty"`
+}
+
+type MilestoneUpdate struct {
+	Id        int64     `json:"id"`
+	Progress  int       `json:"progress"`
+	Comment   string    `json:"comment,omitempty"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// MilestoneEvent is the payload of milestone events; it names the contract so
+// subscribers do not have to look it up.
+type MilestoneEvent struct {
+	ContractId uuid.UUID  `json:"contractId"`
+	Milestone  *Milestone `json:"milestone"`
+}
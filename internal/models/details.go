@@ -0,0 +1,54 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"time"
+)
+
+type OrganizationInfo struct {
+	Id          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Type        string    `json:"type,omitempty"`
+}
+
+// VersionInfo describes the latest version of a tender or a bid.
+type VersionInfo struct {
+	Version   int        `json:"version"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// BidCounts counts submitted bids of a tender; drafts and withdrawn or
+// cancelled bids are not included.
+type BidCounts struct {
+	Submitted int `json:"submitted"`
+	Approved  int `json:"approved"`
+	Rejected  int `json:"rejected"`
+}
+
+type TenderDetails struct {
+	*TendersResponse
+	Organization  *OrganizationInfo `json:"organization"`
+	Bids          *BidCounts        `json:"bids"`
+	LatestVersion *VersionInfo      `json:"latestVersion"`
+}
+
+type BidAuthor struct {
+	Type TypeAuthor `json:"type"`
+	Id   uuid.UUID  `json:"id"`
+	Name string     `json:"name,omitempty"`
+}
+
+type BidTender struct {
+	Id           uuid.UUID         `json:"id"`
+	Name         string            `json:"name"`
+	Status       TypeStatus        `json:"status"`
+	Organization *OrganizationInfo `json:"organization"`
+}
+
+type BidDetails struct {
+	*BidResponse
+	Author        *BidAuthor   `json:"author"`
+	Tender        *BidTender   `json:"tender"`
+	LatestVersion *VersionInfo `json:"latestVersion"`
+}
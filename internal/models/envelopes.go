@@ -0,0 +1,130 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type EvaluationPhase string
+
+const (
+	PhaseTechnicalReview  EvaluationPhase = "TechnicalReview"
+	PhaseFinancialOpening EvaluationPhase = "FinancialOpening"
+	PhaseAward            EvaluationPhase = "Award"
+)
+
+// Next returns the phase that follows p, or an empty phase after Award.
+func (p EvaluationPhase) Next() EvaluationPhase {
+	switch p {
+	case PhaseTechnicalReview:
+		return PhaseFinancialOpening
+	case PhaseFinancialOpening:
+		return PhaseAward
+	default:
+		return ""
+	}
+}
+
+// PricesVisible reports whether financial envelopes may be read in phase p.
+func (p EvaluationPhase) PricesVisible() bool {
+	return p == PhaseFinancialOpening || p == PhaseAward
+}
+
+type TechnicalResult string
+
+const (
+	TechnicalQualified    TechnicalResult = "Qualified"
+	TechnicalDisqualified TechnicalResult = "Disqualified"
+)
+
+func (r TechnicalResult) IsValid() bool {
+	return r == TechnicalQualified || r == TechnicalDisqualified
+}
+
+// TwoEnvelope reports whether bids for the service type are evaluated in two
+// stages: the technical envelope first, prices only for qualified bidders.
+func TwoEnvelope(serviceType TypeService) bool {
+	return serviceType == ServiceTypeConstruction
+}
+
+type TechnicalReviewRequest struct {
+	Result  TechnicalResult `json:"result"`
+	Comment string          `json:"comment,omitempty"`
+}
+
+type TechnicalEnvelope struct {
+	BidId            uuid.UUID       `json:"bidId"`
+	Name             string          `json:"name"`
+	Description      string          `json:"description"`
+	AuthorType       TypeAuthor      `json:"authorType"`
+	AuthorId         uuid.UUID       `json:"authorId"`
+	Version          int             `json:"version"`
+	TechnicalResult  TechnicalResult `json:"technicalResult,omitempty"`
+	TechnicalComment string          `json:"technicalComment,omitempty"`
+}
+
+type FinancialEnvelope struct {
+	BidId      uuid.UUID        `json:"bidId"`
+	Name       string           `json:"name"`
+	Price      *decimal.Decimal `json:"price,omitempty"`
+	Currency   string           `json:"currency,omitempty"`
+	ValidUntil *time.Time       `json:"validUntil,omitempty"`
+	Items      []*BidItem       `json:"items,omitempty"`
+}
+
+type PhaseChange struct {
+	TenderId uuid.UUID       `json:"tenderId"`
+	From     EvaluationPhase `json:"from"`
+	To       EvaluationPhase `json:"to"`
+}
+
+func (b *BidResponse) TechnicalEnvelope() *TechnicalEnvelope {
+	return &TechnicalEnvelope{
+		BidId:            b.Id,
+		Name:             b.Name,
+		Description:      b.Description,
+		AuthorType:       b.AuthorType,
+		AuthorId:         b.AuthorId,
+		Version:          b.Version,
+		TechnicalResult:  b.TechnicalResult,
+		TechnicalComment: b.TechnicalComment,
+	}
+}
+
+func (b *BidResponse) FinancialEnvelope() *FinancialEnvelope {
+	return &FinancialEnvelope{
+		BidId:      b.Id,
+		Name:       b.Name,
+		Price:      b.Price,
+		Currency:   b.Currency,
+		ValidUntil: b.ValidUntil,
+		Items:      b.Items,
+	}
+}
+
+// HideFinancial clears the financial envelope of the bid.
+func (b *BidResponse) HideFinancial() {
+	b.Price, b.Currency, b.ValidUntil, b.Items = nil, "", nil, nil
+}
+
+// WithoutFinancial returns a copy of a bid event payload with the financial
+// envelope cleared; payloads that carry no bid are returned as is.
+func WithoutFinancial(payload interface{}) interface{} {
+	switch p := payload.(type) {
+	case *BidResponse:
+		hidden := *p
+		hidden.HideFinancial()
+		return &hidden
+	case *AuctionPricePayload:
+		hidden := *p
+		hidden.Bid = WithoutFinancial(p.Bid).(*BidResponse)
+		return &hidden
+	case *BidDecisionPayload:
+		hidden := *p
+		hidden.Bid = WithoutFinancial(p.Bid).(*BidResponse)
+		return &hidden
+	default:
+		return payload
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestWithoutFinancial(t *testing.T) {
+	price := decimal.NewFromInt(100)
+	bid := &BidResponse{Name: "bid", Price: &price, Currency: "RUB", Items: []*BidItem{{Name: "cement"}}}
+
+	hidden := WithoutFinancial(bid).(*BidResponse)
+	if hidden.Price != nil || hidden.Currency != "" || hidden.Items != nil || hidden.Name != "bid" {
+		t.Fatalf("hidden bid = %+v", hidden)
+	}
+	auction := WithoutFinancial(&AuctionPricePayload{Bid: bid}).(*AuctionPricePayload)
+	if auction.Bid.Price != nil {
+		t.Fatal("auction payload keeps the price")
+	}
+	decision := WithoutFinancial(&BidDecisionPayload{Bid: bid, Decision: DecisionApproved}).(*BidDecisionPayload)
+	if decision.Bid.Price != nil || decision.Decision != DecisionApproved {
+		t.Fatalf("decision payload = %+v", decision)
+	}
+	if bid.Price == nil || bid.Currency != "RUB" || bid.Items == nil {
+		t.Fatal("the original bid was changed")
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// Criterion weights are percentages and must add up to TotalWeight;
+// scores are given on a 0..MaxScore scale.
+var (
+	TotalWeight = decimal.NewFromInt(100)
+	MaxScore    = decimal.NewFromInt(100)
+)
+
+type CriterionRequest struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Weight      decimal.Decimal `json:"weight"`
+}
+
+type Criterion struct {
+	Id          uuid.UUID       `json:"id"`
+	Position    int             `json:"position"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Weight      decimal.Decimal `json:"weight"`
+}
+
+type ScoreRequest struct {
+	CriterionId uuid.UUID       `json:"criterionId"`
+	Score       decimal.Decimal `json:"score"`
+	Comment     string          `json:"comment"`
+}
+
+type BidScore struct {
+	CriterionId uuid.UUID       `json:"criterionId"`
+	Evaluator   string          `json:"evaluator"`
+	Score       decimal.Decimal `json:"score"`
+	Comment     string          `json:"comment"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
+
+// BidEvaluation holds the average score per criterion across evaluators.
+type BidEvaluation struct {
+	BidId     uuid.UUID
+	Name      string
+	Price     *decimal.Decimal
+	CreatedAt time.Time
+	Scores    map[uuid.UUID]decimal.Decimal
+}
+
+type RankedBid struct {
+	Rank           int              `json:"rank"`
+	BidId          uuid.UUID        `json:"bidId"`
+	Name           string           `json:"name"`
+	Price          *decimal.Decimal `json:"price,omitempty"`
+	WeightedTotal  decimal.Decimal  `json:"weightedTotal"`
+	CriteriaScored int              `json:"criteriaScored"`
+	Complete       bool             `json:"complete"`
+}
+
+type Ranking struct {
+	Id         int64        `json:"id,omitempty"`
+	TenderId   uuid.UUID    `json:"tenderId"`
+	Criteria   []*Criterion `json:"criteria"`
+	Bids       []*RankedBid `json:"bids"`
+	ComputedAt time.Time    `json:"computedAt"`
+}
+
+func (r *Ranking) Position(bidId uuid.UUID) *RankedBid {
+	for _, bid := range r.Bids {
+		if bid.BidId == bidId {
+			return bid
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/satori/uuid"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventTenderCreated        EventType = "TenderCreated"
+	EventTenderPublished      EventType = "TenderPublished"
+	EventTenderClosed         EventType = "TenderClosed"
+	EventTenderStatusChanged  EventType = "TenderStatusChanged"
+	EventTenderEdited         EventType = "TenderEdited"
+	EventTenderCanceled       EventType = "TenderCanceled"
+	EventTenderAwarded        EventType = "TenderAwarded"
+	EventPublicationScheduled EventType = "TenderPublicationScheduled"
+	EventPublicationCancelled EventType = "TenderPublicationCancelled"
+	EventPublicationFailed    EventType = "TenderPublicationFailed"
+	EventTenderBidsOpened     EventType = "TenderBidsOpened"
+	EventTenderPhaseChanged   EventType = "TenderEvaluationPhaseChanged"
+	EventLotAwarded           EventType = "LotAwarded"
+	EventLotCancelled         EventType = "LotCancelled"
+	EventLotBidRejected       EventType = "LotBidRejected"
+	EventQuestionAsked        EventType = "TenderQuestionAsked"
+	EventQuestionAnswered     EventType = "TenderQuestionAnswered"
+	EventInvitationSent       EventType = "TenderInvitationSent"
+	EventInvitationAccepted   EventType = "TenderInvitationAccepted"
+	EventInvitationDeclined   EventType = "TenderInvitationDeclined"
+	EventBidSubmitted         EventType = "BidSubmitted"
+	EventBidStatusChanged     EventType = "BidStatusChanged"
+	EventBidEdited            EventType = "BidEdited"
+	EventBidDecisionMade      EventType = "BidDecisionMade"
+	EventBidTechnicalReviewed EventType = "BidTechnicalReviewed"
+	EventBidWithdrawn         EventType = "BidWithdrawn"
+	EventBidCanceled          EventType = "BidCanceled"
+	EventAuctionPricePlaced   EventType = "AuctionPricePlaced"
+	EventContractCreated      EventType = "ContractCreated"
+	EventContractCompleted    EventType = "ContractCompleted"
+	EventMilestoneAdded       EventType = "MilestoneAdded"
+	EventMilestoneProgress    EventType = "MilestoneProgressUpdated"
+	EventMilestoneAccepted    EventType = "MilestoneAccepted"
+	EventMilestoneRejected    EventType = "MilestoneRejected"
+	EventMilestoneOverdue     EventType = "MilestoneOverdue"
+	EventApplicationSubmitted EventType = "PrequalificationSubmitted"
+	EventApplicationApproved  EventType = "PrequalificationApproved"
+	EventApplicationRejected  EventType = "PrequalificationRejected"
+)
+
+var EventTypes = []EventType{
+	EventTenderCreated,
+	EventTenderPublished,
+	EventTenderClosed,
+	EventTenderStatusChanged,
+	EventTenderEdited,
+	EventTenderCanceled,
+	EventTenderAwarded,
+	EventPublicationScheduled,
+	EventPublicationCancelled,
+	EventPublicationFailed,
+	EventTenderBidsOpened,
+	EventTenderPhaseChanged,
+	EventLotAwarded,
+	EventLotCancelled,
+	EventLotBidRejected,
+	EventQuestionAsked,
+	EventQuestionAnswered,
+	EventInvitationSent,
+	EventInvitationAccepted,
+	EventInvitationDeclined,
+	EventBidSubmitted,
+	EventBidStatusChanged,
+	EventBidEdited,
+	EventBidDecisionMade,
+	EventBidTechnicalReviewed,
+	EventBidWithdrawn,
+	EventBidCanceled,
+	EventAuctionPricePlaced,
+	EventContractCreated,
+	EventContractCompleted,
+	EventMilestoneAdded,
+	EventMilestoneProgress,
+	EventMilestoneAccepted,
+	EventMilestoneRejected,
+	EventMilestoneOverdue,
+	EventApplicationSubmitted,
+	EventApplicationApproved,
+	EventApplicationRejected,
+}
+
+func (t EventType) IsValid() bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	AggregateTender      = "tender"
+	AggregateBid         = "bid"
+	AggregateQuestion    = "question"
+	AggregateInvitation  = "invitation"
+	AggregateContract    = "contract"
+	AggregateMilestone   = "milestone"
+	AggregateApplication = "prequalification"
+)
+
+type Event struct {
+	Id             int64     `json:"id"`
+	Type           EventType `json:"type"`
+	AggregateType  string    `json:"aggregateType"`
+	AggregateId    uuid.UUID `json:"aggregateId"`
+	TenderId       uuid.UUID `json:"tenderId"`
+	OrganizationId uuid.UUID `json:"organizationId"`
+	// RecipientId addresses the event to one employee besides the members of
+	// the organization; uuid.Nil organization means the employee alone.
+	RecipientId *uuid.UUID      `json:"recipientId,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+type BidDecisionPayload struct {
+	Bid      *BidResponse `json:"bid"`
+	Decision TypeDecision `json:"decision"`
+	Ranking  *Ranking     `json:"ranking,omitempty"`
+}
+
+func NewTenderEvent(eventType EventType, tender *TendersResponse) *Event {
+	return newEvent(eventType, AggregateTender, tender.Id, tender.Id, tender.OrganizationId, tender)
+}
+
+func NewBidEvent(eventType EventType, bid *BidResponse, organizationId uuid.UUID, payload interface{}) *Event {
+	return newEvent(eventType, AggregateBid, bid.Id, bid.TenderId, organizationId, payload)
+}
+
+func NewOpeningEvent(protocol *OpeningProtocol, organizationId uuid.UUID) *Event {
+	return newEvent(EventTenderBidsOpened, AggregateTender, protocol.TenderId, protocol.TenderId, organizationId, protocol)
+}
+
+func NewLotEvent(eventType EventType, lot *Lot, organizationId uuid.UUID) *Event {
+	return newEvent(eventType, AggregateTender, lot.TenderId, lot.TenderId, organizationId, lot)
+}
+
+func NewLotRejectionEvent(rejection *LotRejection, organizationId uuid.UUID) *Event {
+	return newEvent(EventLotBidRejected, AggregateBid, rejection.BidId, rejection.TenderId, organizationId, rejection)
+}
+
+func NewQuestionEvent(eventType EventType, question *Question, organizationId uuid.UUID) *Event {
+	return newEvent(eventType, AggregateQuestion, question.Id, question.TenderId, organizationId, question)
+}
+
+func NewInvitationEvent(eventType EventType, invitation *Invitation, organizationId uuid.UUID) *Event {
+	return newEvent(eventType, AggregateInvitation, invitation.Id, invitation.TenderId, organizationId, invitation)
+}
+
+// NewBidCancellationEvent is addressed to the bidder's organization rather
+// than to the tender's.
+// NewBidCancellationEvent addresses the cancellation to the bid author and to
+// their organization, if they are responsible for one.
+func NewBidCancellationEvent(cancellation *BidCancellation, bidderOrganizationId, authorId uuid.UUID) *Event {
+	event := newEvent(EventBidCanceled, AggregateBid, cancellation.BidId, cancellation.TenderId, bidderOrganizationId, cancellation)
+	event.RecipientId = &authorId
+	return event
+}
+
+func NewAwardEvent(award *Award, organizationId uuid.UUID) *Event {
+	return newEvent(EventTenderAwarded, AggregateTender, award.TenderId, award.TenderId, organizationId, award)
+}
+
+func NewContractEvent(eventType EventType, contract *Contract) *Event {
+	return newEvent(eventType, AggregateContract, contract.Id, contract.TenderId, contract.OrganizationId, contract)
+}
+
+func NewMilestoneEvent(eventType EventType, milestone *Milestone, tenderId, organizationId uuid.UUID) *Event {
+	return newEvent(eventType, AggregateMilestone, milestone.Id, tenderId, organizationId,
+		&MilestoneEvent{ContractId: milestone.ContractId, Milestone: milestone})
+}
+
+func NewApplicationEvent(eventType EventType, application *Application, organizationId uuid.UUID) *Event {
+	return newEvent(eventType, AggregateApplication, application.Id, application.TenderId, organizationId, application)
+}
+
+func NewPhaseEvent(change *PhaseChange, organizationId uuid.UUID) *Event {
+	return newEvent(EventTenderPhaseChanged, AggregateTender, change.TenderId, change.TenderId, organizationId, change)
+}
+
+func TenderStatusEvent(status TypeStatus) EventType {
+	switch status {
+	case StatusPublished:
+		return EventTenderPublished
+	case StatusClosed:
+		return EventTenderClosed
+	case StatusCanceled:
+		return EventTenderCanceled
+	default:
+		return EventTenderStatusChanged
+	}
+}
+
+func newEvent(eventType EventType, aggregateType string, aggregateId, tenderId, organizationId uuid.UUID, payload interface{}) *Event {
+	data, _ := json.Marshal(payload)
+	return &Event{
+		Type:           eventType,
+		AggregateType:  aggregateType,
+		AggregateId:    aggregateId,
+		TenderId:       tenderId,
+		OrganizationId: organizationId,
+		Payload:        data,
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"time"
+)
+
+type TypeVisibility string
+
+const (
+	VisibilityPublic     TypeVisibility = "Public"
+	VisibilityInviteOnly TypeVisibility = "InviteOnly"
+)
+
+func (v TypeVisibility) IsValid() bool {
+	return v == VisibilityPublic || v == VisibilityInviteOnly
+}
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "Pending"
+	InvitationAccepted InvitationStatus = "Accepted"
+	InvitationDeclined InvitationStatus = "Declined"
+)
+
+// InvitationRequest invites either a single employee or a whole organization.
+type InvitationRequest struct {
+	Username       string     `json:"username,omitempty"`
+	OrganizationId *uuid.UUID `json:"organizationId,omitempty"`
+}
+
+func (r *InvitationRequest) IsValid() bool {
+	return (r.Username == "") != (r.OrganizationId == nil)
+}
+
+type Invitation struct {
+	Id             uuid.UUID        `json:"id"`
+	TenderId       uuid.UUID        `json:"tenderId"`
+	Username       string           `json:"username,omitempty"`
+	OrganizationId *uuid.UUID       `json:"organizationId,omitempty"`
+	Status         InvitationStatus `json:"status"`
+	InvitedBy      string           `json:"invitedBy"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	RespondedBy    string           `json:"respondedBy,omitempty"`
+	RespondedAt    *time.Time       `json:"respondedAt,omitempty"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type TenderItemRequest struct {
+	Name      string          `json:"name"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	Unit      string          `json:"unit"`
+	Mandatory bool            `json:"mandatory"`
+}
+
+type TenderItem struct {
+	Id        uuid.UUID       `json:"id"`
+	Position  int             `json:"position"`
+	Name      string          `json:"name"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	Unit      string          `json:"unit"`
+	Mandatory bool            `json:"mandatory"`
+}
+
+type BidItemRequest struct {
+	ItemId    uuid.UUID       `json:"itemId"`
+	UnitPrice decimal.Decimal `json:"unitPrice"`
+}
+
+type BidItem struct {
+	ItemId    uuid.UUID       `json:"itemId"`
+	Name      string          `json:"name"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	Unit      string          `json:"unit"`
+	UnitPrice decimal.Decimal `json:"unitPrice"`
+	Total     decimal.Decimal `json:"total"`
+}
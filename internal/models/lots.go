@@ -0,0 +1,44 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"time"
+)
+
+type LotStatus string
+
+const (
+	LotOpen      LotStatus = "Open"
+	LotAwarded   LotStatus = "Awarded"
+	LotCancelled LotStatus = "Cancelled"
+)
+
+type LotRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type Lot struct {
+	Id           uuid.UUID  `json:"id"`
+	TenderId     uuid.UUID  `json:"tenderId"`
+	Position     int        `json:"position"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description,omitempty"`
+	Status       LotStatus  `json:"status"`
+	AwardedBidId *uuid.UUID `json:"awardedBidId,omitempty"`
+	ClosedAt     *time.Time `json:"closedAt,omitempty"`
+}
+
+// BidLot is a lot targeted by a bid together with the decision on it.
+type BidLot struct {
+	LotId    uuid.UUID    `json:"lotId"`
+	Name     string       `json:"name"`
+	Decision TypeDecision `json:"decision,omitempty"`
+}
+
+// LotRejection is the payload of a rejected lot decision.
+type LotRejection struct {
+	TenderId uuid.UUID `json:"tenderId"`
+	LotId    uuid.UUID `json:"lotId"`
+	BidId    uuid.UUID `json:"bidId"`
+}
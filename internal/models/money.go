@@ -0,0 +1,25 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// MoneyScale is the number of fractional digits stored for amounts (NUMERIC(19,4)).
+const MoneyScale = 4
+
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func ValidAmount(amount *decimal.Decimal) bool {
+	if amount == nil {
+		return true
+	}
+	return amount.IsPositive() && amount.Exponent() >= -MoneyScale
+}
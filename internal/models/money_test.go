@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestValidCurrency(t *testing.T) {
+	for code, ok := range map[string]bool{"RUB": true, "USD": true, "rub": false, "RU": false, "RUBL": false, "R1B": false, "": false} {
+		if ValidCurrency(code) != ok {
+			t.Errorf("ValidCurrency(%q) = %t", code, !ok)
+		}
+	}
+}
+
+func TestValidAmount(t *testing.T) {
+	if !ValidAmount(nil) {
+		t.Error("nil amount rejected")
+	}
+	for amount, ok := range map[string]bool{"1": true, "0.01": true, "0": false, "-5": false, "0.0001": true, "0.00001": false} {
+		d := decimal.RequireFromString(amount)
+		if ValidAmount(&d) != ok {
+			t.Errorf("ValidAmount(%s) = %t", amount, !ok)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"time"
+)
+
+type RequirementKind string
+
+const (
+	RequirementLicence    RequirementKind = "Licence"
+	RequirementExperience RequirementKind = "Experience"
+)
+
+func (k RequirementKind) IsValid() bool {
+	return k == RequirementLicence || k == RequirementExperience
+}
+
+// RequirementRequest describes a qualification a supplier must hold; MinYears
+// only applies to experience.
+type RequirementRequest struct {
+	Kind        RequirementKind `json:"kind"`
+	Description string          `json:"description"`
+	MinYears    int             `json:"minYears,omitempty"`
+}
+
+type Requirement struct {
+	Id          uuid.UUID       `json:"id"`
+	TenderId    uuid.UUID       `json:"tenderId"`
+	Position    int             `json:"position"`
+	Kind        RequirementKind `json:"kind"`
+	Description string          `json:"description"`
+	MinYears    int             `json:"minYears,omitempty"`
+}
+
+type ApplicationStatus string
+
+const (
+	ApplicationPending  ApplicationStatus = "Pending"
+	ApplicationApproved ApplicationStatus = "Approved"
+	ApplicationRejected ApplicationStatus = "Rejected"
+)
+
+// Evidence backs one requirement: a licence number or document, or the years
+// of relevant experience.
+type Evidence struct {
+	RequirementId uuid.UUID `json:"requirementId"`
+	Description   string    `json:"description"`
+	DocumentUrl   string    `json:"documentUrl,omitempty"`
+	Years         int       `json:"years,omitempty"`
+}
+
+// ApplicationRequest applies for prequalification on behalf of the employee or,
+// with OrganizationId, of an organization they are responsible for.
+type ApplicationRequest struct {
+	OrganizationId *uuid.UUID  `json:"organizationId,omitempty"`
+	Evidence       []*Evidence `json:"evidence"`
+}
+
+type Application struct {
+	Id            uuid.UUID         `json:"id"`
+	TenderId      uuid.UUID         `json:"tenderId"`
+	AuthorType    TypeAuthor        `json:"authorType"`
+	AuthorId      uuid.UUID         `json:"authorId"`
+	Status        ApplicationStatus `json:"status"`
+	Evidence      []*Evidence       `json:"evidence"`
+	SubmittedBy   string            `json:"submittedBy"`
+	SubmittedAt   time.Time         `json:"submittedAt"`
+	ReviewedBy    string            `json:"reviewedBy,omitempty"`
+	ReviewedAt    *time.Time        `json:"reviewedAt,omitempty"`
+	ReviewComment string            `json:"reviewComment,omitempty"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"time"
+)
+
+type QuestionRequest struct {
+	Text string `json:"text"`
+}
+
+// AnswerRequest answers a question; with Anonymous the author of the question
+// is hidden from everyone outside the tender's organization.
+type AnswerRequest struct {
+	Text      string `json:"text"`
+	Anonymous bool   `json:"anonymous,omitempty"`
+}
+
+type Question struct {
+	Id         uuid.UUID  `json:"id"`
+	TenderId   uuid.UUID  `json:"tenderId"`
+	Text       string     `json:"text"`
+	Author     string     `json:"author,omitempty"`
+	Anonymous  bool       `json:"anonymous,omitempty"`
+	AskedAt    time.Time  `json:"askedAt"`
+	Answer     string     `json:"answer,omitempty"`
+	AnsweredBy string     `json:"answeredBy,omitempty"`
+	AnsweredAt *time.Time `json:"answeredAt,omitempty"`
+}
+
+func (q *Question) Answered() bool {
+	return q.AnsweredAt != nil
+}
+
+// Published is the question as every bidder sees it: an anonymised answer
+// hides who asked.
+func (q *Question) Published() *Question {
+	published := *q
+	if published.Anonymous {
+		published.Author = ""
+	}
+	return &published
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// SealedEnvelope is the part of a bid that stays encrypted until opening.
+type SealedEnvelope struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Price       *decimal.Decimal `json:"price,omitempty"`
+	Items       []*BidItem       `json:"items,omitempty"`
+}
+
+type OpeningEntry struct {
+	BidId       uuid.UUID        `json:"bidId"`
+	AuthorType  TypeAuthor       `json:"authorType"`
+	AuthorId    uuid.UUID        `json:"authorId"`
+	Name        string           `json:"name"`
+	Price       *decimal.Decimal `json:"price,omitempty"`
+	Currency    string           `json:"currency,omitempty"`
+	Status      TypeStatus       `json:"status"`
+	SubmittedAt time.Time        `json:"submittedAt"`
+	// Unreadable marks an envelope that failed to decrypt; the bid stays sealed.
+	Unreadable bool `json:"unreadable,omitempty"`
+}
+
+type OpeningProtocol struct {
+	TenderId uuid.UUID       `json:"tenderId"`
+	OpenedAt time.Time       `json:"openedAt"`
+	Bids     []*OpeningEntry `json:"bids"`
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
 	"time"
 )
 
@@ -13,35 +14,104 @@ const (
 	ServiceTypeManufacture  TypeService = "Manufacture"
 )
 
+const DefaultCurrency = "RUB"
+
 type TypeStatus string
 
 const (
 	StatusCreated   TypeStatus = "Created"
 	StatusPublished TypeStatus = "Published"
 	StatusClosed    TypeStatus = "Closed"
+	StatusCanceled  TypeStatus = "Canceled"
 )
 
+// Awardable reports whether a winner may be chosen in a tender with this
+// status. The deadline job closes a published tender once submission ends, and
+// the winner is usually chosen after that.
+func (s TypeStatus) Awardable() bool {
+	return s == StatusPublished || s == StatusClosed
+}
+
 type TendersRequest struct {
-	Name            string      `json:"name"`
-	Description     string      `json:"description"`
-	ServiceType     TypeService `json:"serviceType"`
-	OrganizationId  uuid.UUID   `json:"organizationId"`
-	CreatorUsername string      `json:"creatorUsername"`
+	Name                  string              `json:"name"`
+	Description           string              `json:"description"`
+	ServiceType           TypeService         `json:"serviceType"`
+	OrganizationId        uuid.UUID           `json:"organizationId"`
+	CreatorUsername       string              `json:"creatorUsername"`
+	SubmissionDeadline    *time.Time          `json:"submissionDeadline,omitempty"`
+	ClarificationDeadline *time.Time          `json:"clarificationDeadline,omitempty"`
+	Visibility            TypeVisibility      `json:"visibility,omitempty"`
+	Publication           *PublicationRequest `json:"publication,omitempty"`
+	EstimatedBudget       *decimal.Decimal    `json:"estimatedBudget,omitempty"`
+	MaxPrice              *decimal.Decimal    `json:"maxPrice,omitempty"`
+	Currency              string              `json:"currency,omitempty"`
+	Items                 []TenderItemRequest `json:"items,omitempty"`
+	Lots                  []LotRequest        `json:"lots,omitempty"`
+	Sealed                bool                `json:"sealed,omitempty"`
+	OpeningAt             *time.Time          `json:"openingAt,omitempty"`
 }
 
 type TendersResponse struct {
-	Id             uuid.UUID   `json:"id"`
-	Name           string      `json:"name"`
-	Description    string      `json:"description"`
-	Status         TypeStatus  `json:"status"`
-	ServiceType    TypeService `json:"serviceType"`
-	Version        int         `json:"version"`
-	CreatedAt      time.Time   `json:"createdAt"`
-	OrganizationId uuid.UUID   `json:"organizationId"`
+	Id                    uuid.UUID        `json:"id"`
+	Name                  string           `json:"name"`
+	Description           string           `json:"description"`
+	Status                TypeStatus       `json:"status"`
+	ServiceType           TypeService      `json:"serviceType"`
+	Version               int              `json:"version"`
+	CreatedAt             time.Time        `json:"createdAt"`
+	OrganizationId        uuid.UUID        `json:"organizationId"`
+	SubmissionDeadline    *time.Time       `json:"submissionDeadline,omitempty"`
+	ClarificationDeadline *time.Time       `json:"clarificationDeadline,omitempty"`
+	Visibility            TypeVisibility   `json:"visibility"`
+	Publication           *Publication     `json:"publication,omitempty"`
+	PublicationError      string           `json:"publicationError,omitempty"`
+	EstimatedBudget       *decimal.Decimal `json:"estimatedBudget,omitempty"`
+	MaxPrice              *decimal.Decimal `json:"maxPrice,omitempty"`
+	Currency              string           `json:"currency"`
+	Items                 []*TenderItem    `json:"items,omitempty"`
+	Lots                  []*Lot           `json:"lots,omitempty"`
+	Sealed                bool             `json:"sealed"`
+	OpeningAt             *time.Time       `json:"openingAt,omitempty"`
+	OpenedAt              *time.Time       `json:"openedAt,omitempty"`
+	EvaluationPhase       EvaluationPhase  `json:"evaluationPhase,omitempty"`
+	CancellationReason    string           `json:"cancellationReason,omitempty"`
+	CanceledAt            *time.Time       `json:"canceledAt,omitempty"`
+}
+
+// QuestionsDeadline is when clarification questions close: the clarification
+// deadline if set, otherwise the submission deadline.
+func (t *TendersResponse) QuestionsDeadline() *time.Time {
+	if t.ClarificationDeadline != nil {
+		return t.ClarificationDeadline
+	}
+	return t.SubmissionDeadline
 }
 
 type TenderEditRequest struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	ServiceType TypeService `json:"serviceType"`
+	Name                  string           `json:"name"`
+	Description           string           `json:"description"`
+	ServiceType           TypeService      `json:"serviceType"`
+	SubmissionDeadline    *time.Time       `json:"submissionDeadline,omitempty"`
+	ClarificationDeadline *time.Time       `json:"clarificationDeadline,omitempty"`
+	EstimatedBudget       *decimal.Decimal `json:"estimatedBudget,omitempty"`
+	MaxPrice              *decimal.Decimal `json:"maxPrice,omitempty"`
+	Currency              string           `json:"currency,omitempty"`
+}
+
+// PublicationRequest accepts either an RFC 3339 instant or a local wall time
+// ("2006-01-02T15:04") that is interpreted in Timezone.
+type PublicationRequest struct {
+	PublishAt string `json:"publishAt"`
+	Timezone  string `json:"timezone,omitempty"`
+}
+
+type Publication struct {
+	PublishAt time.Time `json:"publishAt"`
+	Timezone  string    `json:"timezone"`
+}
+
+type ScheduledPublication struct {
+	TenderId        uuid.UUID
+	CreatorUsername string
+	Attempts        int
 }
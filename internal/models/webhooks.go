@@ -0,0 +1,60 @@
+package models
+
+import (
+	"github.com/satori/uuid"
+	"time"
+)
+
+type TypeDeliveryStatus string
+
+const (
+	DeliveryPending    TypeDeliveryStatus = "Pending"
+	DeliveryDelivered  TypeDeliveryStatus = "Delivered"
+	DeliveryDeadLetter TypeDeliveryStatus = "DeadLetter"
+)
+
+type WebhookRequest struct {
+	Url        string      `json:"url"`
+	EventTypes []EventType `json:"eventTypes"`
+	Secret     string      `json:"secret"`
+}
+
+type WebhookResponse struct {
+	Id             uuid.UUID   `json:"id"`
+	OrganizationId uuid.UUID   `json:"organizationId"`
+	Url            string      `json:"url"`
+	EventTypes     []EventType `json:"eventTypes"`
+	CreatedAt      time.Time   `json:"createdAt"`
+}
+
+type WebhookDelivery struct {
+	Id            uuid.UUID          `json:"id"`
+	WebhookId     uuid.UUID          `json:"webhookId"`
+	EventId       int64              `json:"eventId"`
+	EventType     EventType          `json:"eventType"`
+	Status        TypeDeliveryStatus `json:"status"`
+	Attempts      int                `json:"attempts"`
+	NextAttemptAt time.Time          `json:"nextAttemptAt"`
+	LastError     string             `json:"lastError,omitempty"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	DeliveredAt   *time.Time         `json:"deliveredAt,omitempty"`
+}
+
+type WebhookAttempt struct {
+	Id             uuid.UUID `json:"id"`
+	DeliveryId     uuid.UUID `json:"deliveryId"`
+	AttemptedAt    time.Time `json:"attemptedAt"`
+	ResponseStatus *int      `json:"responseStatus,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	DurationMs     int64     `json:"durationMs"`
+}
+
+// WebhookJob is a claimed delivery together with everything needed to send it.
+type WebhookJob struct {
+	DeliveryId uuid.UUID
+	EventType  EventType
+	Url        string
+	Secret     string
+	Payload    []byte
+	Attempts   int
+}
@@ -9,4 +9,58 @@ var (
 	ErrTenderNotFound = errors.New("тендер не найден")
 	ErrBidNotFound    = errors.New("предложение не найдено")
 	ErrInternal       = errors.New("внутренняя ошибка сервера")
+
+	ErrDeadlinePassed         = errors.New("срок подачи предложений истёк")
+	ErrPublicationNotAllowed  = errors.New("публикацию можно запланировать только для тендера в статусе Created")
+	ErrPriceAboveMax          = errors.New("цена предложения превышает максимальную цену тендера")
+	ErrTenderNotEditable      = errors.New("позиции можно менять только у тендера в статусе Created")
+	ErrMandatoryItemMissing   = errors.New("в предложении не указана цена обязательной позиции тендера")
+	ErrRankingNotFound        = errors.New("ранжирование не найдено")
+	ErrBidsSealed             = errors.New("предложения запечатаны до вскрытия конвертов")
+	ErrSealingDisabled        = errors.New("закрытые тендеры не настроены на сервере")
+	ErrProtocolNotFound       = errors.New("протокол вскрытия не найден")
+	ErrWrongPhase             = errors.New("действие недоступно на текущем этапе оценки")
+	ErrTechnicalReviewPending = errors.New("не все предложения прошли техническую оценку")
+	ErrAuctionNotFound        = errors.New("аукцион не найден")
+	ErrAuctionStarted         = errors.New("аукцион уже начался")
+	ErrAuctionNotActive       = errors.New("аукцион сейчас не проводится")
+	ErrAuctionNotAllowed      = errors.New("аукцион можно назначить только для тендера в статусе Created или Published")
+	ErrAuctionAfterDeadline   = errors.New("аукцион должен закончиться до окончания срока подачи предложений")
+	ErrDecrementTooSmall      = errors.New("снижение цены меньше шага аукциона")
+	ErrLotNotFound            = errors.New("лот не найден")
+	ErrLotClosed              = errors.New("лот уже закрыт")
+	ErrLotNotClosable         = errors.New("решение по лоту доступно только в опубликованном или закрытом тендере")
+	ErrLotDecisionRequired    = errors.New("по тендеру с лотами решение принимается по каждому лоту")
+	ErrQuestionNotFound       = errors.New("вопрос не найден")
+	ErrQuestionAnswered       = errors.New("на вопрос уже дан ответ")
+	ErrClarificationClosed    = errors.New("приём вопросов по тендеру закрыт")
+	ErrInvitationNotFound     = errors.New("приглашение не найдено")
+	ErrInvitationResponded    = errors.New("на приглашение уже дан ответ")
+	ErrNotInvited             = errors.New("тендер доступен только по приглашению")
+	ErrBidNotWithdrawable     = errors.New("предложение нельзя отозвать после решения по нему")
+	ErrBidCanceled            = errors.New("предложение отозвано")
+	ErrTenderNotCancelable    = errors.New("отменить можно только тендер в статусе Created или Published")
+	ErrTenderCanceled         = errors.New("тендер отменён")
+	ErrTenderNotAwardable     = errors.New("победителя можно выбрать только в опубликованном или закрытом тендере без победителя")
+	ErrTenderNotPublished     = errors.New("предложения принимаются только по опубликованному тендеру")
+	ErrBidDecided             = errors.New("решение по предложению уже принято")
+	ErrAwardNotFound          = errors.New("победитель тендера не выбран")
+	ErrContractNotFound       = errors.New("контракт не найден")
+	ErrContractExists         = errors.New("контракт по тендеру уже заключён")
+	ErrContractCompleted      = errors.New("контракт уже исполнен")
+	ErrMilestoneNotFound      = errors.New("этап контракта не найден")
+	ErrMilestoneState         = errors.New("действие недоступно в текущем статусе этапа")
+	ErrApplicationNotFound    = errors.New("заявка на предквалификацию не найдена")
+	ErrApplicationExists      = errors.New("заявка на предквалификацию уже подана")
+	ErrApplicationReviewed    = errors.New("заявка на предквалификацию уже рассмотрена")
+	ErrRequirementsLocked     = errors.New("требования нельзя менять после подачи заявок или предложений")
+	ErrNotPrequalified        = errors.New("автор предложения не прошёл предквалификацию")
+	ErrCurrencyLocked         = errors.New("валюту тендера нельзя менять после подачи предложений")
+
+	ErrWebhookNotFound  = errors.New("подписка на вебхуки не найдена")
+	ErrDeliveryNotFound = errors.New("доставка вебхука не найдена")
+
+	ErrRequestTooLarge      = errors.New("слишком большое тело запроса")
+	ErrUnsupportedMediaType = errors.New("тело запроса должно быть в формате application/json")
+	ErrTooManyRequests      = errors.New("слишком много запросов, повторите позже")
 )
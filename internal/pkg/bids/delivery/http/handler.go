@@ -22,7 +22,7 @@ func NewHandler(u bids.BidUsecase) *BidHandler {
 func (h *BidHandler) CreateNewBid(w http.ResponseWriter, r *http.Request) {
 	var bidData *models.BidRequest
 	if err := utils.ReadRequestData(r, &bidData); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		utils.WriteRequestError(w, err)
 		return
 	}
 	newBid, err := h.u.CreateNewBid(bidData)
@@ -31,15 +31,45 @@ func (h *BidHandler) CreateNewBid(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, myErrors.ErrBadRequest):
 			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
 			return
+		case errors.Is(err, myErrors.ErrAuctionStarted):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrAuctionStarted)
+			return
+		case errors.Is(err, myErrors.ErrTenderCanceled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderCanceled)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotPublished):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderNotPublished)
+			return
 		case errors.Is(err, myErrors.ErrForbidden):
 			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
 			return
+		case errors.Is(err, myErrors.ErrNotInvited):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrNotInvited)
+			return
+		case errors.Is(err, myErrors.ErrNotPrequalified):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrNotPrequalified)
+			return
 		case errors.Is(err, myErrors.ErrUserNotFound):
 			utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
 			return
 		case errors.Is(err, myErrors.ErrTenderNotFound):
 			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
 			return
+		case errors.Is(err, myErrors.ErrDeadlinePassed):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrDeadlinePassed)
+			return
+		case errors.Is(err, myErrors.ErrWrongPhase):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrWrongPhase)
+			return
+		case errors.Is(err, myErrors.ErrPriceAboveMax):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrPriceAboveMax)
+			return
+		case errors.Is(err, myErrors.ErrSealingDisabled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrSealingDisabled)
+			return
+		case errors.Is(err, myErrors.ErrMandatoryItemMissing):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrMandatoryItemMissing)
+			return
 		default:
 			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
 			return
@@ -97,15 +127,25 @@ func (h *BidHandler) GetTenderBids(w http.ResponseWriter, r *http.Request) {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
-	bidsList, err := h.u.GetTenderBids(limit, offset, tenderId, username)
+	sort := models.BidSortName
+	if value := r.URL.Query().Get("sort"); value != "" {
+		sort = models.BidSort(value)
+	}
+	bidsList, err := h.u.GetTenderBids(limit, offset, tenderId, username, sort)
 	if err != nil {
 		switch {
 		case errors.Is(err, myErrors.ErrBadRequest):
 			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
 			return
+		case errors.Is(err, myErrors.ErrWrongPhase):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrWrongPhase)
+			return
 		case errors.Is(err, myErrors.ErrForbidden):
 			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
 			return
+		case errors.Is(err, myErrors.ErrNotInvited):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrNotInvited)
+			return
 		case errors.Is(err, myErrors.ErrUserNotFound):
 			utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
 			return
@@ -123,6 +163,43 @@ func (h *BidHandler) GetTenderBids(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJSON(w, http.StatusOK, bidsList)
 }
 
+func (h *BidHandler) GetBid(w http.ResponseWriter, r *http.Request) {
+	bidId, err := uuid.FromString(mux.Vars(r)["bidId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	bid, err := h.u.GetBid(bidId, username)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		case errors.Is(err, myErrors.ErrUserNotFound):
+			utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		case errors.Is(err, myErrors.ErrBidNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrBidNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, bid)
+}
+
 func (h *BidHandler) GetBidStatus(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	bidIdStr := vars["bidId"]
@@ -179,6 +256,15 @@ func (h *BidHandler) EditBidStatus(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, myErrors.ErrBadRequest):
 			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
 			return
+		case errors.Is(err, myErrors.ErrDeadlinePassed):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrDeadlinePassed)
+			return
+		case errors.Is(err, myErrors.ErrBidCanceled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBidCanceled)
+			return
+		case errors.Is(err, myErrors.ErrTenderCanceled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderCanceled)
+			return
 		case errors.Is(err, myErrors.ErrForbidden):
 			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
 			return
@@ -188,6 +274,9 @@ func (h *BidHandler) EditBidStatus(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, myErrors.ErrBidNotFound):
 			utils.WriteError(w, http.StatusNotFound, myErrors.ErrBidNotFound)
 			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
 		default:
 			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
 			return
@@ -195,6 +284,62 @@ func (h *BidHandler) EditBidStatus(w http.ResponseWriter, r *http.Request) {
 	}
 	utils.WriteJSON(w, http.StatusOK, editedBid)
 }
+func (h *BidHandler) WithdrawBid(w http.ResponseWriter, r *http.Request) {
+	bidId, err := uuid.FromString(mux.Vars(r)["bidId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var withdrawal models.WithdrawRequest
+	if r.ContentLength != 0 {
+		if err = utils.ReadRequestData(r, &withdrawal); err != nil {
+			utils.WriteRequestError(w, err)
+			return
+		}
+	}
+	bid, err := h.u.WithdrawBid(bidId, username, &withdrawal)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrBidCanceled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBidCanceled)
+			return
+		case errors.Is(err, myErrors.ErrTenderCanceled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderCanceled)
+			return
+		case errors.Is(err, myErrors.ErrBidNotWithdrawable):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBidNotWithdrawable)
+			return
+		case errors.Is(err, myErrors.ErrDeadlinePassed):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrDeadlinePassed)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		case errors.Is(err, myErrors.ErrUserNotFound):
+			utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		case errors.Is(err, myErrors.ErrBidNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrBidNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, bid)
+}
+
 func (h *BidHandler) EditBid(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	bidIdStr := vars["bidId"]
@@ -210,7 +355,7 @@ func (h *BidHandler) EditBid(w http.ResponseWriter, r *http.Request) {
 	}
 	var editedData *models.BidEditRequest
 	if err = utils.ReadRequestData(r, &editedData); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		utils.WriteRequestError(w, err)
 		return
 	}
 	editedBid, err := h.u.EditBid(bidId, username, editedData)
@@ -219,6 +364,18 @@ func (h *BidHandler) EditBid(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, myErrors.ErrBadRequest):
 			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
 			return
+		case errors.Is(err, myErrors.ErrBidCanceled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBidCanceled)
+			return
+		case errors.Is(err, myErrors.ErrTenderCanceled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderCanceled)
+			return
+		case errors.Is(err, myErrors.ErrAuctionStarted):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrAuctionStarted)
+			return
+		case errors.Is(err, myErrors.ErrWrongPhase):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrWrongPhase)
+			return
 		case errors.Is(err, myErrors.ErrForbidden):
 			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
 			return
@@ -228,6 +385,21 @@ func (h *BidHandler) EditBid(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, myErrors.ErrBidNotFound):
 			utils.WriteError(w, http.StatusNotFound, myErrors.ErrBidNotFound)
 			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		case errors.Is(err, myErrors.ErrPriceAboveMax):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrPriceAboveMax)
+			return
+		case errors.Is(err, myErrors.ErrDeadlinePassed):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrDeadlinePassed)
+			return
+		case errors.Is(err, myErrors.ErrSealingDisabled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrSealingDisabled)
+			return
+		case errors.Is(err, myErrors.ErrMandatoryItemMissing):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrMandatoryItemMissing)
+			return
 		default:
 			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
 			return
@@ -256,6 +428,27 @@ func (h *BidHandler) SubmitDecision(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, myErrors.ErrBadRequest):
 			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
 			return
+		case errors.Is(err, myErrors.ErrWrongPhase):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrWrongPhase)
+			return
+		case errors.Is(err, myErrors.ErrBidsSealed):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBidsSealed)
+			return
+		case errors.Is(err, myErrors.ErrLotDecisionRequired):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrLotDecisionRequired)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotAwardable):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderNotAwardable)
+			return
+		case errors.Is(err, myErrors.ErrBidDecided):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBidDecided)
+			return
+		case errors.Is(err, myErrors.ErrBidCanceled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBidCanceled)
+			return
+		case errors.Is(err, myErrors.ErrTenderCanceled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderCanceled)
+			return
 		case errors.Is(err, myErrors.ErrForbidden):
 			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
 			return
@@ -272,3 +465,345 @@ func (h *BidHandler) SubmitDecision(w http.ResponseWriter, r *http.Request) {
 	}
 	utils.WriteJSON(w, http.StatusOK, bid)
 }
+
+func (h *BidHandler) GetBidItems(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bidId, err := uuid.FromString(vars["bidId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	items, err := h.u.GetBidItems(bidId, username)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrWrongPhase):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrWrongPhase)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		case errors.Is(err, myErrors.ErrBidNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrBidNotFound)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	if items == nil {
+		items = []*models.BidItem{}
+	}
+	utils.WriteJSON(w, http.StatusOK, items)
+}
+
+func (h *BidHandler) GetOpeningProtocol(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	protocol, err := h.u.GetOpeningProtocol(tenderId, r.URL.Query().Get("username"))
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBidsSealed):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBidsSealed)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		case errors.Is(err, myErrors.ErrNotInvited):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrNotInvited)
+			return
+		case errors.Is(err, myErrors.ErrUserNotFound):
+			utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		case errors.Is(err, myErrors.ErrProtocolNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrProtocolNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, protocol)
+}
+
+func (h *BidHandler) ReviewTechnical(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bidId, err := uuid.FromString(vars["bidId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var review *models.TechnicalReviewRequest
+	if err = utils.ReadRequestData(r, &review); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	bid, err := h.u.ReviewTechnical(bidId, username, review)
+	if err != nil {
+		writeEnvelopeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, bid)
+}
+
+func (h *BidHandler) GetTechnicalEnvelopes(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := utils.ReadLimitOffset(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	envelopes, err := h.u.GetTechnicalEnvelopes(limit, offset, tenderId, username)
+	if err != nil {
+		writeEnvelopeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, envelopes)
+}
+
+func (h *BidHandler) GetFinancialEnvelopes(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := utils.ReadLimitOffset(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	envelopes, err := h.u.GetFinancialEnvelopes(limit, offset, tenderId, username)
+	if err != nil {
+		writeEnvelopeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, envelopes)
+}
+
+func (h *BidHandler) AdvancePhase(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	phase := r.URL.Query().Get("phase")
+	if username == "" || phase == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	tender, err := h.u.AdvancePhase(tenderId, username, models.EvaluationPhase(phase))
+	if err != nil {
+		writeEnvelopeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, tender)
+}
+
+func writeEnvelopeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, myErrors.ErrBadRequest):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+	case errors.Is(err, myErrors.ErrWrongPhase):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrWrongPhase)
+	case errors.Is(err, myErrors.ErrTechnicalReviewPending):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTechnicalReviewPending)
+	case errors.Is(err, myErrors.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+	case errors.Is(err, myErrors.ErrTenderNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+	case errors.Is(err, myErrors.ErrBidNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrBidNotFound)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+	}
+}
+
+func (h *BidHandler) PlaceAuctionPrice(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bidId, err := uuid.FromString(vars["bidId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var price *models.AuctionPriceRequest
+	if err = utils.ReadRequestData(r, &price); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	state, err := h.u.PlaceAuctionPrice(bidId, username, price)
+	if err != nil {
+		writeAuctionError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, state)
+}
+
+func (h *BidHandler) GetAuction(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	state, err := h.u.GetAuction(tenderId, username)
+	if err != nil {
+		writeAuctionError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, state)
+}
+
+func writeAuctionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, myErrors.ErrBadRequest):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+	case errors.Is(err, myErrors.ErrAuctionNotActive):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrAuctionNotActive)
+	case errors.Is(err, myErrors.ErrDecrementTooSmall):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrDecrementTooSmall)
+	case errors.Is(err, myErrors.ErrPriceAboveMax):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrPriceAboveMax)
+	case errors.Is(err, myErrors.ErrTenderCanceled):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderCanceled)
+	case errors.Is(err, myErrors.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+	case errors.Is(err, myErrors.ErrNotInvited):
+		utils.WriteError(w, http.StatusForbidden, myErrors.ErrNotInvited)
+	case errors.Is(err, myErrors.ErrTenderNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+	case errors.Is(err, myErrors.ErrBidNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrBidNotFound)
+	case errors.Is(err, myErrors.ErrAuctionNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrAuctionNotFound)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+	}
+}
+
+func (h *BidHandler) GetBidLots(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bidId, err := uuid.FromString(vars["bidId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	lots, err := h.u.GetBidLots(bidId, username)
+	if err != nil {
+		writeLotError(w, err)
+		return
+	}
+	if lots == nil {
+		lots = []*models.BidLot{}
+	}
+	utils.WriteJSON(w, http.StatusOK, lots)
+}
+
+func (h *BidHandler) SubmitLotDecision(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bidId, err := uuid.FromString(vars["bidId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	lotId, err := uuid.FromString(vars["lotId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	decision := r.URL.Query().Get("decision")
+	if username == "" || decision == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	lot, err := h.u.SubmitLotDecision(bidId, lotId, username, decision)
+	if err != nil {
+		writeLotError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, lot)
+}
+
+func writeLotError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, myErrors.ErrBadRequest):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+	case errors.Is(err, myErrors.ErrLotClosed):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrLotClosed)
+	case errors.Is(err, myErrors.ErrLotNotClosable):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrLotNotClosable)
+	case errors.Is(err, myErrors.ErrBidDecided):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBidDecided)
+	case errors.Is(err, myErrors.ErrTenderCanceled):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderCanceled)
+	case errors.Is(err, myErrors.ErrBidsSealed):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBidsSealed)
+	case errors.Is(err, myErrors.ErrWrongPhase):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrWrongPhase)
+	case errors.Is(err, myErrors.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+	case errors.Is(err, myErrors.ErrTenderNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+	case errors.Is(err, myErrors.ErrBidNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrBidNotFound)
+	case errors.Is(err, myErrors.ErrLotNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrLotNotFound)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+	}
+}
@@ -2,26 +2,68 @@ package bids
 
 import (
 	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"time"
 	"zadanie-6105/internal/models"
 )
 
 type BidRepository interface {
-	CreateBid(bidData *models.BidRequest) (*models.BidResponse, error)
+	CreateBid(bidData *models.BidRequest, items []*models.BidItem, envelope []byte) (*models.BidResponse, error)
 	SelectUserBids(limit, offset int32, username string) ([]*models.BidResponse, error)
-	SelectTenderBids(limit, offset int32, tenderId uuid.UUID, username string) ([]*models.BidResponse, error)
+	SelectTenderBids(limit, offset int32, tenderId uuid.UUID, username string, sort models.BidSort) ([]*models.BidResponse, error)
+	SelectBid(bidId uuid.UUID) (*models.BidResponse, error)
+	SelectBidDetails(bidId uuid.UUID) (*models.BidDetails, error)
 	SelectBidStatus(bidId uuid.UUID) (string, error)
 	CheckBidAuthor(bidId uuid.UUID, username string) (bool, error)
 	UpdateBidStatus(bidId uuid.UUID, status string) (*models.BidResponse, error)
-	UpdateBid(bidId uuid.UUID, editedData *models.BidEditRequest) (*models.BidResponse, error)
-	SubmitDecision(bidId uuid.UUID, decision string, username string) (*models.BidResponse, error)
+	WithdrawBid(bidId uuid.UUID, reason string) (*models.BidResponse, error)
+	UpdateBid(bidId uuid.UUID, editedData *models.BidEditRequest, items []*models.BidItem, envelope []byte) (*models.BidResponse, error)
+	SelectBidItems(bidId uuid.UUID) ([]*models.BidItem, error)
+	SelectBidLots(bidId uuid.UUID) ([]*models.BidLot, error)
+	SubmitDecision(bidId uuid.UUID, decision string, username string, ranking *models.Ranking) (*models.BidResponse, error)
+	SelectBidEnvelope(bidId uuid.UUID) ([]byte, error)
+	SelectTendersToOpen(now time.Time) ([]uuid.UUID, error)
+	OpenSealedBids(tenderId uuid.UUID, now time.Time,
+		open func(ciphertext []byte) (*models.SealedEnvelope, error)) (*models.OpeningProtocol, error)
+	SelectOpeningProtocol(tenderId uuid.UUID) (*models.OpeningProtocol, error)
+	CheckTenderBidder(tenderId uuid.UUID, username string) (bool, error)
+	ReviewTechnical(bidId uuid.UUID, username string, review *models.TechnicalReviewRequest) (*models.BidResponse, error)
+	SelectQualifiedBids(limit, offset int32, tenderId uuid.UUID) ([]*models.BidResponse, error)
+	AdvancePhase(tenderId uuid.UUID, from, to models.EvaluationPhase) error
+	PlaceAuctionPrice(bidId uuid.UUID, price decimal.Decimal, now time.Time) (*models.BidResponse, *models.Auction, error)
+	SelectAuctionRanks(tenderId uuid.UUID, username string) ([]*models.AuctionRank, error)
+}
+
+// Ranker supplies the evaluation ranking a decision is linked to.
+type Ranker interface {
+	TenderRanking(tenderId uuid.UUID) (*models.Ranking, error)
+}
+
+// Prequalifier decides whether the author may bid on a tender that requires
+// prequalification.
+type Prequalifier interface {
+	CheckPrequalified(tender *models.TendersResponse, authorType models.TypeAuthor, authorId uuid.UUID) error
 }
 
 type BidUsecase interface {
 	CreateNewBid(bidData *models.BidRequest) (*models.BidResponse, error)
 	GetUserBids(limit, offset int32, username string) ([]*models.BidResponse, error)
-	GetTenderBids(limit, offset int32, tenderId uuid.UUID, username string) ([]*models.BidResponse, error)
+	GetTenderBids(limit, offset int32, tenderId uuid.UUID, username string, sort models.BidSort) ([]*models.BidResponse, error)
+	GetBid(bidId uuid.UUID, username string) (*models.BidDetails, error)
 	GetBidStatus(bidId uuid.UUID, username string) (string, error)
 	EditBidStatus(bidId uuid.UUID, username, status string) (*models.BidResponse, error)
+	WithdrawBid(bidId uuid.UUID, username string, withdrawal *models.WithdrawRequest) (*models.BidResponse, error)
 	EditBid(bidId uuid.UUID, username string, editedData *models.BidEditRequest) (*models.BidResponse, error)
 	SubmitDecision(bidId uuid.UUID, username string, decision string) (*models.BidResponse, error)
+	GetBidItems(bidId uuid.UUID, username string) ([]*models.BidItem, error)
+	OpenSealedBids(now time.Time) ([]*models.OpeningProtocol, error)
+	GetOpeningProtocol(tenderId uuid.UUID, username string) (*models.OpeningProtocol, error)
+	ReviewTechnical(bidId uuid.UUID, username string, review *models.TechnicalReviewRequest) (*models.BidResponse, error)
+	GetTechnicalEnvelopes(limit, offset int32, tenderId uuid.UUID, username string) ([]*models.TechnicalEnvelope, error)
+	GetFinancialEnvelopes(limit, offset int32, tenderId uuid.UUID, username string) ([]*models.FinancialEnvelope, error)
+	AdvancePhase(tenderId uuid.UUID, username string, phase models.EvaluationPhase) (*models.TendersResponse, error)
+	PlaceAuctionPrice(bidId uuid.UUID, username string, price *models.AuctionPriceRequest) (*models.AuctionState, error)
+	GetAuction(tenderId uuid.UUID, username string) (*models.AuctionState, error)
+	GetBidLots(bidId uuid.UUID, username string) ([]*models.BidLot, error)
+	SubmitLotDecision(bidId, lotId uuid.UUID, username string, decision string) (*models.Lot, error)
 }
@@ -2,13 +2,28 @@ package repo
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"time"
 	"zadanie-6105/internal/models"
 	"zadanie-6105/internal/myErrors"
+	repoOutbox "zadanie-6105/internal/pkg/outbox/repo"
+	repoTender "zadanie-6105/internal/pkg/tenders/repo"
 )
 
+const bidColumns = `id, name, description, status, tender_id, author_type, author_id, version, created_at,
+    price, currency, valid_until, decision, decision_ranking_id, decision_rank, sealed, technical_result, technical_comment,
+    withdrawn_at, withdrawal_reason, award_status, canceled_by`
+
+var bidOrder = map[models.BidSort]string{
+	models.BidSortName:      `name ASC`,
+	models.BidSortPriceAsc:  `price ASC NULLS LAST, created_at ASC`,
+	models.BidSortPriceDesc: `price DESC NULLS LAST, created_at ASC`,
+}
+
 type BidRepoPostgres struct {
 	db *sql.DB
 }
@@ -19,33 +34,50 @@ func NewRepository(db *sql.DB) *BidRepoPostgres {
 	}
 }
 
-func (r *BidRepoPostgres) CreateBid(bidData *models.BidRequest) (*models.BidResponse, error) {
-	var tenderExists bool
-	queryTender := `SELECT 1 FROM TENDER WHERE id = $1`
-	if err := r.db.QueryRow(queryTender, bidData.TenderId).Scan(&tenderExists); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, myErrors.ErrTenderNotFound
-		}
-		return nil, myErrors.ErrBadRequest
+func (r *BidRepoPostgres) CreateBid(bidData *models.BidRequest, items []*models.BidItem, envelope []byte) (*models.BidResponse, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err = tenderOrganization(tx, bidData.TenderId); err != nil {
+		return nil, err
 	}
 
 	query := `
-		INSERT INTO bid (name, description, tender_id, author_type, author_id, status)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, name, description, status, tender_id, author_type, author_id, version, created_at
+		INSERT INTO bid (name, description, tender_id, author_type, author_id, status, price, currency, valid_until, sealed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING ` + bidColumns + `
 	`
 
-	var bidResponse models.BidResponse
-	err := r.db.QueryRow(
+	bidResponse, err := scanBid(tx.QueryRow(
 		query,
-		bidData.Name, bidData.Description, bidData.TenderId, bidData.AuthorType, bidData.AuthorId, models.StatusCreated).Scan(
-		&bidResponse.Id, &bidResponse.Name, &bidResponse.Description, &bidResponse.Status, &bidResponse.TenderId,
-		&bidResponse.AuthorType, &bidResponse.AuthorId, &bidResponse.Version, &bidResponse.CreatedAt)
+		bidData.Name, bidData.Description, bidData.TenderId, bidData.AuthorType, bidData.AuthorId, models.StatusCreated,
+		bidData.Price, nullString(bidData.Currency), bidData.ValidUntil, envelope != nil))
 	if err != nil {
 		return nil, err
 	}
+	if err = insertBidItems(tx, bidResponse.Id, items); err != nil {
+		return nil, err
+	}
+	bidResponse.Items = items
+	for _, lotId := range bidData.LotIds {
+		if _, err = tx.Exec(`INSERT INTO bid_lot (bid_id, lot_id) VALUES ($1, $2)`, bidResponse.Id, lotId); err != nil {
+			return nil, myErrors.ErrBadRequest
+		}
+	}
+	bidResponse.LotIds = bidData.LotIds
+	if envelope != nil {
+		if _, err = tx.Exec(`INSERT INTO bid_envelope (bid_id, ciphertext) VALUES ($1, $2)`, bidResponse.Id, envelope); err != nil {
+			return nil, err
+		}
+	}
 
-	return &bidResponse, nil
+	if err = commitBidEvent(tx, models.EventBidSubmitted, bidResponse, bidResponse); err != nil {
+		return nil, err
+	}
+	return bidResponse, nil
 }
 func (r *BidRepoPostgres) SelectUserBids(limit, offset int32, username string) ([]*models.BidResponse, error) {
 	userId, err := r.GetUserIdByUsername(username)
@@ -53,7 +85,7 @@ func (r *BidRepoPostgres) SelectUserBids(limit, offset int32, username string) (
 		return nil, err
 	}
 	query := `
-		SELECT id, name, description, status, tender_id, author_type, author_id, version, created_at
+		SELECT ` + bidColumns + `
 		FROM bid
 		WHERE author_id = $1
 		ORDER BY name ASC
@@ -66,20 +98,14 @@ func (r *BidRepoPostgres) SelectUserBids(limit, offset int32, username string) (
 	}
 	defer rows.Close()
 
-	var bids []*models.BidResponse
-	for rows.Next() {
-		var bid models.BidResponse
-		err = rows.Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.TenderId,
-			&bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt)
-		if err != nil {
-			return nil, err
-		}
-		bids = append(bids, &bid)
+	return scanBids(rows)
+}
+func (r *BidRepoPostgres) SelectTenderBids(limit, offset int32, tenderId uuid.UUID, username string, sort models.BidSort) ([]*models.BidResponse, error) {
+	order, ok := bidOrder[sort]
+	if !ok {
+		return nil, myErrors.ErrBadRequest
 	}
 
-	return bids, nil
-}
-func (r *BidRepoPostgres) SelectTenderBids(limit, offset int32, tenderId uuid.UUID, username string) ([]*models.BidResponse, error) {
 	queryUser := `
 			SELECT 1
 			FROM organization_responsible AS o_r
@@ -107,11 +133,11 @@ func (r *BidRepoPostgres) SelectTenderBids(limit, offset int32, tenderId uuid.UU
 	}
 
 	query := `
-		SELECT id, name, description, status, tender_id, author_type, author_id, version, created_at
+		SELECT ` + bidColumns + `
 		FROM bid
 		WHERE tender_id = $1
 		AND status = $2
-		ORDER BY name ASC
+		ORDER BY ` + order + `
 		LIMIT $3 OFFSET $4
 	`
 
@@ -121,21 +147,105 @@ func (r *BidRepoPostgres) SelectTenderBids(limit, offset int32, tenderId uuid.UU
 	}
 	defer rows.Close()
 
-	var bids []*models.BidResponse
+	return scanBids(rows)
+}
+func (r *BidRepoPostgres) SelectBid(bidId uuid.UUID) (*models.BidResponse, error) {
+	query := `SELECT ` + bidColumns + ` FROM bid WHERE id = $1`
+
+	bid, err := scanBid(r.db.QueryRow(query, bidId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrBidNotFound
+		}
+		return nil, err
+	}
+	return bid, nil
+}
+
+// SelectBidDetails loads the bid with its author, tender and latest version.
+func (r *BidRepoPostgres) SelectBidDetails(bidId uuid.UUID) (*models.BidDetails, error) {
+	bid, err := r.SelectBid(bidId)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT COALESCE(employee.username, organization.name, ''), bid.version, bid.updated_at,
+		       tender.name, tender.status, tender.organization_id
+		FROM bid
+		JOIN tender ON tender.id = bid.tender_id
+		LEFT JOIN employee ON bid.author_type = 'User' AND employee.id = bid.author_id
+		LEFT JOIN organization ON bid.author_type = 'Organization' AND organization.id = bid.author_id
+		WHERE bid.id = $1`
+
+	details := &models.BidDetails{
+		BidResponse:   bid,
+		Author:        &models.BidAuthor{Type: bid.AuthorType, Id: bid.AuthorId},
+		Tender:        &models.BidTender{Id: bid.TenderId, Organization: &models.OrganizationInfo{}},
+		LatestVersion: &models.VersionInfo{},
+	}
+	err = r.db.QueryRow(query, bidId).Scan(&details.Author.Name, &details.LatestVersion.Version, &details.LatestVersion.UpdatedAt,
+		&details.Tender.Name, &details.Tender.Status, &details.Tender.Organization.Id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrBidNotFound
+		}
+		return nil, err
+	}
+	return details, nil
+}
+func (r *BidRepoPostgres) SelectBidItems(bidId uuid.UUID) ([]*models.BidItem, error) {
+	query := `
+		SELECT ti.id, ti.name, ti.quantity, ti.unit, bi.unit_price, bi.total
+		FROM bid_item AS bi
+		JOIN tender_item AS ti ON ti.id = bi.tender_item_id
+		WHERE bi.bid_id = $1
+		ORDER BY ti.position ASC
+	`
+
+	rows, err := r.db.Query(query, bidId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*models.BidItem
 	for rows.Next() {
-		var bid models.BidResponse
-		err = rows.Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.TenderId,
-			&bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt)
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return nil, myErrors.ErrBidNotFound
-			}
+		var item models.BidItem
+		if err = rows.Scan(&item.ItemId, &item.Name, &item.Quantity, &item.Unit, &item.UnitPrice, &item.Total); err != nil {
 			return nil, err
 		}
-		bids = append(bids, &bid)
+		items = append(items, &item)
+	}
+	return items, rows.Err()
+}
+func (r *BidRepoPostgres) SelectBidLots(bidId uuid.UUID) ([]*models.BidLot, error) {
+	query := `
+		SELECT l.id, l.name, bl.decision
+		FROM bid_lot AS bl
+		JOIN tender_lot AS l ON l.id = bl.lot_id
+		WHERE bl.bid_id = $1
+		ORDER BY l.position ASC
+	`
+
+	rows, err := r.db.Query(query, bidId)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return bids, nil
+	var lots []*models.BidLot
+	for rows.Next() {
+		var (
+			lot      models.BidLot
+			decision sql.NullString
+		)
+		if err = rows.Scan(&lot.LotId, &lot.Name, &decision); err != nil {
+			return nil, err
+		}
+		lot.Decision = models.TypeDecision(decision.String)
+		lots = append(lots, &lot)
+	}
+	return lots, rows.Err()
 }
 func (r *BidRepoPostgres) SelectBidStatus(bidId uuid.UUID) (string, error) {
 	query := `SELECT status FROM bid WHERE id = $1`
@@ -174,20 +284,58 @@ func (r *BidRepoPostgres) UpdateBidStatus(bidId uuid.UUID, status string) (*mode
 		UPDATE bid
 		SET status = $1
 		WHERE id = $2
-		RETURNING id, name, description, status, tender_id, author_type, author_id, version, created_at
+		RETURNING ` + bidColumns + `
 	`
 
-	var bid models.BidResponse
-	err := r.db.QueryRow(query, status, bidId).Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.TenderId,
-		&bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	bid, err := scanBid(tx.QueryRow(query, status, bidId))
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &bid, nil
+	if err = commitBidEvent(tx, models.EventBidStatusChanged, bid, bid); err != nil {
+		return nil, err
+	}
+	return bid, nil
 }
-func (r *BidRepoPostgres) UpdateBid(bidId uuid.UUID, editedData *models.BidEditRequest) (*models.BidResponse, error) {
+
+// WithdrawBid cancels a bid that has no decision on it or on any of its lots.
+// The bid is kept with its reason for audit.
+func (r *BidRepoPostgres) WithdrawBid(bidId uuid.UUID, reason string) (*models.BidResponse, error) {
+	query := `
+		UPDATE bid
+		SET status = $2, withdrawal_reason = $3, withdrawn_at = now(), canceled_by = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status IN ($4, $5) AND decision IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM bid_lot WHERE bid_lot.bid_id = bid.id AND bid_lot.decision IS NOT NULL)
+		RETURNING ` + bidColumns
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	bid, err := scanBid(tx.QueryRow(query, bidId, models.StatusCanceled, nullString(reason),
+		models.StatusCreated, models.StatusPublished, models.CanceledByAuthor))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrBidNotWithdrawable
+		}
+		return nil, err
+	}
+
+	if err = commitBidEvent(tx, models.EventBidWithdrawn, bid, bid); err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
+func (r *BidRepoPostgres) UpdateBid(bidId uuid.UUID, editedData *models.BidEditRequest, items []*models.BidItem, envelope []byte) (*models.BidResponse, error) {
 	query := `UPDATE bid SET updated_at = CURRENT_TIMESTAMP, version = version + 1`
 
 	var args []interface{}
@@ -202,23 +350,59 @@ func (r *BidRepoPostgres) UpdateBid(bidId uuid.UUID, editedData *models.BidEditR
 		args = append(args, editedData.Description)
 		argCounter++
 	}
+	if editedData.Price != nil {
+		query += `, price = $` + fmt.Sprint(argCounter)
+		args = append(args, *editedData.Price)
+		argCounter++
+	}
+	if editedData.Currency != "" {
+		query += `, currency = $` + fmt.Sprint(argCounter)
+		args = append(args, editedData.Currency)
+		argCounter++
+	}
+	if editedData.ValidUntil != nil {
+		query += `, valid_until = $` + fmt.Sprint(argCounter)
+		args = append(args, *editedData.ValidUntil)
+		argCounter++
+	}
 
 	query += ` WHERE id = $` + fmt.Sprint(argCounter)
 	args = append(args, bidId)
 
-	query += ` RETURNING id, name, description, status, tender_id, author_type, author_id, version, created_at`
+	query += ` RETURNING ` + bidColumns + ``
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-	var bid models.BidResponse
-	err := r.db.QueryRow(query, args...).Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.TenderId,
-		&bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt)
+	bid, err := scanBid(tx.QueryRow(query, args...))
 
 	if err != nil {
 		return nil, err
 	}
+	if items != nil {
+		if _, err = tx.Exec(`DELETE FROM bid_item WHERE bid_id = $1`, bidId); err != nil {
+			return nil, err
+		}
+		if err = insertBidItems(tx, bidId, items); err != nil {
+			return nil, err
+		}
+		bid.Items = items
+	}
+	if envelope != nil {
+		if _, err = tx.Exec(`UPDATE bid_envelope SET ciphertext = $1 WHERE bid_id = $2`, envelope, bidId); err != nil {
+			return nil, err
+		}
+	}
 
-	return &bid, nil
+	if err = commitBidEvent(tx, models.EventBidEdited, bid, bid); err != nil {
+		return nil, err
+	}
+	return bid, nil
 }
-func (r *BidRepoPostgres) SubmitDecision(bidId uuid.UUID, decision string, username string) (*models.BidResponse, error) {
+func (r *BidRepoPostgres) SubmitDecision(bidId uuid.UUID, decision string, username string, ranking *models.Ranking) (*models.BidResponse, error) {
 	queryUser := `
 			SELECT 1
 			FROM organization_responsible AS o_r
@@ -238,20 +422,520 @@ func (r *BidRepoPostgres) SubmitDecision(bidId uuid.UUID, decision string, usern
 	}
 	query := `
 		UPDATE bid
-		SET decision = $1, status = $2
-		WHERE id = $3
-		RETURNING id, name, description, status, tender_id, author_type, author_id, version, created_at
+		SET decision = $1, status = $2, decision_ranking_id = $4, decision_rank = $5, award_status = $6
+		WHERE id = $3 AND status = $7 AND decision IS NULL
+		RETURNING ` + bidColumns + `
 	`
 
-	var bid models.BidResponse
-	err = r.db.QueryRow(query, decision, models.StatusClosed, bidId).Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.TenderId,
-		&bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
+	// The tender is locked before the bid, so concurrent decisions on its
+	// bids queue up instead of both passing the award checks.
+	_, err = tx.Exec(`SELECT 1 FROM tender WHERE id = (SELECT tender_id FROM bid WHERE id = $1) FOR UPDATE`, bidId)
 	if err != nil {
 		return nil, err
 	}
 
-	return &bid, nil
+	var rankingId, rank *int64
+	if ranking != nil {
+		if rankingId, err = insertRanking(tx, ranking, username); err != nil {
+			return nil, err
+		}
+		if position := ranking.Position(bidId); position != nil {
+			value := int64(position.Rank)
+			rank = &value
+		}
+	}
+
+	approved := models.TypeDecision(decision) == models.DecisionApproved
+	var awardStatus sql.NullString
+	if approved {
+		awardStatus = nullString(string(models.AwardStatusAwarded))
+	}
+	bid, err := scanBid(tx.QueryRow(query, decision, models.StatusClosed, bidId, rankingId, rank, awardStatus,
+		models.StatusPublished))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrBidDecided
+		}
+		return nil, err
+	}
+
+	payload := &models.BidDecisionPayload{Bid: bid, Decision: models.TypeDecision(decision), Ranking: ranking}
+	if !approved {
+		if err = commitBidEvent(tx, models.EventBidDecisionMade, bid, payload); err != nil {
+			return nil, err
+		}
+		return bid, nil
+	}
+
+	event, err := bidEvent(tx, models.EventBidDecisionMade, bid, payload)
+	if err != nil {
+		return nil, err
+	}
+	if err = repoOutbox.Insert(tx, event); err != nil {
+		return nil, err
+	}
+	award, err := awardBid(tx, bid, username, rankingId)
+	if err != nil {
+		return nil, err
+	}
+	if err = commitWithEvent(tx, models.NewAwardEvent(award, event.OrganizationId)); err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
+
+// awardBid records the approved bid as the winner, marks the other open bids
+// of the tender as not awarded and closes the tender unless the submission
+// deadline has closed it already.
+func awardBid(tx *sql.Tx, bid *models.BidResponse, username string, rankingId *int64) (*models.Award, error) {
+	var status models.TypeStatus
+	err := tx.QueryRow(`SELECT status FROM tender WHERE id = $1 FOR UPDATE`, bid.TenderId).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrTenderNotFound
+		}
+		return nil, err
+	}
+	var awarded bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM tender_award WHERE tender_id = $1)`, bid.TenderId).Scan(&awarded)
+	if err != nil {
+		return nil, err
+	}
+	if !status.Awardable() || awarded {
+		return nil, myErrors.ErrTenderNotAwardable
+	}
+
+	queryOthers := `
+		UPDATE bid
+		SET status = $1, award_status = $2
+		WHERE tender_id = $3 AND id <> $4 AND status IN ($5, $6)
+	`
+	_, err = tx.Exec(queryOthers, models.StatusClosed, models.AwardStatusNotAwarded, bid.TenderId, bid.Id,
+		models.StatusCreated, models.StatusPublished)
+	if err != nil {
+		return nil, err
+	}
+
+	award := &models.Award{
+		TenderId:   bid.TenderId,
+		BidId:      bid.Id,
+		AuthorType: bid.AuthorType,
+		AuthorId:   bid.AuthorId,
+		Price:      bid.Price,
+		Currency:   bid.Currency,
+		AwardedBy:  username,
+		RankingId:  rankingId,
+	}
+	queryAward := `
+		INSERT INTO tender_award (tender_id, bid_id, author_type, author_id, price, currency, awarded_by, ranking_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING awarded_at
+	`
+	err = tx.QueryRow(queryAward, award.TenderId, award.BidId, award.AuthorType, award.AuthorId, award.Price,
+		nullString(award.Currency), award.AwardedBy, award.RankingId).Scan(&award.AwardedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// A tender closed by its submission deadline is already Closed.
+	tender, err := repoTender.CloseTender(tx, bid.TenderId)
+	switch {
+	case err == nil:
+		if err = repoOutbox.Insert(tx, models.NewTenderEvent(models.EventTenderClosed, tender)); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+	return award, nil
+}
+
+func (r *BidRepoPostgres) SelectBidEnvelope(bidId uuid.UUID) ([]byte, error) {
+	var envelope []byte
+	err := r.db.QueryRow(`SELECT ciphertext FROM bid_envelope WHERE bid_id = $1`, bidId).Scan(&envelope)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrBidNotFound
+		}
+		return nil, err
+	}
+	return envelope, nil
+}
+func (r *BidRepoPostgres) SelectTendersToOpen(now time.Time) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM tender
+		WHERE sealed AND opened_at IS NULL AND opening_at <= $1
+		ORDER BY opening_at ASC
+	`
+
+	rows, err := r.db.Query(query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenderIds []uuid.UUID
+	for rows.Next() {
+		var tenderId uuid.UUID
+		if err = rows.Scan(&tenderId); err != nil {
+			return nil, err
+		}
+		tenderIds = append(tenderIds, tenderId)
+	}
+	return tenderIds, rows.Err()
+}
+
+// OpenSealedBids decrypts every sealed bid of the tender with open, stores the
+// plain values and records the opening protocol in one transaction. It returns
+// nil when the tender has already been opened.
+func (r *BidRepoPostgres) OpenSealedBids(tenderId uuid.UUID, now time.Time,
+	open func(ciphertext []byte) (*models.SealedEnvelope, error)) (*models.OpeningProtocol, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var (
+		organizationId uuid.UUID
+		openedAt       sql.NullTime
+	)
+	err = tx.QueryRow(`SELECT organization_id, opened_at FROM tender WHERE id = $1 AND sealed FOR UPDATE`, tenderId).Scan(
+		&organizationId, &openedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrTenderNotFound
+		}
+		return nil, err
+	}
+	if openedAt.Valid {
+		return nil, nil
+	}
+
+	query := `
+		SELECT b.id, b.author_type, b.author_id, b.currency, b.status, b.created_at, e.ciphertext
+		FROM bid AS b
+		JOIN bid_envelope AS e ON e.bid_id = b.id
+		WHERE b.tender_id = $1 AND b.status = $2
+		ORDER BY b.created_at ASC, b.id ASC
+	`
+	rows, err := tx.Query(query, tenderId, models.StatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	type sealedBid struct {
+		entry      *models.OpeningEntry
+		ciphertext []byte
+	}
+	var sealed []sealedBid
+	for rows.Next() {
+		var (
+			entry    models.OpeningEntry
+			currency sql.NullString
+			data     []byte
+		)
+		err = rows.Scan(&entry.BidId, &entry.AuthorType, &entry.AuthorId, &currency, &entry.Status, &entry.SubmittedAt, &data)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		entry.Currency = currency.String
+		sealed = append(sealed, sealedBid{entry: &entry, ciphertext: data})
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	protocol := &models.OpeningProtocol{TenderId: tenderId, OpenedAt: now, Bids: []*models.OpeningEntry{}}
+	for _, bid := range sealed {
+		// A broken envelope must not hold back the opening of the others.
+		envelope, err := open(bid.ciphertext)
+		if err != nil {
+			bid.entry.Unreadable = true
+			protocol.Bids = append(protocol.Bids, bid.entry)
+			continue
+		}
+		_, err = tx.Exec(`UPDATE bid SET name = $1, description = $2, price = $3, sealed = FALSE WHERE id = $4`,
+			envelope.Name, envelope.Description, envelope.Price, bid.entry.BidId)
+		if err != nil {
+			return nil, err
+		}
+		if err = insertBidItems(tx, bid.entry.BidId, envelope.Items); err != nil {
+			return nil, err
+		}
+		bid.entry.Name = envelope.Name
+		bid.entry.Price = envelope.Price
+		protocol.Bids = append(protocol.Bids, bid.entry)
+	}
+
+	data, err := json.Marshal(protocol)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`INSERT INTO tender_opening_protocol (tender_id, opened_at, protocol) VALUES ($1, $2, $3::jsonb)`,
+		tenderId, now, string(data))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.Exec(`UPDATE tender SET opened_at = $1 WHERE id = $2`, now, tenderId); err != nil {
+		return nil, err
+	}
+
+	if err = commitWithEvent(tx, models.NewOpeningEvent(protocol, organizationId)); err != nil {
+		return nil, err
+	}
+	return protocol, nil
+}
+
+// CheckTenderBidder reports whether the employee authored a bid on the tender.
+func (r *BidRepoPostgres) CheckTenderBidder(tenderId uuid.UUID, username string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM bid AS b
+			JOIN employee AS e ON e.id = b.author_id
+			WHERE b.tender_id = $1 AND e.username = $2
+		)`
+	var exists bool
+	err := r.db.QueryRow(query, tenderId, username).Scan(&exists)
+	return exists, err
+}
+
+func (r *BidRepoPostgres) SelectOpeningProtocol(tenderId uuid.UUID) (*models.OpeningProtocol, error) {
+	var data []byte
+	err := r.db.QueryRow(`SELECT protocol FROM tender_opening_protocol WHERE tender_id = $1`, tenderId).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrProtocolNotFound
+		}
+		return nil, err
+	}
+	var protocol models.OpeningProtocol
+	if err = json.Unmarshal(data, &protocol); err != nil {
+		return nil, err
+	}
+	return &protocol, nil
+}
+
+// ReviewTechnical records the technical verdict on a bid while its tender is
+// still in technical review.
+func (r *BidRepoPostgres) ReviewTechnical(bidId uuid.UUID, username string, review *models.TechnicalReviewRequest) (*models.BidResponse, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var phase sql.NullString
+	queryPhase := `
+		SELECT t.evaluation_phase
+		FROM tender AS t
+		JOIN bid AS b ON b.tender_id = t.id
+		WHERE b.id = $1
+		FOR SHARE OF t
+	`
+	if err = tx.QueryRow(queryPhase, bidId).Scan(&phase); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrBidNotFound
+		}
+		return nil, err
+	}
+	if phase.Valid && models.EvaluationPhase(phase.String) != models.PhaseTechnicalReview {
+		return nil, myErrors.ErrWrongPhase
+	}
+
+	query := `
+		UPDATE bid
+		SET technical_result = $1, technical_comment = $2, technical_reviewed_by = $3, technical_reviewed_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING ` + bidColumns + `
+	`
+	bid, err := scanBid(tx.QueryRow(query, review.Result, nullString(review.Comment), username, bidId))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = commitBidEvent(tx, models.EventBidTechnicalReviewed, bid, bid); err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
+func (r *BidRepoPostgres) SelectQualifiedBids(limit, offset int32, tenderId uuid.UUID) ([]*models.BidResponse, error) {
+	query := `
+		SELECT ` + bidColumns + `
+		FROM bid
+		WHERE tender_id = $1 AND status = $2 AND technical_result = $3
+		ORDER BY price ASC NULLS LAST, created_at ASC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.Query(query, tenderId, models.StatusPublished, models.TechnicalQualified, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanBids(rows)
+}
+
+// AdvancePhase moves the tender from one evaluation phase to the next. Prices
+// are opened only once every published bid has a technical verdict.
+func (r *BidRepoPostgres) AdvancePhase(tenderId uuid.UUID, from, to models.EvaluationPhase) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var (
+		organizationId uuid.UUID
+		current        models.EvaluationPhase
+	)
+	queryTender := `SELECT organization_id, COALESCE(evaluation_phase, $2) FROM tender WHERE id = $1 FOR UPDATE`
+	if err = tx.QueryRow(queryTender, tenderId, models.PhaseTechnicalReview).Scan(&organizationId, &current); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return myErrors.ErrTenderNotFound
+		}
+		return err
+	}
+	if current != from {
+		return myErrors.ErrWrongPhase
+	}
+	if to == models.PhaseFinancialOpening {
+		var pending int
+		queryPending := `SELECT COUNT(*) FROM bid WHERE tender_id = $1 AND status = $2 AND technical_result IS NULL`
+		if err = tx.QueryRow(queryPending, tenderId, models.StatusPublished).Scan(&pending); err != nil {
+			return err
+		}
+		if pending > 0 {
+			return myErrors.ErrTechnicalReviewPending
+		}
+	}
+
+	if _, err = tx.Exec(`UPDATE tender SET evaluation_phase = $1 WHERE id = $2`, to, tenderId); err != nil {
+		return err
+	}
+	change := &models.PhaseChange{TenderId: tenderId, From: from, To: to}
+	return commitWithEvent(tx, models.NewPhaseEvent(change, organizationId))
+}
+
+// PlaceAuctionPrice lowers the bid price during a reverse auction. The auction
+// row is locked for the whole transaction, so concurrent prices for one tender
+// are applied one at a time against the latest end time and bid price.
+func (r *BidRepoPostgres) PlaceAuctionPrice(bidId uuid.UUID, price decimal.Decimal, now time.Time) (*models.BidResponse, *models.Auction, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var tenderId uuid.UUID
+	if err = tx.QueryRow(`SELECT tender_id FROM bid WHERE id = $1`, bidId).Scan(&tenderId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, myErrors.ErrBidNotFound
+		}
+		return nil, nil, err
+	}
+
+	var auction models.Auction
+	queryAuction := `
+		SELECT tender_id, starts_at, ends_at, min_decrement, extension_window, extension
+		FROM tender_auction
+		WHERE tender_id = $1
+		FOR UPDATE
+	`
+	err = tx.QueryRow(queryAuction, tenderId).Scan(&auction.TenderId, &auction.StartsAt, &auction.EndsAt,
+		&auction.MinDecrement, &auction.ExtensionWindow, &auction.Extension)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, myErrors.ErrAuctionNotFound
+		}
+		return nil, nil, err
+	}
+	if auction.Status(now) != models.AuctionActive {
+		return nil, nil, myErrors.ErrAuctionNotActive
+	}
+
+	current, err := scanBid(tx.QueryRow(`SELECT `+bidColumns+` FROM bid WHERE id = $1 FOR UPDATE`, bidId))
+	if err != nil {
+		return nil, nil, err
+	}
+	if current.Status != models.StatusPublished {
+		return nil, nil, myErrors.ErrBadRequest
+	}
+	if !auction.ValidDecrement(current.Price, price) {
+		return nil, nil, myErrors.ErrDecrementTooSmall
+	}
+
+	query := `
+		UPDATE bid
+		SET price = $1, currency = COALESCE(currency, (SELECT currency FROM tender WHERE id = bid.tender_id)),
+		    version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+		RETURNING ` + bidColumns + `
+	`
+	bid, err := scanBid(tx.QueryRow(query, price, bidId))
+	if err != nil {
+		return nil, nil, err
+	}
+	_, err = tx.Exec(`INSERT INTO auction_price (tender_id, bid_id, price, placed_at) VALUES ($1, $2, $3, $4)`,
+		tenderId, bidId, price, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if auction.Extend(now) {
+		if _, err = tx.Exec(`UPDATE tender_auction SET ends_at = $1 WHERE tender_id = $2`, auction.EndsAt, tenderId); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	payload := &models.AuctionPricePayload{Bid: bid, EndsAt: auction.EndsAt}
+	if err = commitBidEvent(tx, models.EventAuctionPricePlaced, bid, payload); err != nil {
+		return nil, nil, err
+	}
+	return bid, &auction, nil
+}
+
+// SelectAuctionRanks lists priced published bids from the lowest price; equal
+// prices are ordered by who placed them first. Own marks bids of username.
+func (r *BidRepoPostgres) SelectAuctionRanks(tenderId uuid.UUID, username string) ([]*models.AuctionRank, error) {
+	query := `
+		SELECT b.id, b.price, COALESCE(b.currency, ''), COALESCE(b.author_id = e.id, FALSE)
+		FROM bid AS b
+		LEFT JOIN LATERAL (SELECT MAX(placed_at) AS placed_at FROM auction_price WHERE bid_id = b.id) AS p ON TRUE
+		LEFT JOIN employee AS e ON e.username = $2
+		WHERE b.tender_id = $1 AND b.status = $3 AND b.price IS NOT NULL
+		ORDER BY b.price ASC, COALESCE(p.placed_at, b.created_at) ASC, b.id ASC
+	`
+
+	rows, err := r.db.Query(query, tenderId, username, models.StatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ranks []*models.AuctionRank
+	for rows.Next() {
+		var (
+			rank  models.AuctionRank
+			bidId uuid.UUID
+		)
+		if err = rows.Scan(&bidId, &rank.Price, &rank.Currency, &rank.Own); err != nil {
+			return nil, err
+		}
+		rank.Rank = len(ranks) + 1
+		rank.BidId = &bidId
+		ranks = append(ranks, &rank)
+	}
+	return ranks, rows.Err()
 }
 
 func (r *BidRepoPostgres) GetUserIdByUsername(username string) (uuid.UUID, error) {
@@ -265,3 +949,132 @@ func (r *BidRepoPostgres) GetUserIdByUsername(username string) (uuid.UUID, error
 
 	return userId, nil
 }
+
+func insertRanking(tx *sql.Tx, ranking *models.Ranking, username string) (*int64, error) {
+	data, err := json.Marshal(ranking)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		INSERT INTO tender_ranking (tender_id, ranking, created_by)
+		VALUES ($1, $2::jsonb, $3)
+		RETURNING id
+	`
+	var id int64
+	if err = tx.QueryRow(query, ranking.TenderId, string(data), username).Scan(&id); err != nil {
+		return nil, err
+	}
+	ranking.Id = id
+	return &id, nil
+}
+
+func insertBidItems(tx *sql.Tx, bidId uuid.UUID, items []*models.BidItem) error {
+	query := `
+		INSERT INTO bid_item (bid_id, tender_item_id, unit_price, total)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, item := range items {
+		if _, err := tx.Exec(query, bidId, item.ItemId, item.UnitPrice, item.Total); err != nil {
+			return myErrors.ErrBadRequest
+		}
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBid(row scanner) (*models.BidResponse, error) {
+	var (
+		bid       models.BidResponse
+		currency  sql.NullString
+		decision  sql.NullString
+		technical sql.NullString
+		comment   sql.NullString
+		reason    sql.NullString
+		award     sql.NullString
+		canceled  sql.NullString
+	)
+	err := row.Scan(&bid.Id, &bid.Name, &bid.Description, &bid.Status, &bid.TenderId,
+		&bid.AuthorType, &bid.AuthorId, &bid.Version, &bid.CreatedAt, &bid.Price, &currency, &bid.ValidUntil,
+		&decision, &bid.DecisionRankingId, &bid.DecisionRank, &bid.Sealed, &technical, &comment,
+		&bid.WithdrawnAt, &reason, &award, &canceled)
+	if err != nil {
+		return nil, err
+	}
+	bid.Currency = currency.String
+	bid.Decision = models.TypeDecision(decision.String)
+	bid.TechnicalResult = models.TechnicalResult(technical.String)
+	bid.TechnicalComment = comment.String
+	bid.WithdrawalReason = reason.String
+	bid.AwardStatus = models.AwardStatus(award.String)
+	bid.CanceledBy = models.CanceledBy(canceled.String)
+	return &bid, nil
+}
+
+func scanBids(rows *sql.Rows) ([]*models.BidResponse, error) {
+	var bids []*models.BidResponse
+	for rows.Next() {
+		bid, err := scanBid(rows)
+		if err != nil {
+			return nil, err
+		}
+		bids = append(bids, bid)
+	}
+	return bids, rows.Err()
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func tenderOrganization(tx *sql.Tx, tenderId uuid.UUID) (uuid.UUID, error) {
+	var organizationId uuid.UUID
+	err := tx.QueryRow(`SELECT organization_id FROM tender WHERE id = $1`, tenderId).Scan(&organizationId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, myErrors.ErrTenderNotFound
+		}
+		return uuid.Nil, myErrors.ErrBadRequest
+	}
+	return organizationId, nil
+}
+
+// bidEvent builds an event about the bid. While a two-envelope tender is
+// still in technical review the payload goes out without the financial
+// envelope, so prices do not leak through the stream and webhooks.
+func bidEvent(tx *sql.Tx, eventType models.EventType, bid *models.BidResponse, payload interface{}) (*models.Event, error) {
+	var (
+		organizationId uuid.UUID
+		serviceType    models.TypeService
+		phase          sql.NullString
+	)
+	err := tx.QueryRow(`SELECT organization_id, service_type, evaluation_phase FROM tender WHERE id = $1`, bid.TenderId).
+		Scan(&organizationId, &serviceType, &phase)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrTenderNotFound
+		}
+		return nil, myErrors.ErrBadRequest
+	}
+	if models.TwoEnvelope(serviceType) && !models.EvaluationPhase(phase.String).PricesVisible() {
+		payload = models.WithoutFinancial(payload)
+	}
+	return models.NewBidEvent(eventType, bid, organizationId, payload), nil
+}
+
+func commitBidEvent(tx *sql.Tx, eventType models.EventType, bid *models.BidResponse, payload interface{}) error {
+	event, err := bidEvent(tx, eventType, bid, payload)
+	if err != nil {
+		return err
+	}
+	return commitWithEvent(tx, event)
+}
+
+func commitWithEvent(tx *sql.Tx, event *models.Event) error {
+	if err := repoOutbox.Insert(tx, event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -1,22 +1,110 @@
 package usecase
 
 import (
+	"encoding/json"
+	"errors"
 	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"strings"
+	"time"
+	"unicode/utf8"
 	"zadanie-6105/internal/models"
 	"zadanie-6105/internal/myErrors"
 	"zadanie-6105/internal/pkg/bids"
+	"zadanie-6105/internal/pkg/clock"
+	"zadanie-6105/internal/pkg/tenders"
 )
 
+const maxWithdrawalReason = 1000
+
 type BidUsecase struct {
-	r bids.BidRepository
+	r            bids.BidRepository
+	tr           tenders.TenderRepoPostgres
+	ranker       bids.Ranker
+	prequalifier bids.Prequalifier
+	sealer       tenders.Sealer
+	clock        clock.Clock
 }
 
-func NewBidUsecase(r bids.BidRepository) *BidUsecase {
-	return &BidUsecase{r: r}
+func NewBidUsecase(r bids.BidRepository, tr tenders.TenderRepoPostgres, ranker bids.Ranker, prequalifier bids.Prequalifier,
+	sealer tenders.Sealer, clk clock.Clock) *BidUsecase {
+	return &BidUsecase{r: r, tr: tr, ranker: ranker, prequalifier: prequalifier, sealer: sealer, clock: clk}
 }
 
 func (u *BidUsecase) CreateNewBid(bidData *models.BidRequest) (*models.BidResponse, error) {
-	bid, err := u.r.CreateBid(bidData)
+	if bidData == nil {
+		return nil, myErrors.ErrBadRequest
+	}
+	tender, err := u.tr.SelectTender(bidData.TenderId)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Status == models.StatusCanceled {
+		return nil, myErrors.ErrTenderCanceled
+	}
+	if tender.Status != models.StatusPublished {
+		return nil, myErrors.ErrTenderNotPublished
+	}
+	// Submission ends once a two-envelope tender opens the financial envelopes.
+	if models.TwoEnvelope(tender.ServiceType) && tender.EvaluationPhase != models.PhaseTechnicalReview {
+		return nil, myErrors.ErrWrongPhase
+	}
+	if tender.Visibility == models.VisibilityInviteOnly {
+		ok, err := u.tr.CheckBidderInvited(tender.Id, bidData.AuthorType, bidData.AuthorId)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, myErrors.ErrNotInvited
+		}
+	}
+	if err = u.prequalifier.CheckPrequalified(tender, bidData.AuthorType, bidData.AuthorId); err != nil {
+		return nil, err
+	}
+	if err = u.checkSubmissionOpen(tender); err != nil {
+		return nil, err
+	}
+	if err = u.checkAuctionNotStarted(tender.Id); err != nil {
+		return nil, err
+	}
+	lots, err := u.tr.SelectTenderLots(tender.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !validLots(lots, bidData.LotIds) {
+		return nil, myErrors.ErrBadRequest
+	}
+	items, err := u.priceItems(tender.Id, bidData.Items, bidData.Items == nil)
+	if err != nil {
+		return nil, err
+	}
+	if items != nil {
+		if bidData.Price != nil {
+			return nil, myErrors.ErrBadRequest
+		}
+		bidData.Price = itemsTotal(items)
+	}
+	if bidData.Currency == "" && bidData.Price != nil {
+		bidData.Currency = tender.Currency
+	}
+	if err = u.checkPrice(tender, bidData.Price, bidData.Currency, bidData.ValidUntil); err != nil {
+		return nil, err
+	}
+	if tender.Sealed {
+		envelope, err := u.sealEnvelope(tender.Id, &models.SealedEnvelope{
+			Name:        bidData.Name,
+			Description: bidData.Description,
+			Price:       bidData.Price,
+			Items:       items,
+		})
+		if err != nil {
+			return nil, err
+		}
+		plain := *bidData
+		plain.Name, plain.Description, plain.Price, plain.Items = "", "", nil, nil
+		return u.r.CreateBid(&plain, nil, envelope)
+	}
+	bid, err := u.r.CreateBid(bidData, items, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -29,13 +117,69 @@ func (u *BidUsecase) GetUserBids(limit, offset int32, username string) ([]*model
 	}
 	return userBids, nil
 }
-func (u *BidUsecase) GetTenderBids(limit, offset int32, tenderId uuid.UUID, username string) ([]*models.BidResponse, error) {
-	tenderBids, err := u.r.SelectTenderBids(limit, offset, tenderId, username)
+func (u *BidUsecase) GetTenderBids(limit, offset int32, tenderId uuid.UUID, username string, sort models.BidSort) ([]*models.BidResponse, error) {
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if err = tenders.CheckVisible(u.tr, tender, username); err != nil {
+		return nil, err
+	}
+	tenderBids, err := u.r.SelectTenderBids(limit, offset, tenderId, username, sort)
 	if err != nil {
 		return nil, err
 	}
+	if models.TwoEnvelope(tender.ServiceType) {
+		if !tender.EvaluationPhase.PricesVisible() && sort != models.BidSortName {
+			return nil, myErrors.ErrWrongPhase
+		}
+		for _, bid := range tenderBids {
+			if !tender.EvaluationPhase.PricesVisible() || bid.TechnicalResult != models.TechnicalQualified {
+				bid.HideFinancial()
+			}
+		}
+	}
 	return tenderBids, nil
 }
+
+// GetBid returns the full bid to its author and to the tender's responsible
+// employees; the latter see prices only once the evaluation phase allows it.
+func (u *BidUsecase) GetBid(bidId uuid.UUID, username string) (*models.BidDetails, error) {
+	bid, err := u.r.SelectBidDetails(bidId)
+	if err != nil {
+		return nil, err
+	}
+	isAuthor, err := u.r.CheckBidAuthor(bidId, username)
+	if err != nil && !errors.Is(err, myErrors.ErrForbidden) {
+		return nil, err
+	}
+	hidePrices := false
+	if !isAuthor {
+		tender, err := u.responsibleTender(bid.TenderId, username)
+		if err != nil {
+			return nil, err
+		}
+		hidePrices = models.TwoEnvelope(tender.ServiceType) &&
+			(!tender.EvaluationPhase.PricesVisible() || bid.TechnicalResult != models.TechnicalQualified)
+	}
+	if bid.Tender.Organization, err = u.tr.SelectOrganization(bid.Tender.Organization.Id); err != nil {
+		return nil, err
+	}
+	if bid.Items, err = u.r.SelectBidItems(bidId); err != nil {
+		return nil, err
+	}
+	lots, err := u.r.SelectBidLots(bidId)
+	if err != nil {
+		return nil, err
+	}
+	for _, lot := range lots {
+		bid.LotIds = append(bid.LotIds, lot.LotId)
+	}
+	if hidePrices {
+		bid.HideFinancial()
+	}
+	return bid, nil
+}
 func (u *BidUsecase) GetBidStatus(bidId uuid.UUID, username string) (string, error) {
 	ok, err := u.r.CheckBidAuthor(bidId, username)
 	if err != nil {
@@ -58,13 +202,66 @@ func (u *BidUsecase) EditBidStatus(bidId uuid.UUID, username, status string) (*m
 	if !ok {
 		return nil, myErrors.ErrForbidden
 	}
+	if models.TypeStatus(status) == models.StatusCanceled {
+		return nil, myErrors.ErrBadRequest
+	}
+	current, err := u.r.SelectBid(bidId)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status == models.StatusCanceled {
+		return nil, canceledError(current)
+	}
+	tender, err := u.tr.SelectTender(current.TenderId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkSubmissionOpen(tender); err != nil {
+		return nil, err
+	}
 	bid, err := u.r.UpdateBidStatus(bidId, status)
 	if err != nil {
 		return nil, err
 	}
 	return bid, nil
 }
+
+// WithdrawBid lets the author pull the bid out until the submission deadline
+// (or the opening of sealed bids), provided no decision has been made on it.
+func (u *BidUsecase) WithdrawBid(bidId uuid.UUID, username string, withdrawal *models.WithdrawRequest) (*models.BidResponse, error) {
+	if withdrawal == nil {
+		withdrawal = &models.WithdrawRequest{}
+	}
+	if utf8.RuneCountInString(withdrawal.Reason) > maxWithdrawalReason {
+		return nil, myErrors.ErrBadRequest
+	}
+	ok, err := u.r.CheckBidAuthor(bidId, username)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrForbidden
+	}
+	bid, err := u.r.SelectBid(bidId)
+	if err != nil {
+		return nil, err
+	}
+	if bid.Status == models.StatusCanceled {
+		return nil, canceledError(bid)
+	}
+	tender, err := u.tr.SelectTender(bid.TenderId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkSubmissionOpen(tender); err != nil {
+		return nil, err
+	}
+	return u.r.WithdrawBid(bidId, strings.TrimSpace(withdrawal.Reason))
+}
 func (u *BidUsecase) EditBid(bidId uuid.UUID, username string, editedData *models.BidEditRequest) (*models.BidResponse, error) {
+	if editedData == nil {
+		return nil, myErrors.ErrBadRequest
+	}
 	ok, err := u.r.CheckBidAuthor(bidId, username)
 	if err != nil {
 		return nil, err
@@ -72,16 +269,706 @@ func (u *BidUsecase) EditBid(bidId uuid.UUID, username string, editedData *model
 	if !ok {
 		return nil, myErrors.ErrForbidden
 	}
-	bid, err := u.r.UpdateBid(bidId, editedData)
+	current, err := u.r.SelectBid(bidId)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status == models.StatusCanceled {
+		return nil, canceledError(current)
+	}
+	if current.TechnicalResult != "" {
+		return nil, myErrors.ErrWrongPhase
+	}
+	tender, err := u.tr.SelectTender(current.TenderId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkSubmissionOpen(tender); err != nil {
+		return nil, err
+	}
+	if current.Sealed {
+		return u.editSealedBid(tender, current, editedData)
+	}
+	var items []*models.BidItem
+	if editedData.Price != nil || editedData.Currency != "" || editedData.ValidUntil != nil || editedData.Items != nil {
+		if editedData.Price != nil || editedData.Currency != "" || editedData.Items != nil {
+			if err = u.checkAuctionNotStarted(tender.Id); err != nil {
+				return nil, err
+			}
+		}
+		if editedData.Items != nil {
+			if items, err = u.priceItems(tender.Id, editedData.Items, false); err != nil {
+				return nil, err
+			}
+		}
+		// The price follows the items only when the tender is itemized; an
+		// empty item list on a lump-sum tender leaves the price alone.
+		if items != nil {
+			if editedData.Price != nil {
+				return nil, myErrors.ErrBadRequest
+			}
+			editedData.Price = itemsTotal(items)
+		} else if editedData.Price != nil {
+			priced, err := u.r.SelectBidItems(bidId)
+			if err != nil {
+				return nil, err
+			}
+			if len(priced) > 0 {
+				return nil, myErrors.ErrBadRequest
+			}
+		}
+		price, currency, validUntil := current.Price, current.Currency, current.ValidUntil
+		if editedData.Price != nil {
+			price = editedData.Price
+		}
+		if editedData.Currency != "" {
+			currency = editedData.Currency
+		}
+		if currency == "" {
+			currency = tender.Currency
+			editedData.Currency = currency
+		}
+		if editedData.ValidUntil != nil {
+			validUntil = editedData.ValidUntil
+		}
+		if err = u.checkPrice(tender, price, currency, validUntil); err != nil {
+			return nil, err
+		}
+	}
+	bid, err := u.r.UpdateBid(bidId, editedData, items, nil)
 	if err != nil {
 		return nil, err
 	}
 	return bid, nil
 }
 func (u *BidUsecase) SubmitDecision(bidId uuid.UUID, username string, decision string) (*models.BidResponse, error) {
-	bid, err := u.r.SubmitDecision(bidId, decision, username)
+	current, err := u.r.SelectBid(bidId)
+	if err != nil {
+		return nil, err
+	}
+	if current.Sealed {
+		return nil, myErrors.ErrBidsSealed
+	}
+	if current.Status == models.StatusCanceled {
+		return nil, canceledError(current)
+	}
+	tender, err := u.tr.SelectTender(current.TenderId)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkDecision(tender, current, models.TypeDecision(decision)); err != nil {
+		return nil, err
+	}
+	if current.Decision != "" {
+		return nil, myErrors.ErrBidDecided
+	}
+	if current.Status != models.StatusPublished {
+		return nil, myErrors.ErrBadRequest
+	}
+	if models.TypeDecision(decision) == models.DecisionApproved && !tender.Status.Awardable() {
+		return nil, myErrors.ErrTenderNotAwardable
+	}
+	lots, err := u.tr.SelectTenderLots(tender.Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(lots) > 0 {
+		return nil, myErrors.ErrLotDecisionRequired
+	}
+	ranking, err := u.ranker.TenderRanking(current.TenderId)
+	if err != nil {
+		return nil, err
+	}
+	bid, err := u.r.SubmitDecision(bidId, decision, username, ranking)
 	if err != nil {
 		return nil, err
 	}
 	return bid, nil
 }
+func (u *BidUsecase) GetBidItems(bidId uuid.UUID, username string) ([]*models.BidItem, error) {
+	ok, err := u.r.CheckBidAuthor(bidId, username)
+	if err != nil && !errors.Is(err, myErrors.ErrForbidden) {
+		return nil, err
+	}
+	if !ok {
+		bid, err := u.r.SelectBid(bidId)
+		if err != nil {
+			return nil, err
+		}
+		tender, err := u.tr.SelectTender(bid.TenderId)
+		if err != nil {
+			return nil, err
+		}
+		isResponsible, err := u.tr.CheckUsernameOrganization(username, tender.OrganizationId)
+		if err != nil {
+			return nil, err
+		}
+		if !isResponsible {
+			return nil, myErrors.ErrForbidden
+		}
+		if models.TwoEnvelope(tender.ServiceType) &&
+			(!tender.EvaluationPhase.PricesVisible() || bid.TechnicalResult != models.TechnicalQualified) {
+			return nil, myErrors.ErrWrongPhase
+		}
+	}
+	items, err := u.r.SelectBidItems(bidId)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (u *BidUsecase) GetBidLots(bidId uuid.UUID, username string) ([]*models.BidLot, error) {
+	ok, err := u.r.CheckBidAuthor(bidId, username)
+	if err != nil && !errors.Is(err, myErrors.ErrForbidden) {
+		return nil, err
+	}
+	if !ok {
+		bid, err := u.r.SelectBid(bidId)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = u.responsibleTender(bid.TenderId, username); err != nil {
+			return nil, err
+		}
+	}
+	lots, err := u.r.SelectBidLots(bidId)
+	if err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+func (u *BidUsecase) SubmitLotDecision(bidId, lotId uuid.UUID, username string, decision string) (*models.Lot, error) {
+	typed := models.TypeDecision(decision)
+	if typed != models.DecisionApproved && typed != models.DecisionRejected {
+		return nil, myErrors.ErrBadRequest
+	}
+	bid, err := u.r.SelectBid(bidId)
+	if err != nil {
+		return nil, err
+	}
+	if bid.Sealed {
+		return nil, myErrors.ErrBidsSealed
+	}
+	tender, err := u.responsibleTender(bid.TenderId, username)
+	if err != nil {
+		return nil, err
+	}
+	if bid.Status != models.StatusPublished {
+		return nil, myErrors.ErrBadRequest
+	}
+	if err = checkDecision(tender, bid, typed); err != nil {
+		return nil, err
+	}
+	lot, err := u.tr.DecideLot(lotId, bidId, typed, username)
+	if err != nil {
+		return nil, err
+	}
+	return lot, nil
+}
+
+func (u *BidUsecase) OpenSealedBids(now time.Time) ([]*models.OpeningProtocol, error) {
+	tenderIds, err := u.r.SelectTendersToOpen(now)
+	if err != nil {
+		return nil, err
+	}
+	if len(tenderIds) > 0 && u.sealer == nil {
+		return nil, myErrors.ErrSealingDisabled
+	}
+	var (
+		opened []*models.OpeningProtocol
+		errs   []error
+	)
+	for _, tenderId := range tenderIds {
+		key, err := u.tr.SelectTenderKey(tenderId)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		protocol, err := u.r.OpenSealedBids(tenderId, now, func(ciphertext []byte) (*models.SealedEnvelope, error) {
+			return u.openEnvelope(key, tenderId, ciphertext)
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if protocol != nil {
+			opened = append(opened, protocol)
+		}
+	}
+	return opened, errors.Join(errs...)
+}
+
+// GetOpeningProtocol shows the protocol to the organiser and to the bidders.
+func (u *BidUsecase) GetOpeningProtocol(tenderId uuid.UUID, username string) (*models.OpeningProtocol, error) {
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if !tender.Sealed {
+		return nil, myErrors.ErrProtocolNotFound
+	}
+	if err = tenders.CheckVisible(u.tr, tender, username); err != nil {
+		return nil, err
+	}
+	ok, err := u.tr.CheckUsernameOrganization(username, tender.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if ok, err = u.r.CheckTenderBidder(tenderId, username); err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, myErrors.ErrForbidden
+		}
+	}
+	if tender.OpenedAt == nil {
+		return nil, myErrors.ErrBidsSealed
+	}
+	protocol, err := u.r.SelectOpeningProtocol(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	return protocol, nil
+}
+
+func (u *BidUsecase) ReviewTechnical(bidId uuid.UUID, username string, review *models.TechnicalReviewRequest) (*models.BidResponse, error) {
+	if review == nil || !review.Result.IsValid() {
+		return nil, myErrors.ErrBadRequest
+	}
+	bid, err := u.r.SelectBid(bidId)
+	if err != nil {
+		return nil, err
+	}
+	tender, err := u.twoEnvelopeTender(bid.TenderId, username)
+	if err != nil {
+		return nil, err
+	}
+	if tender.EvaluationPhase != models.PhaseTechnicalReview {
+		return nil, myErrors.ErrWrongPhase
+	}
+	if bid.Status != models.StatusPublished {
+		return nil, myErrors.ErrBadRequest
+	}
+	reviewed, err := u.r.ReviewTechnical(bidId, username, review)
+	if err != nil {
+		return nil, err
+	}
+	reviewed.HideFinancial()
+	return reviewed, nil
+}
+func (u *BidUsecase) GetTechnicalEnvelopes(limit, offset int32, tenderId uuid.UUID, username string) ([]*models.TechnicalEnvelope, error) {
+	if _, err := u.twoEnvelopeTender(tenderId, username); err != nil {
+		return nil, err
+	}
+	tenderBids, err := u.r.SelectTenderBids(limit, offset, tenderId, username, models.BidSortName)
+	if err != nil {
+		return nil, err
+	}
+	envelopes := make([]*models.TechnicalEnvelope, 0, len(tenderBids))
+	for _, bid := range tenderBids {
+		envelopes = append(envelopes, bid.TechnicalEnvelope())
+	}
+	return envelopes, nil
+}
+
+// GetFinancialEnvelopes returns prices of technically qualified bids, cheapest
+// first; they are available only after the financial opening.
+func (u *BidUsecase) GetFinancialEnvelopes(limit, offset int32, tenderId uuid.UUID, username string) ([]*models.FinancialEnvelope, error) {
+	tender, err := u.twoEnvelopeTender(tenderId, username)
+	if err != nil {
+		return nil, err
+	}
+	if !tender.EvaluationPhase.PricesVisible() {
+		return nil, myErrors.ErrWrongPhase
+	}
+	qualified, err := u.r.SelectQualifiedBids(limit, offset, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	envelopes := make([]*models.FinancialEnvelope, 0, len(qualified))
+	for _, bid := range qualified {
+		if bid.Items, err = u.r.SelectBidItems(bid.Id); err != nil {
+			return nil, err
+		}
+		envelopes = append(envelopes, bid.FinancialEnvelope())
+	}
+	return envelopes, nil
+}
+func (u *BidUsecase) AdvancePhase(tenderId uuid.UUID, username string, phase models.EvaluationPhase) (*models.TendersResponse, error) {
+	tender, err := u.twoEnvelopeTender(tenderId, username)
+	if err != nil {
+		return nil, err
+	}
+	if phase == "" || phase != tender.EvaluationPhase.Next() {
+		return nil, myErrors.ErrWrongPhase
+	}
+	if err = u.r.AdvancePhase(tenderId, tender.EvaluationPhase, phase); err != nil {
+		return nil, err
+	}
+	return u.tr.SelectTender(tenderId)
+}
+
+func (u *BidUsecase) PlaceAuctionPrice(bidId uuid.UUID, username string, price *models.AuctionPriceRequest) (*models.AuctionState, error) {
+	if price == nil || !models.ValidAmount(&price.Price) {
+		return nil, myErrors.ErrBadRequest
+	}
+	ok, err := u.r.CheckBidAuthor(bidId, username)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrForbidden
+	}
+	bid, err := u.r.SelectBid(bidId)
+	if err != nil {
+		return nil, err
+	}
+	tender, err := u.tr.SelectTender(bid.TenderId)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Status == models.StatusCanceled {
+		return nil, myErrors.ErrTenderCanceled
+	}
+	if tender.Status != models.StatusPublished {
+		return nil, myErrors.ErrAuctionNotActive
+	}
+	currency := bid.Currency
+	if currency == "" {
+		currency = tender.Currency
+	}
+	if err = u.checkPrice(tender, &price.Price, currency, bid.ValidUntil); err != nil {
+		return nil, err
+	}
+	_, auction, err := u.r.PlaceAuctionPrice(bidId, price.Price, u.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	return u.auctionState(auction, username, false)
+}
+func (u *BidUsecase) GetAuction(tenderId uuid.UUID, username string) (*models.AuctionState, error) {
+	auction, err := u.tr.SelectAuction(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if err = tenders.CheckVisible(u.tr, tender, username); err != nil {
+		return nil, err
+	}
+	isResponsible, err := u.tr.CheckUsernameOrganization(username, tender.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+	return u.auctionState(auction, username, isResponsible)
+}
+
+// auctionState shows the current ranks. Bidders see only their own bid ids;
+// anyone who is neither a bidder nor responsible for the tender is refused.
+func (u *BidUsecase) auctionState(auction *models.Auction, username string, isResponsible bool) (*models.AuctionState, error) {
+	ranks, err := u.r.SelectAuctionRanks(auction.TenderId, username)
+	if err != nil {
+		return nil, err
+	}
+	isBidder := false
+	for _, rank := range ranks {
+		isBidder = isBidder || rank.Own
+		if !isResponsible && !rank.Own {
+			rank.BidId = nil
+		}
+	}
+	if !isResponsible && !isBidder {
+		return nil, myErrors.ErrForbidden
+	}
+	if ranks == nil {
+		ranks = []*models.AuctionRank{}
+	}
+	return &models.AuctionState{Auction: auction, Status: auction.Status(u.clock.Now()), Ranks: ranks}, nil
+}
+
+// checkAuctionNotStarted rejects price changes outside the auction once the
+// tender's reverse auction has started.
+func (u *BidUsecase) checkAuctionNotStarted(tenderId uuid.UUID) error {
+	auction, err := u.tr.SelectAuction(tenderId)
+	if err != nil {
+		if errors.Is(err, myErrors.ErrAuctionNotFound) {
+			return nil
+		}
+		return err
+	}
+	if auction.Status(u.clock.Now()) != models.AuctionScheduled {
+		return myErrors.ErrAuctionStarted
+	}
+	return nil
+}
+
+// checkDecision enforces the two-envelope rules: decisions are made only at
+// the award phase and only technically qualified bids can be approved.
+func checkDecision(tender *models.TendersResponse, bid *models.BidResponse, decision models.TypeDecision) error {
+	if !models.TwoEnvelope(tender.ServiceType) {
+		return nil
+	}
+	if tender.EvaluationPhase != models.PhaseAward {
+		return myErrors.ErrWrongPhase
+	}
+	if decision == models.DecisionApproved && bid.TechnicalResult != models.TechnicalQualified {
+		return myErrors.ErrBadRequest
+	}
+	return nil
+}
+
+// canceledError explains why a cancelled bid can no longer change: it was
+// withdrawn by its author or closed together with the tender.
+func canceledError(bid *models.BidResponse) error {
+	if bid.CanceledBy == models.CanceledByTender {
+		return myErrors.ErrTenderCanceled
+	}
+	return myErrors.ErrBidCanceled
+}
+
+// validLots requires a bid on a tender with lots to target one or more
+// distinct open lots of that tender.
+func validLots(lots []*models.Lot, lotIds []uuid.UUID) bool {
+	if len(lots) == 0 {
+		return len(lotIds) == 0
+	}
+	if len(lotIds) == 0 {
+		return false
+	}
+	open := make(map[uuid.UUID]bool, len(lots))
+	for _, lot := range lots {
+		open[lot.Id] = lot.Status == models.LotOpen
+	}
+	seen := make(map[uuid.UUID]bool, len(lotIds))
+	for _, lotId := range lotIds {
+		if !open[lotId] || seen[lotId] {
+			return false
+		}
+		seen[lotId] = true
+	}
+	return true
+}
+
+func (u *BidUsecase) responsibleTender(tenderId uuid.UUID, username string) (*models.TendersResponse, error) {
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := u.tr.CheckUsernameOrganization(username, tender.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrForbidden
+	}
+	return tender, nil
+}
+
+// twoEnvelopeTender loads a tender evaluated in two envelopes and checks that
+// username is responsible for its organization.
+func (u *BidUsecase) twoEnvelopeTender(tenderId uuid.UUID, username string) (*models.TendersResponse, error) {
+	tender, err := u.responsibleTender(tenderId, username)
+	if err != nil {
+		return nil, err
+	}
+	if !models.TwoEnvelope(tender.ServiceType) {
+		return nil, myErrors.ErrBadRequest
+	}
+	return tender, nil
+}
+
+// editSealedBid applies the edit to the decrypted envelope and seals it again;
+// only currency and validity are stored in the clear.
+func (u *BidUsecase) editSealedBid(tender *models.TendersResponse, current *models.BidResponse,
+	editedData *models.BidEditRequest) (*models.BidResponse, error) {
+	if u.sealer == nil {
+		return nil, myErrors.ErrSealingDisabled
+	}
+	key, err := u.tr.SelectTenderKey(tender.Id)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := u.r.SelectBidEnvelope(current.Id)
+	if err != nil {
+		return nil, err
+	}
+	envelope, err := u.openEnvelope(key, tender.Id, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	if editedData.Name != "" {
+		envelope.Name = editedData.Name
+	}
+	if editedData.Description != "" {
+		envelope.Description = editedData.Description
+	}
+	if editedData.Items != nil {
+		if editedData.Price != nil {
+			return nil, myErrors.ErrBadRequest
+		}
+		if envelope.Items, err = u.priceItems(tender.Id, editedData.Items, false); err != nil {
+			return nil, err
+		}
+		envelope.Price = itemsTotal(envelope.Items)
+	} else if editedData.Price != nil {
+		if len(envelope.Items) > 0 {
+			return nil, myErrors.ErrBadRequest
+		}
+		envelope.Price = editedData.Price
+	}
+
+	plain := &models.BidEditRequest{Currency: editedData.Currency, ValidUntil: editedData.ValidUntil}
+	currency, validUntil := current.Currency, current.ValidUntil
+	if plain.Currency != "" {
+		currency = plain.Currency
+	}
+	if currency == "" && envelope.Price != nil {
+		currency = tender.Currency
+		plain.Currency = currency
+	}
+	if plain.ValidUntil != nil {
+		validUntil = plain.ValidUntil
+	}
+	if err = u.checkPrice(tender, envelope.Price, currency, validUntil); err != nil {
+		return nil, err
+	}
+
+	sealed, err := u.seal(key, tender.Id, envelope)
+	if err != nil {
+		return nil, err
+	}
+	return u.r.UpdateBid(current.Id, plain, nil, sealed)
+}
+
+// checkSubmissionOpen rejects changes to bids once the submission deadline has
+// passed or, for sealed tenders, once the bids are due to be opened.
+func (u *BidUsecase) checkSubmissionOpen(tender *models.TendersResponse) error {
+	if tender.SubmissionDeadline != nil && !u.clock.Now().Before(*tender.SubmissionDeadline) {
+		return myErrors.ErrDeadlinePassed
+	}
+	if tender.Sealed && !u.beforeOpening(tender) {
+		return myErrors.ErrDeadlinePassed
+	}
+	return nil
+}
+
+func (u *BidUsecase) beforeOpening(tender *models.TendersResponse) bool {
+	return tender.OpenedAt == nil && tender.OpeningAt != nil && u.clock.Now().Before(*tender.OpeningAt)
+}
+
+func (u *BidUsecase) sealEnvelope(tenderId uuid.UUID, envelope *models.SealedEnvelope) ([]byte, error) {
+	if u.sealer == nil {
+		return nil, myErrors.ErrSealingDisabled
+	}
+	key, err := u.tr.SelectTenderKey(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	return u.seal(key, tenderId, envelope)
+}
+
+func (u *BidUsecase) seal(key []byte, tenderId uuid.UUID, envelope *models.SealedEnvelope) ([]byte, error) {
+	plaintext, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	return u.sealer.Seal(key, tenderId, plaintext)
+}
+
+func (u *BidUsecase) openEnvelope(key []byte, tenderId uuid.UUID, ciphertext []byte) (*models.SealedEnvelope, error) {
+	plaintext, err := u.sealer.Open(key, tenderId, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	var envelope models.SealedEnvelope
+	if err = json.Unmarshal(plaintext, &envelope); err != nil {
+		return nil, err
+	}
+	return &envelope, nil
+}
+
+// priceItems matches requested unit prices against the tender items and
+// computes line totals. It returns nil when the bid is not priced per item.
+func (u *BidUsecase) priceItems(tenderId uuid.UUID, requested []models.BidItemRequest, omitted bool) ([]*models.BidItem, error) {
+	tenderItems, err := u.tr.SelectTenderItems(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if len(tenderItems) == 0 {
+		if len(requested) > 0 {
+			return nil, myErrors.ErrBadRequest
+		}
+		return nil, nil
+	}
+
+	prices := make(map[uuid.UUID]decimal.Decimal, len(requested))
+	for _, item := range requested {
+		if _, duplicate := prices[item.ItemId]; duplicate || !models.ValidAmount(&item.UnitPrice) {
+			return nil, myErrors.ErrBadRequest
+		}
+		prices[item.ItemId] = item.UnitPrice
+	}
+
+	var priced []*models.BidItem
+	for _, tenderItem := range tenderItems {
+		unitPrice, ok := prices[tenderItem.Id]
+		if !ok {
+			if tenderItem.Mandatory {
+				return nil, myErrors.ErrMandatoryItemMissing
+			}
+			continue
+		}
+		delete(prices, tenderItem.Id)
+		priced = append(priced, &models.BidItem{
+			ItemId:    tenderItem.Id,
+			Name:      tenderItem.Name,
+			Quantity:  tenderItem.Quantity,
+			Unit:      tenderItem.Unit,
+			UnitPrice: unitPrice,
+			Total:     tenderItem.Quantity.Mul(unitPrice).Round(models.MoneyScale),
+		})
+	}
+	if len(prices) > 0 {
+		return nil, myErrors.ErrBadRequest
+	}
+	if omitted && len(priced) == 0 {
+		return nil, nil
+	}
+	if len(priced) == 0 {
+		return nil, myErrors.ErrBadRequest
+	}
+	return priced, nil
+}
+
+func itemsTotal(items []*models.BidItem) *decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range items {
+		total = total.Add(item.Total)
+	}
+	return &total
+}
+
+// checkPrice compares amounts only in the tender's currency; there is no conversion.
+func (u *BidUsecase) checkPrice(tender *models.TendersResponse, price *decimal.Decimal, currency string, validUntil *time.Time) error {
+	if validUntil != nil && !validUntil.After(u.clock.Now()) {
+		return myErrors.ErrBadRequest
+	}
+	if price == nil {
+		if tender.MaxPrice != nil {
+			return myErrors.ErrBadRequest
+		}
+		return nil
+	}
+	if !models.ValidAmount(price) || currency != tender.Currency {
+		return myErrors.ErrBadRequest
+	}
+	if tender.MaxPrice != nil && price.GreaterThan(*tender.MaxPrice) {
+		return myErrors.ErrPriceAboveMax
+	}
+	return nil
+}
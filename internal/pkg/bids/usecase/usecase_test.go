@@ -0,0 +1,357 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/bids"
+	"zadanie-6105/internal/pkg/clock"
+	"zadanie-6105/internal/pkg/tenders"
+)
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeTenders and fakeBids implement just enough of the repositories for the
+// tests; any other call panics on the embedded nil interface.
+type fakeTenders struct {
+	tenders.TenderRepoPostgres
+	tender *models.TendersResponse
+	items  []*models.TenderItem
+}
+
+func (r *fakeTenders) SelectTender(uuid.UUID) (*models.TendersResponse, error) {
+	return r.tender, nil
+}
+
+func (r *fakeTenders) SelectTenderItems(uuid.UUID) ([]*models.TenderItem, error) {
+	return r.items, nil
+}
+
+func (r *fakeTenders) SelectAuction(uuid.UUID) (*models.Auction, error) {
+	return nil, myErrors.ErrAuctionNotFound
+}
+
+func (r *fakeTenders) SelectTenderLots(uuid.UUID) ([]*models.Lot, error) {
+	return nil, nil
+}
+
+func (r *fakeTenders) CheckUsernameOrganization(string, uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+func (r *fakeTenders) DecideLot(lotId, _ uuid.UUID, _ models.TypeDecision, _ string) (*models.Lot, error) {
+	return &models.Lot{Id: lotId, TenderId: r.tender.Id, Status: models.LotAwarded}, nil
+}
+
+type fakeBids struct {
+	bids.BidRepository
+	bid     *models.BidResponse
+	edited  *models.BidEditRequest
+	updated []*models.BidItem
+}
+
+func (r *fakeBids) CheckBidAuthor(uuid.UUID, string) (bool, error) {
+	return true, nil
+}
+
+func (r *fakeBids) SelectBid(uuid.UUID) (*models.BidResponse, error) {
+	return r.bid, nil
+}
+
+func (r *fakeBids) SelectBidItems(uuid.UUID) ([]*models.BidItem, error) {
+	return nil, nil
+}
+
+func (r *fakeBids) UpdateBidStatus(_ uuid.UUID, status string) (*models.BidResponse, error) {
+	r.bid.Status = models.TypeStatus(status)
+	return r.bid, nil
+}
+
+func (r *fakeBids) SubmitDecision(uuid.UUID, string, string, *models.Ranking) (*models.BidResponse, error) {
+	r.bid.Decision, r.bid.AwardStatus = models.DecisionApproved, models.AwardStatusAwarded
+	return r.bid, nil
+}
+
+func (r *fakeBids) UpdateBid(_ uuid.UUID, editedData *models.BidEditRequest, items []*models.BidItem, _ []byte) (*models.BidResponse, error) {
+	r.edited, r.updated = editedData, items
+	return r.bid, nil
+}
+
+type fakeRanker struct{}
+
+func (fakeRanker) TenderRanking(uuid.UUID) (*models.Ranking, error) {
+	return nil, nil
+}
+
+// fakePrequalifier approves only the listed authors.
+type fakePrequalifier map[uuid.UUID]bool
+
+func (p fakePrequalifier) CheckPrequalified(_ *models.TendersResponse, _ models.TypeAuthor, authorId uuid.UUID) error {
+	if !p[authorId] {
+		return myErrors.ErrNotPrequalified
+	}
+	return nil
+}
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func decPtr(s string) *decimal.Decimal {
+	d := dec(s)
+	return &d
+}
+
+func newTestUsecase(br *fakeBids, tr *fakeTenders) *BidUsecase {
+	return NewBidUsecase(br, tr, nil, nil, nil, clock.NewFake(now))
+}
+
+func TestPriceItems(t *testing.T) {
+	cement := &models.TenderItem{Id: uuid.NewV4(), Name: "cement", Quantity: dec("2.5"), Unit: "t", Mandatory: true}
+	sand := &models.TenderItem{Id: uuid.NewV4(), Name: "sand", Quantity: dec("3"), Unit: "t"}
+	u := newTestUsecase(&fakeBids{}, &fakeTenders{items: []*models.TenderItem{cement, sand}})
+
+	for _, tc := range []struct {
+		name      string
+		requested []models.BidItemRequest
+		omitted   bool
+		total     string
+		err       error
+	}{
+		{
+			name:      "all items",
+			requested: []models.BidItemRequest{{ItemId: cement.Id, UnitPrice: dec("100.33")}, {ItemId: sand.Id, UnitPrice: dec("10")}},
+			total:     "280.825",
+		},
+		{
+			name:      "optional item skipped",
+			requested: []models.BidItemRequest{{ItemId: cement.Id, UnitPrice: dec("100")}},
+			total:     "250",
+		},
+		{
+			name:      "mandatory item missing",
+			requested: []models.BidItemRequest{{ItemId: sand.Id, UnitPrice: dec("10")}},
+			err:       myErrors.ErrMandatoryItemMissing,
+		},
+		{
+			name:      "unknown item",
+			requested: []models.BidItemRequest{{ItemId: cement.Id, UnitPrice: dec("1")}, {ItemId: uuid.NewV4(), UnitPrice: dec("1")}},
+			err:       myErrors.ErrBadRequest,
+		},
+		{
+			name:      "duplicate item",
+			requested: []models.BidItemRequest{{ItemId: cement.Id, UnitPrice: dec("1")}, {ItemId: cement.Id, UnitPrice: dec("2")}},
+			err:       myErrors.ErrBadRequest,
+		},
+		{
+			name:      "zero price",
+			requested: []models.BidItemRequest{{ItemId: cement.Id, UnitPrice: dec("0")}},
+			err:       myErrors.ErrBadRequest,
+		},
+		{
+			name: "nothing priced",
+			err:  myErrors.ErrMandatoryItemMissing,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			items, err := u.priceItems(uuid.Nil, tc.requested, tc.omitted)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if total := itemsTotal(items); !total.Equal(dec(tc.total)) {
+				t.Fatalf("total = %s, want %s", total, tc.total)
+			}
+		})
+	}
+}
+
+func TestPriceItemsWithoutTenderItems(t *testing.T) {
+	u := newTestUsecase(&fakeBids{}, &fakeTenders{})
+	if items, err := u.priceItems(uuid.Nil, nil, false); items != nil || err != nil {
+		t.Fatalf("items = %v, err = %v", items, err)
+	}
+	if _, err := u.priceItems(uuid.Nil, []models.BidItemRequest{{ItemId: uuid.NewV4(), UnitPrice: dec("1")}}, false); !errors.Is(err, myErrors.ErrBadRequest) {
+		t.Fatalf("err = %v, want %v", err, myErrors.ErrBadRequest)
+	}
+}
+
+func TestItemsTotal(t *testing.T) {
+	if total := itemsTotal(nil); !total.IsZero() {
+		t.Fatalf("empty total = %s", total)
+	}
+	items := []*models.BidItem{{Total: dec("1.25")}, {Total: dec("2.5")}, {Total: dec("0.0001")}}
+	if total := itemsTotal(items); !total.Equal(dec("3.7501")) {
+		t.Fatalf("total = %s", total)
+	}
+}
+
+func TestEditBidKeepsPriceOnLumpSumTender(t *testing.T) {
+	tender := &models.TendersResponse{Id: uuid.NewV4(), Status: models.StatusPublished, Currency: "RUB"}
+	br := &fakeBids{bid: &models.BidResponse{Id: uuid.NewV4(), TenderId: tender.Id, Status: models.StatusCreated,
+		Price: decPtr("500"), Currency: "RUB"}}
+	u := newTestUsecase(br, &fakeTenders{tender: tender})
+
+	_, err := u.EditBid(br.bid.Id, "user", &models.BidEditRequest{Price: decPtr("450"), Items: []models.BidItemRequest{}})
+	if err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if br.edited.Price == nil || !br.edited.Price.Equal(dec("450")) || br.updated != nil {
+		t.Fatalf("price = %v, items = %v", br.edited.Price, br.updated)
+	}
+}
+
+func TestCreateNewBidAfterTechnicalReview(t *testing.T) {
+	tender := &models.TendersResponse{Id: uuid.NewV4(), Status: models.StatusPublished,
+		ServiceType: models.ServiceTypeConstruction, EvaluationPhase: models.PhaseFinancialOpening}
+	u := newTestUsecase(&fakeBids{}, &fakeTenders{tender: tender})
+	if _, err := u.CreateNewBid(&models.BidRequest{TenderId: tender.Id}); !errors.Is(err, myErrors.ErrWrongPhase) {
+		t.Fatalf("err = %v, want %v", err, myErrors.ErrWrongPhase)
+	}
+}
+
+func TestCreateNewBidWithoutPrequalification(t *testing.T) {
+	tender := &models.TendersResponse{Id: uuid.NewV4(), Status: models.StatusPublished,
+		ServiceType: models.ServiceTypeConstruction, EvaluationPhase: models.PhaseTechnicalReview}
+	u := NewBidUsecase(&fakeBids{}, &fakeTenders{tender: tender}, nil, fakePrequalifier{}, nil, clock.NewFake(now))
+
+	_, err := u.CreateNewBid(&models.BidRequest{TenderId: tender.Id, AuthorType: models.User, AuthorId: uuid.NewV4()})
+	if !errors.Is(err, myErrors.ErrNotPrequalified) {
+		t.Fatalf("err = %v, want %v", err, myErrors.ErrNotPrequalified)
+	}
+}
+
+func TestPlaceAuctionPriceAboveMax(t *testing.T) {
+	tender := &models.TendersResponse{Id: uuid.NewV4(), Status: models.StatusPublished, Currency: "RUB", MaxPrice: decPtr("1000")}
+	br := &fakeBids{bid: &models.BidResponse{Id: uuid.NewV4(), TenderId: tender.Id, Status: models.StatusPublished}}
+	u := newTestUsecase(br, &fakeTenders{tender: tender})
+
+	_, err := u.PlaceAuctionPrice(br.bid.Id, "user", &models.AuctionPriceRequest{Price: dec("1500")})
+	if !errors.Is(err, myErrors.ErrPriceAboveMax) {
+		t.Fatalf("err = %v, want %v", err, myErrors.ErrPriceAboveMax)
+	}
+}
+
+func TestWithdrawBidOfCanceledTender(t *testing.T) {
+	for _, tc := range []struct {
+		canceledBy models.CanceledBy
+		err        error
+	}{
+		{models.CanceledByAuthor, myErrors.ErrBidCanceled},
+		{models.CanceledByTender, myErrors.ErrTenderCanceled},
+	} {
+		br := &fakeBids{bid: &models.BidResponse{Id: uuid.NewV4(), Status: models.StatusCanceled, CanceledBy: tc.canceledBy}}
+		u := newTestUsecase(br, &fakeTenders{})
+		if _, err := u.WithdrawBid(br.bid.Id, "user", nil); !errors.Is(err, tc.err) {
+			t.Errorf("%s: err = %v, want %v", tc.canceledBy, err, tc.err)
+		}
+	}
+}
+
+func TestCreateNewBidOnUnpublishedTender(t *testing.T) {
+	for status, want := range map[models.TypeStatus]error{
+		models.StatusCreated:  myErrors.ErrTenderNotPublished,
+		models.StatusClosed:   myErrors.ErrTenderNotPublished,
+		models.StatusCanceled: myErrors.ErrTenderCanceled,
+	} {
+		u := newTestUsecase(&fakeBids{}, &fakeTenders{tender: &models.TendersResponse{Id: uuid.NewV4(), Status: status}})
+		if _, err := u.CreateNewBid(&models.BidRequest{}); !errors.Is(err, want) {
+			t.Errorf("%s: err = %v, want %v", status, err, want)
+		}
+	}
+}
+
+func TestSubmitDecisionTwice(t *testing.T) {
+	tender := &models.TendersResponse{Id: uuid.NewV4(), Status: models.StatusPublished}
+	br := &fakeBids{bid: &models.BidResponse{Id: uuid.NewV4(), TenderId: tender.Id, Status: models.StatusPublished,
+		Decision: models.DecisionRejected}}
+	u := newTestUsecase(br, &fakeTenders{tender: tender})
+	if _, err := u.SubmitDecision(br.bid.Id, "user", string(models.DecisionApproved)); !errors.Is(err, myErrors.ErrBidDecided) {
+		t.Fatalf("err = %v, want %v", err, myErrors.ErrBidDecided)
+	}
+}
+
+func TestAwardAfterSubmissionDeadline(t *testing.T) {
+	// The deadline job has closed the published tender by the time the
+	// organization picks the winner.
+	tender := &models.TendersResponse{Id: uuid.NewV4(), Status: models.StatusClosed, SubmissionDeadline: &now}
+	clk := clock.NewFake(now)
+	clk.Advance(time.Hour)
+	br := &fakeBids{bid: &models.BidResponse{Id: uuid.NewV4(), TenderId: tender.Id, Status: models.StatusPublished}}
+	u := NewBidUsecase(br, &fakeTenders{tender: tender}, fakeRanker{}, nil, nil, clk)
+
+	bid, err := u.SubmitDecision(br.bid.Id, "user", string(models.DecisionApproved))
+	if err != nil || bid.AwardStatus != models.AwardStatusAwarded {
+		t.Fatalf("bid = %v, err = %v", bid, err)
+	}
+	lot, err := u.SubmitLotDecision(br.bid.Id, uuid.NewV4(), "user", string(models.DecisionApproved))
+	if err != nil || lot.Status != models.LotAwarded {
+		t.Fatalf("lot = %v, err = %v", lot, err)
+	}
+}
+
+func TestAwardOnCanceledTender(t *testing.T) {
+	tender := &models.TendersResponse{Id: uuid.NewV4(), Status: models.StatusCanceled}
+	br := &fakeBids{bid: &models.BidResponse{Id: uuid.NewV4(), TenderId: tender.Id, Status: models.StatusPublished}}
+	u := NewBidUsecase(br, &fakeTenders{tender: tender}, fakeRanker{}, nil, nil, clock.NewFake(now))
+	if _, err := u.SubmitDecision(br.bid.Id, "user", string(models.DecisionApproved)); !errors.Is(err, myErrors.ErrTenderNotAwardable) {
+		t.Fatalf("err = %v, want %v", err, myErrors.ErrTenderNotAwardable)
+	}
+}
+
+func TestEditBidAfterSubmissionCloses(t *testing.T) {
+	past, future := now.Add(-time.Minute), now.Add(time.Hour)
+	for _, tc := range []struct {
+		name   string
+		tender *models.TendersResponse
+	}{
+		{"deadline passed", &models.TendersResponse{SubmissionDeadline: &past}},
+		{"deadline now", &models.TendersResponse{SubmissionDeadline: &now}},
+		{"sealed bids opened", &models.TendersResponse{SubmissionDeadline: &future, Sealed: true, OpeningAt: &future, OpenedAt: &past}},
+		{"sealed opening due", &models.TendersResponse{Sealed: true, OpeningAt: &past}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.tender.Id, tc.tender.Status, tc.tender.Currency = uuid.NewV4(), models.StatusPublished, "RUB"
+			br := &fakeBids{bid: &models.BidResponse{Id: uuid.NewV4(), TenderId: tc.tender.Id, Status: models.StatusCreated,
+				Price: decPtr("500"), Currency: "RUB"}}
+			u := newTestUsecase(br, &fakeTenders{tender: tc.tender})
+
+			_, err := u.EditBid(br.bid.Id, "user", &models.BidEditRequest{Price: decPtr("450")})
+			if !errors.Is(err, myErrors.ErrDeadlinePassed) || br.edited != nil {
+				t.Fatalf("EditBid: err = %v, edited = %t", err, br.edited != nil)
+			}
+			_, err = u.EditBidStatus(br.bid.Id, "user", string(models.StatusPublished))
+			if !errors.Is(err, myErrors.ErrDeadlinePassed) || br.bid.Status != models.StatusCreated {
+				t.Fatalf("EditBidStatus: err = %v, status = %s", err, br.bid.Status)
+			}
+		})
+	}
+}
+
+func TestEditBidStatusBeforeDeadline(t *testing.T) {
+	deadline := now.Add(time.Hour)
+	tender := &models.TendersResponse{Id: uuid.NewV4(), Status: models.StatusPublished, SubmissionDeadline: &deadline}
+	br := &fakeBids{bid: &models.BidResponse{Id: uuid.NewV4(), TenderId: tender.Id, Status: models.StatusCreated}}
+	u := newTestUsecase(br, &fakeTenders{tender: tender})
+	if bid, err := u.EditBidStatus(br.bid.Id, "user", string(models.StatusPublished)); err != nil || bid.Status != models.StatusPublished {
+		t.Fatalf("bid = %v, err = %v", bid, err)
+	}
+}
+
+func TestPlaceAuctionPriceOnClosedTender(t *testing.T) {
+	tender := &models.TendersResponse{Id: uuid.NewV4(), Status: models.StatusClosed, Currency: "RUB"}
+	br := &fakeBids{bid: &models.BidResponse{Id: uuid.NewV4(), TenderId: tender.Id, Status: models.StatusPublished}}
+	u := newTestUsecase(br, &fakeTenders{tender: tender})
+
+	_, err := u.PlaceAuctionPrice(br.bid.Id, "user", &models.AuctionPriceRequest{Price: dec("500")})
+	if !errors.Is(err, myErrors.ErrAuctionNotActive) {
+		t.Fatalf("err = %v, want %v", err, myErrors.ErrAuctionNotActive)
+	}
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/satori/uuid"
+	"net/http"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/clarifications"
+	"zadanie-6105/internal/pkg/utils"
+)
+
+type ClarificationHandler struct {
+	u clarifications.ClarificationUsecase
+}
+
+func NewHandler(u clarifications.ClarificationUsecase) *ClarificationHandler {
+	return &ClarificationHandler{u: u}
+}
+
+func (h *ClarificationHandler) GetQuestions(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	questions, err := h.u.GetQuestions(tenderId, r.URL.Query().Get("username"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if questions == nil {
+		questions = []*models.Question{}
+	}
+	utils.WriteJSON(w, http.StatusOK, questions)
+}
+
+func (h *ClarificationHandler) AskQuestion(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var questionData models.QuestionRequest
+	if err = utils.ReadRequestData(r, &questionData); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	question, err := h.u.AskQuestion(tenderId, username, &questionData)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, question)
+}
+
+func (h *ClarificationHandler) AnswerQuestion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	questionId, err := uuid.FromString(vars["questionId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var answerData models.AnswerRequest
+	if err = utils.ReadRequestData(r, &answerData); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	question, err := h.u.AnswerQuestion(tenderId, questionId, username, &answerData)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, question)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, myErrors.ErrBadRequest):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+	case errors.Is(err, myErrors.ErrClarificationClosed):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrClarificationClosed)
+	case errors.Is(err, myErrors.ErrQuestionAnswered):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrQuestionAnswered)
+	case errors.Is(err, myErrors.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+	case errors.Is(err, myErrors.ErrNotInvited):
+		utils.WriteError(w, http.StatusForbidden, myErrors.ErrNotInvited)
+	case errors.Is(err, myErrors.ErrTenderNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+	case errors.Is(err, myErrors.ErrQuestionNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrQuestionNotFound)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+	}
+}
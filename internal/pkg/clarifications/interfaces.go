@@ -0,0 +1,19 @@
+package clarifications
+
+import (
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+)
+
+type ClarificationRepository interface {
+	InsertQuestion(question *models.Question, organizationId uuid.UUID) (*models.Question, error)
+	AnswerQuestion(questionId uuid.UUID, username string, answer *models.AnswerRequest, organizationId uuid.UUID) (*models.Question, error)
+	SelectQuestion(questionId uuid.UUID) (*models.Question, error)
+	SelectQuestions(tenderId uuid.UUID) ([]*models.Question, error)
+}
+
+type ClarificationUsecase interface {
+	AskQuestion(tenderId uuid.UUID, username string, question *models.QuestionRequest) (*models.Question, error)
+	AnswerQuestion(tenderId, questionId uuid.UUID, username string, answer *models.AnswerRequest) (*models.Question, error)
+	GetQuestions(tenderId uuid.UUID, username string) ([]*models.Question, error)
+}
@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	repoOutbox "zadanie-6105/internal/pkg/outbox/repo"
+)
+
+const questionColumns = `id, tender_id, text, author_username, anonymous, asked_at,
+	COALESCE(answer, ''), COALESCE(answered_by, ''), answered_at`
+
+type ClarificationRepoPostgres struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *ClarificationRepoPostgres {
+	return &ClarificationRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *ClarificationRepoPostgres) InsertQuestion(question *models.Question, organizationId uuid.UUID) (*models.Question, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO tender_question (tender_id, text, author_username)
+		VALUES ($1, $2, $3)
+		RETURNING ` + questionColumns
+
+	inserted, err := scanQuestion(tx.QueryRow(query, question.TenderId, question.Text, question.Author))
+	if err != nil {
+		return nil, err
+	}
+	if err = repoOutbox.Insert(tx, models.NewQuestionEvent(models.EventQuestionAsked, inserted, organizationId)); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+func (r *ClarificationRepoPostgres) AnswerQuestion(questionId uuid.UUID, username string, answer *models.AnswerRequest, organizationId uuid.UUID) (*models.Question, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE tender_question
+		SET answer = $2, answered_by = $3, anonymous = $4, answered_at = now()
+		WHERE id = $1 AND answered_at IS NULL
+		RETURNING ` + questionColumns
+
+	answered, err := scanQuestion(tx.QueryRow(query, questionId, answer.Text, username, answer.Anonymous))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrQuestionAnswered
+		}
+		return nil, err
+	}
+	if err = repoOutbox.Insert(tx, models.NewQuestionEvent(models.EventQuestionAnswered, answered.Published(), organizationId)); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return answered, nil
+}
+
+func (r *ClarificationRepoPostgres) SelectQuestion(questionId uuid.UUID) (*models.Question, error) {
+	query := `SELECT ` + questionColumns + ` FROM tender_question WHERE id = $1`
+
+	question, err := scanQuestion(r.db.QueryRow(query, questionId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrQuestionNotFound
+		}
+		return nil, err
+	}
+	return question, nil
+}
+
+func (r *ClarificationRepoPostgres) SelectQuestions(tenderId uuid.UUID) ([]*models.Question, error) {
+	query := `
+		SELECT ` + questionColumns + `
+		FROM tender_question
+		WHERE tender_id = $1
+		ORDER BY asked_at ASC, id ASC`
+
+	rows, err := r.db.Query(query, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []*models.Question
+	for rows.Next() {
+		question, err := scanQuestion(rows)
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, question)
+	}
+	return questions, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanQuestion(row scanner) (*models.Question, error) {
+	var question models.Question
+	var answeredAt sql.NullTime
+	err := row.Scan(&question.Id, &question.TenderId, &question.Text, &question.Author, &question.Anonymous,
+		&question.AskedAt, &question.Answer, &question.AnsweredBy, &answeredAt)
+	if err != nil {
+		return nil, err
+	}
+	if answeredAt.Valid {
+		question.AnsweredAt = &answeredAt.Time
+	}
+	return &question, nil
+}
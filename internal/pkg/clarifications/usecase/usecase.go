@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/clarifications"
+	"zadanie-6105/internal/pkg/clock"
+	"zadanie-6105/internal/pkg/tenders"
+)
+
+const maxTextLength = 2000
+
+type ClarificationUsecase struct {
+	r     clarifications.ClarificationRepository
+	tr    tenders.TenderRepoPostgres
+	clock clock.Clock
+}
+
+func NewUsecase(r clarifications.ClarificationRepository, tr tenders.TenderRepoPostgres, clk clock.Clock) *ClarificationUsecase {
+	return &ClarificationUsecase{r: r, tr: tr, clock: clk}
+}
+
+func (u *ClarificationUsecase) AskQuestion(tenderId uuid.UUID, username string, question *models.QuestionRequest) (*models.Question, error) {
+	if !validText(question.Text) {
+		return nil, myErrors.ErrBadRequest
+	}
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Status != models.StatusPublished {
+		return nil, myErrors.ErrTenderNotFound
+	}
+	if deadline := tender.QuestionsDeadline(); deadline != nil && !u.clock.Now().Before(*deadline) {
+		return nil, myErrors.ErrClarificationClosed
+	}
+	responsible, err := u.tr.CheckUsernameOrganization(username, tender.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+	if responsible {
+		return nil, myErrors.ErrForbidden
+	}
+	if err = tenders.CheckVisible(u.tr, tender, username); err != nil {
+		return nil, err
+	}
+	return u.r.InsertQuestion(&models.Question{
+		TenderId: tenderId,
+		Text:     strings.TrimSpace(question.Text),
+		Author:   username,
+	}, tender.OrganizationId)
+}
+
+func (u *ClarificationUsecase) AnswerQuestion(tenderId, questionId uuid.UUID, username string, answer *models.AnswerRequest) (*models.Question, error) {
+	if !validText(answer.Text) {
+		return nil, myErrors.ErrBadRequest
+	}
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	responsible, err := u.tr.CheckUsernameOrganization(username, tender.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+	if !responsible {
+		return nil, myErrors.ErrForbidden
+	}
+	question, err := u.r.SelectQuestion(questionId)
+	if err != nil {
+		return nil, err
+	}
+	if question.TenderId != tenderId {
+		return nil, myErrors.ErrQuestionNotFound
+	}
+	if question.Answered() {
+		return nil, myErrors.ErrQuestionAnswered
+	}
+	answer.Text = strings.TrimSpace(answer.Text)
+	return u.r.AnswerQuestion(questionId, username, answer, tender.OrganizationId)
+}
+
+// GetQuestions returns every question to the tender's responsible employees;
+// everyone else sees answered questions plus their own pending ones. Questions
+// to a draft are visible only to its creator, like the draft itself.
+func (u *ClarificationUsecase) GetQuestions(tenderId uuid.UUID, username string) ([]*models.Question, error) {
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Status == models.StatusCreated {
+		if username == "" {
+			return nil, myErrors.ErrForbidden
+		}
+		ok, err := u.tr.CheckUsernameTender(username, tenderId)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, myErrors.ErrForbidden
+		}
+	}
+	responsible := false
+	if username != "" {
+		responsible, err = u.tr.CheckUsernameOrganization(username, tender.OrganizationId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !responsible {
+		if err = tenders.CheckVisible(u.tr, tender, username); err != nil {
+			return nil, err
+		}
+	}
+
+	questions, err := u.r.SelectQuestions(tenderId)
+	if err != nil || responsible {
+		return questions, err
+	}
+	visible := make([]*models.Question, 0, len(questions))
+	for _, question := range questions {
+		switch {
+		case username != "" && question.Author == username:
+			visible = append(visible, question)
+		case question.Answered():
+			visible = append(visible, question.Published())
+		}
+	}
+	return visible, nil
+}
+
+func validText(text string) bool {
+	text = strings.TrimSpace(text)
+	return text != "" && utf8.RuneCountInString(text) <= maxTextLength
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/clarifications"
+	"zadanie-6105/internal/pkg/clock"
+	"zadanie-6105/internal/pkg/tenders"
+)
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeTenders and fakeQuestions implement just enough of the repositories for
+// the tests; any other call panics on the embedded nil interface.
+type fakeTenders struct {
+	tenders.TenderRepoPostgres
+	tender      *models.TendersResponse
+	creator     string
+	responsible bool
+}
+
+func (r *fakeTenders) SelectTender(uuid.UUID) (*models.TendersResponse, error) {
+	return r.tender, nil
+}
+
+func (r *fakeTenders) CheckUsernameTender(username string, _ uuid.UUID) (bool, error) {
+	return username == r.creator, nil
+}
+
+func (r *fakeTenders) CheckUsernameOrganization(string, uuid.UUID) (bool, error) {
+	return r.responsible, nil
+}
+
+type fakeQuestions struct {
+	clarifications.ClarificationRepository
+	question *models.Question
+	answered bool
+}
+
+func (r *fakeQuestions) SelectQuestion(uuid.UUID) (*models.Question, error) {
+	return r.question, nil
+}
+
+func (r *fakeQuestions) SelectQuestions(uuid.UUID) ([]*models.Question, error) {
+	return []*models.Question{r.question}, nil
+}
+
+func (r *fakeQuestions) AnswerQuestion(uuid.UUID, string, *models.AnswerRequest, uuid.UUID) (*models.Question, error) {
+	r.answered = true
+	return r.question, nil
+}
+
+func TestAnswerQuestionOnce(t *testing.T) {
+	tender := &models.TendersResponse{Id: uuid.NewV4(), Status: models.StatusPublished}
+	answeredAt := now
+	repo := &fakeQuestions{question: &models.Question{Id: uuid.NewV4(), TenderId: tender.Id, Answer: "yes", AnsweredAt: &answeredAt}}
+	u := NewUsecase(repo, &fakeTenders{tender: tender, responsible: true}, clock.NewFake(now))
+
+	_, err := u.AnswerQuestion(tender.Id, repo.question.Id, "user", &models.AnswerRequest{Text: "no"})
+	if !errors.Is(err, myErrors.ErrQuestionAnswered) || repo.answered {
+		t.Fatalf("err = %v, answered = %t", err, repo.answered)
+	}
+}
+
+func TestGetQuestionsOfDraft(t *testing.T) {
+	tender := &models.TendersResponse{Id: uuid.NewV4(), Status: models.StatusCreated}
+	repo := &fakeQuestions{question: &models.Question{Id: uuid.NewV4(), TenderId: tender.Id}}
+	// Another responsible employee of the organization is not the creator.
+	u := NewUsecase(repo, &fakeTenders{tender: tender, creator: "creator", responsible: true}, clock.NewFake(now))
+
+	if _, err := u.GetQuestions(tender.Id, "colleague"); !errors.Is(err, myErrors.ErrForbidden) {
+		t.Fatalf("err = %v, want %v", err, myErrors.ErrForbidden)
+	}
+	questions, err := u.GetQuestions(tender.Id, "creator")
+	if err != nil || len(questions) != 1 {
+		t.Fatalf("questions = %v, err = %v", questions, err)
+	}
+}
@@ -0,0 +1,44 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Fake is a manually driven clock for tests.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (c *Fake) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *Fake) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+func (c *Fake) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/satori/uuid"
+	"net/http"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/contracts"
+	"zadanie-6105/internal/pkg/utils"
+)
+
+type ContractHandler struct {
+	u contracts.ContractUsecase
+}
+
+func NewHandler(u contracts.ContractUsecase) *ContractHandler {
+	return &ContractHandler{u: u}
+}
+
+func (h *ContractHandler) CreateContract(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var contractData models.ContractRequest
+	if err = utils.ReadRequestData(r, &contractData); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	lotId, err := lotIdVar(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	contract, err := h.u.CreateContract(tenderId, lotId, r.URL.Query().Get("username"), &contractData)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, contract)
+}
+
+func (h *ContractHandler) GetTenderContract(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	lotId, err := lotIdVar(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	contract, err := h.u.GetTenderContract(tenderId, lotId, r.URL.Query().Get("username"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, contract)
+}
+
+func (h *ContractHandler) GetContract(w http.ResponseWriter, r *http.Request) {
+	contractId, err := uuid.FromString(mux.Vars(r)["contractId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	contract, err := h.u.GetContract(contractId, r.URL.Query().Get("username"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, contract)
+}
+
+func (h *ContractHandler) AddMilestone(w http.ResponseWriter, r *http.Request) {
+	contractId, err := uuid.FromString(mux.Vars(r)["contractId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var milestoneData models.MilestoneRequest
+	if err = utils.ReadRequestData(r, &milestoneData); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	milestone, err := h.u.AddMilestone(contractId, r.URL.Query().Get("username"), &milestoneData)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, milestone)
+}
+
+func (h *ContractHandler) UpdateProgress(w http.ResponseWriter, r *http.Request) {
+	contractId, milestoneId, ok := milestoneVars(r)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var progressData models.ProgressRequest
+	if err := utils.ReadRequestData(r, &progressData); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	milestone, err := h.u.UpdateProgress(contractId, milestoneId, r.URL.Query().Get("username"), &progressData)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, milestone)
+}
+
+func (h *ContractHandler) AcceptMilestone(w http.ResponseWriter, r *http.Request) {
+	h.reviewMilestone(w, r, h.u.AcceptMilestone)
+}
+
+func (h *ContractHandler) RejectMilestone(w http.ResponseWriter, r *http.Request) {
+	h.reviewMilestone(w, r, h.u.RejectMilestone)
+}
+
+type reviewFunc func(contractId, milestoneId uuid.UUID, username string, review *models.ReviewRequest) (*models.Milestone, error)
+
+// reviewMilestone reads the optional review body and applies the decision.
+func (h *ContractHandler) reviewMilestone(w http.ResponseWriter, r *http.Request, review reviewFunc) {
+	contractId, milestoneId, ok := milestoneVars(r)
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var reviewData models.ReviewRequest
+	if r.ContentLength != 0 {
+		if err := utils.ReadRequestData(r, &reviewData); err != nil {
+			utils.WriteRequestError(w, err)
+			return
+		}
+	}
+	milestone, err := review(contractId, milestoneId, r.URL.Query().Get("username"), &reviewData)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, milestone)
+}
+
+func milestoneVars(r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	vars := mux.Vars(r)
+	contractId, err := uuid.FromString(vars["contractId"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, false
+	}
+	milestoneId, err := uuid.FromString(vars["milestoneId"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return contractId, milestoneId, true
+}
+
+// lotIdVar reads the optional lot of the /tenders/{tenderId}/lots/{lotId}/contract routes.
+func lotIdVar(r *http.Request) (*uuid.UUID, error) {
+	value, ok := mux.Vars(r)["lotId"]
+	if !ok {
+		return nil, nil
+	}
+	lotId, err := uuid.FromString(value)
+	if err != nil {
+		return nil, err
+	}
+	return &lotId, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, myErrors.ErrBadRequest):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+	case errors.Is(err, myErrors.ErrContractExists):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrContractExists)
+	case errors.Is(err, myErrors.ErrContractCompleted):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrContractCompleted)
+	case errors.Is(err, myErrors.ErrMilestoneState):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrMilestoneState)
+	case errors.Is(err, myErrors.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+	case errors.Is(err, myErrors.ErrTenderNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+	case errors.Is(err, myErrors.ErrAwardNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrAwardNotFound)
+	case errors.Is(err, myErrors.ErrContractNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrContractNotFound)
+	case errors.Is(err, myErrors.ErrMilestoneNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrMilestoneNotFound)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+	}
+}
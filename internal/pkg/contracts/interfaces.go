@@ -0,0 +1,31 @@
+package contracts
+
+import (
+	"github.com/satori/uuid"
+	"time"
+	"zadanie-6105/internal/models"
+)
+
+type ContractRepository interface {
+	InsertContract(award *models.Award, organizationId uuid.UUID, username string, milestones []*models.MilestoneRequest) (*models.Contract, error)
+	SelectContract(contractId uuid.UUID) (*models.Contract, error)
+	SelectTenderContract(tenderId uuid.UUID, lotId *uuid.UUID) (*models.Contract, error)
+	SelectMilestone(contractId, milestoneId uuid.UUID) (*models.Milestone, error)
+	CheckSupplier(contractId uuid.UUID, username string) (bool, error)
+	InsertMilestone(contract *models.Contract, milestone *models.MilestoneRequest) (*models.Milestone, error)
+	UpdateProgress(contract *models.Contract, milestoneId uuid.UUID, username string, progress *models.ProgressRequest, status models.MilestoneStatus) (*models.Milestone, error)
+	ReviewMilestone(contract *models.Contract, milestoneId uuid.UUID, username string, status models.MilestoneStatus, comment string) (*models.Milestone, error)
+	CompleteContract(contractId uuid.UUID) (*models.Contract, error)
+	MarkOverdueMilestones(now time.Time) ([]*models.Milestone, error)
+}
+
+type ContractUsecase interface {
+	CreateContract(tenderId uuid.UUID, lotId *uuid.UUID, username string, contract *models.ContractRequest) (*models.Contract, error)
+	GetContract(contractId uuid.UUID, username string) (*models.Contract, error)
+	GetTenderContract(tenderId uuid.UUID, lotId *uuid.UUID, username string) (*models.Contract, error)
+	AddMilestone(contractId uuid.UUID, username string, milestone *models.MilestoneRequest) (*models.Milestone, error)
+	UpdateProgress(contractId, milestoneId uuid.UUID, username string, progress *models.ProgressRequest) (*models.Milestone, error)
+	AcceptMilestone(contractId, milestoneId uuid.UUID, username string, review *models.ReviewRequest) (*models.Milestone, error)
+	RejectMilestone(contractId, milestoneId uuid.UUID, username string, review *models.ReviewRequest) (*models.Milestone, error)
+	MarkOverdueMilestones(now time.Time) ([]*models.Milestone, error)
+}
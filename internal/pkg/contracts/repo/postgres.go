@@ -0,0 +1,494 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	repoOutbox "zadanie-6105/internal/pkg/outbox/repo"
+)
+
+const contractColumns = `id, tender_id, lot_id, bid_id, organization_id, supplier_type, supplier_id, price, COALESCE(currency, ''),
+	status, created_by, created_at, completed_at`
+
+const milestoneColumns = `id, contract_id, position, description, due_date, amount, status, progress, overdue_at,
+	COALESCE(reviewed_by, ''), reviewed_at, COALESCE(review_comment, ''), updated_at`
+
+type ContractRepoPostgres struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *ContractRepoPostgres {
+	return &ContractRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *ContractRepoPostgres) InsertContract(award *models.Award, organizationId uuid.UUID, username string,
+	milestones []*models.MilestoneRequest) (*models.Contract, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO contract (tender_id, lot_id, bid_id, organization_id, supplier_type, supplier_id, price, currency, status,
+		                      created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT DO NOTHING
+		RETURNING ` + contractColumns
+
+	contract, err := scanContract(tx.QueryRow(query, award.TenderId, award.LotId, award.BidId, organizationId,
+		award.AuthorType, award.AuthorId, award.Price, nullString(award.Currency), models.ContractActive, username))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrContractExists
+		}
+		return nil, err
+	}
+	for i, milestone := range milestones {
+		inserted, err := insertMilestone(tx, contract.Id, i+1, milestone)
+		if err != nil {
+			return nil, err
+		}
+		contract.Milestones = append(contract.Milestones, inserted)
+	}
+	if err = repoOutbox.Insert(tx, models.NewContractEvent(models.EventContractCreated, contract)); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
+
+func (r *ContractRepoPostgres) SelectContract(contractId uuid.UUID) (*models.Contract, error) {
+	return r.selectContract(`SELECT `+contractColumns+` FROM contract WHERE id = $1`, contractId)
+}
+
+// SelectTenderContract returns the contract of a tender without lots or, with
+// lotId, the contract of that lot.
+func (r *ContractRepoPostgres) SelectTenderContract(tenderId uuid.UUID, lotId *uuid.UUID) (*models.Contract, error) {
+	return r.selectContract(`SELECT `+contractColumns+` FROM contract WHERE tender_id = $1 AND lot_id IS NOT DISTINCT FROM $2`,
+		tenderId, lotId)
+}
+
+func (r *ContractRepoPostgres) SelectMilestone(contractId, milestoneId uuid.UUID) (*models.Milestone, error) {
+	query := `SELECT ` + milestoneColumns + ` FROM contract_milestone WHERE id = $1 AND contract_id = $2`
+
+	milestone, err := scanMilestone(r.db.QueryRow(query, milestoneId, contractId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrMilestoneNotFound
+		}
+		return nil, err
+	}
+	return milestone, nil
+}
+
+// CheckSupplier reports whether the user authored the awarded bid or, for a
+// bid on behalf of an organization, is responsible for the same organization
+// as its author. The supplier is always the employee who authored the bid.
+func (r *ContractRepoPostgres) CheckSupplier(contractId uuid.UUID, username string) (bool, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM contract
+		JOIN employee ON employee.username = $2
+		WHERE contract.id = $1 AND (
+			contract.supplier_id = employee.id
+			OR (contract.supplier_type = 'Organization' AND EXISTS (
+				SELECT 1
+				FROM organization_responsible AS supplier
+				JOIN organization_responsible AS colleague ON colleague.organization_id = supplier.organization_id
+				WHERE supplier.user_id = contract.supplier_id AND colleague.user_id = employee.id)))`
+
+	var count int
+	if err := r.db.QueryRow(query, contractId, username).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// InsertMilestone appends a milestone to an active contract. The contract row
+// stays locked while the milestone amounts are summed, so concurrent additions
+// cannot together exceed the contract price.
+func (r *ContractRepoPostgres) InsertMilestone(contract *models.Contract, milestone *models.MilestoneRequest) (*models.Milestone, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var (
+		status models.ContractStatus
+		price  *decimal.Decimal
+	)
+	err = tx.QueryRow(`SELECT status, price FROM contract WHERE id = $1 FOR UPDATE`, contract.Id).Scan(&status, &price)
+	if err != nil {
+		return nil, err
+	}
+	if status != models.ContractActive {
+		return nil, myErrors.ErrContractCompleted
+	}
+	if price != nil && milestone.Amount != nil {
+		var total decimal.Decimal
+		err = tx.QueryRow(`SELECT COALESCE(SUM(amount), 0) FROM contract_milestone WHERE contract_id = $1`, contract.Id).Scan(&total)
+		if err != nil {
+			return nil, err
+		}
+		if total.Add(*milestone.Amount).GreaterThan(*price) {
+			return nil, myErrors.ErrBadRequest
+		}
+	}
+	var position int
+	err = tx.QueryRow(`SELECT COALESCE(MAX(position), 0) + 1 FROM contract_milestone WHERE contract_id = $1`, contract.Id).Scan(&position)
+	if err != nil {
+		return nil, err
+	}
+	inserted, err := insertMilestone(tx, contract.Id, position, milestone)
+	if err != nil {
+		return nil, err
+	}
+	if err = commitMilestoneEvent(tx, models.EventMilestoneAdded, inserted, contract); err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+// UpdateProgress records the supplier's report and moves the milestone to the
+// given status.
+func (r *ContractRepoPostgres) UpdateProgress(contract *models.Contract, milestoneId uuid.UUID, username string,
+	progress *models.ProgressRequest, status models.MilestoneStatus) (*models.Milestone, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE contract_milestone
+		SET progress = $3, status = $4, updated_at = now()
+		WHERE id = $1 AND contract_id = $2 AND status IN ($5, $6, $7)
+		RETURNING ` + milestoneColumns
+
+	milestone, err := scanMilestone(tx.QueryRow(query, milestoneId, contract.Id, progress.Progress, status,
+		models.MilestonePending, models.MilestoneInProgress, models.MilestoneRejected))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrMilestoneState
+		}
+		return nil, err
+	}
+
+	queryUpdate := `
+		INSERT INTO contract_milestone_update (milestone_id, progress, comment, author_username)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, progress, COALESCE(comment, ''), author_username, created_at`
+
+	update, err := scanUpdate(tx.QueryRow(queryUpdate, milestoneId, progress.Progress, nullString(progress.Comment), username))
+	if err != nil {
+		return nil, err
+	}
+	milestone.Updates = []*models.MilestoneUpdate{update}
+
+	if err = commitMilestoneEvent(tx, models.EventMilestoneProgress, milestone, contract); err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+// ReviewMilestone accepts or rejects a delivered milestone.
+func (r *ContractRepoPostgres) ReviewMilestone(contract *models.Contract, milestoneId uuid.UUID, username string,
+	status models.MilestoneStatus, comment string) (*models.Milestone, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE contract_milestone
+		SET status = $3, reviewed_by = $4, reviewed_at = now(), review_comment = $5, updated_at = now()
+		WHERE id = $1 AND contract_id = $2 AND status = $6
+		RETURNING ` + milestoneColumns
+
+	milestone, err := scanMilestone(tx.QueryRow(query, milestoneId, contract.Id, status, username, nullString(comment),
+		models.MilestoneDelivered))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrMilestoneState
+		}
+		return nil, err
+	}
+
+	eventType := models.EventMilestoneRejected
+	if status == models.MilestoneAccepted {
+		eventType = models.EventMilestoneAccepted
+	}
+	if err = repoOutbox.Insert(tx, models.NewMilestoneEvent(eventType, milestone, contract.TenderId, contract.OrganizationId)); err != nil {
+		return nil, err
+	}
+	if err = insertSupplierEvents(tx, eventType, milestone, contract.TenderId, contract.OrganizationId, contract.SupplierId); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+// CompleteContract closes an active contract once every milestone has been
+// accepted; otherwise it returns ErrContractCompleted and changes nothing.
+func (r *ContractRepoPostgres) CompleteContract(contractId uuid.UUID) (*models.Contract, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE contract
+		SET status = $2, completed_at = now()
+		WHERE id = $1 AND status = $3 AND NOT EXISTS (
+			SELECT 1 FROM contract_milestone WHERE contract_id = $1 AND status <> $4)
+		RETURNING ` + contractColumns
+
+	completed, err := scanContract(tx.QueryRow(query, contractId, models.ContractCompleted,
+		models.ContractActive, models.MilestoneAccepted))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrContractCompleted
+		}
+		return nil, err
+	}
+	if err = repoOutbox.Insert(tx, models.NewContractEvent(models.EventContractCompleted, completed)); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return completed, nil
+}
+
+// MarkOverdueMilestones flags milestones of active contracts whose due date
+// has passed before delivery; each milestone is flagged once.
+func (r *ContractRepoPostgres) MarkOverdueMilestones(now time.Time) ([]*models.Milestone, error) {
+	query := `
+		WITH overdue AS (
+			UPDATE contract_milestone AS m
+			SET overdue_at = $1
+			FROM contract AS c
+			WHERE c.id = m.contract_id AND c.status = $2 AND m.overdue_at IS NULL AND m.due_date <= $1
+				AND m.status IN ($3, $4, $5)
+			RETURNING m.*, c.tender_id AS contract_tender_id, c.organization_id AS contract_organization_id,
+				c.supplier_id AS contract_supplier_id
+		)
+		SELECT ` + milestoneColumns + `, contract_tender_id, contract_organization_id, contract_supplier_id FROM overdue`
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(query, now, models.ContractActive,
+		models.MilestonePending, models.MilestoneInProgress, models.MilestoneRejected)
+	if err != nil {
+		return nil, err
+	}
+	type overdueMilestone struct {
+		milestone                            *models.Milestone
+		tenderId, organizationId, supplierId uuid.UUID
+	}
+	var found []overdueMilestone
+	for rows.Next() {
+		var m overdueMilestone
+		if m.milestone, err = scanMilestone(rows, &m.tenderId, &m.organizationId, &m.supplierId); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		found = append(found, m)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	var overdue []*models.Milestone
+	for _, m := range found {
+		overdue = append(overdue, m.milestone)
+		event := models.NewMilestoneEvent(models.EventMilestoneOverdue, m.milestone, m.tenderId, m.organizationId)
+		if err = repoOutbox.Insert(tx, event); err != nil {
+			return nil, err
+		}
+		err = insertSupplierEvents(tx, models.EventMilestoneOverdue, m.milestone, m.tenderId, m.organizationId, m.supplierId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return overdue, nil
+}
+
+func (r *ContractRepoPostgres) selectContract(query string, args ...interface{}) (*models.Contract, error) {
+	contract, err := scanContract(r.db.QueryRow(query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrContractNotFound
+		}
+		return nil, err
+	}
+
+	queryMilestones := `
+		SELECT ` + milestoneColumns + `
+		FROM contract_milestone
+		WHERE contract_id = $1
+		ORDER BY position ASC`
+
+	rows, err := r.db.Query(queryMilestones, contract.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byId := make(map[uuid.UUID]*models.Milestone)
+	for rows.Next() {
+		milestone, err := scanMilestone(rows)
+		if err != nil {
+			return nil, err
+		}
+		contract.Milestones = append(contract.Milestones, milestone)
+		byId[milestone.Id] = milestone
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	queryUpdates := `
+		SELECT u.milestone_id, u.id, u.progress, COALESCE(u.comment, ''), u.author_username, u.created_at
+		FROM contract_milestone_update AS u
+		JOIN contract_milestone AS m ON m.id = u.milestone_id
+		WHERE m.contract_id = $1
+		ORDER BY u.created_at ASC, u.id ASC`
+
+	updates, err := r.db.Query(queryUpdates, contract.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer updates.Close()
+
+	for updates.Next() {
+		var milestoneId uuid.UUID
+		update, err := scanUpdate(updates, &milestoneId)
+		if err != nil {
+			return nil, err
+		}
+		if milestone, ok := byId[milestoneId]; ok {
+			milestone.Updates = append(milestone.Updates, update)
+		}
+	}
+	return contract, updates.Err()
+}
+
+func insertMilestone(tx *sql.Tx, contractId uuid.UUID, position int, milestone *models.MilestoneRequest) (*models.Milestone, error) {
+	query := `
+		INSERT INTO contract_milestone (contract_id, position, description, due_date, amount, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + milestoneColumns
+
+	return scanMilestone(tx.QueryRow(query, contractId, position, milestone.Description, milestone.DueDate,
+		milestone.Amount, models.MilestonePending))
+}
+
+func commitMilestoneEvent(tx *sql.Tx, eventType models.EventType, milestone *models.Milestone, contract *models.Contract) error {
+	if err := repoOutbox.Insert(tx, models.NewMilestoneEvent(eventType, milestone, contract.TenderId, contract.OrganizationId)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertSupplierEvents addresses a milestone event to the organizations of the
+// supplier as well; the buyer's organization already has its own copy. A
+// supplier outside any organization gets the event addressed to them alone.
+func insertSupplierEvents(tx *sql.Tx, eventType models.EventType, milestone *models.Milestone, tenderId,
+	buyerId, supplierId uuid.UUID) error {
+	rows, err := tx.Query(`SELECT organization_id FROM organization_responsible WHERE user_id = $1`, supplierId)
+	if err != nil {
+		return err
+	}
+	var organizations []uuid.UUID
+	for rows.Next() {
+		var organizationId uuid.UUID
+		if err = rows.Scan(&organizationId); err != nil {
+			rows.Close()
+			return err
+		}
+		organizations = append(organizations, organizationId)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	if len(organizations) == 0 {
+		event := models.NewMilestoneEvent(eventType, milestone, tenderId, uuid.Nil)
+		event.RecipientId = &supplierId
+		return repoOutbox.Insert(tx, event)
+	}
+	for _, organizationId := range organizations {
+		if organizationId == buyerId {
+			continue
+		}
+		if err = repoOutbox.Insert(tx, models.NewMilestoneEvent(eventType, milestone, tenderId, organizationId)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanContract(row scanner) (*models.Contract, error) {
+	var contract models.Contract
+	err := row.Scan(&contract.Id, &contract.TenderId, &contract.LotId, &contract.BidId, &contract.OrganizationId, &contract.SupplierType,
+		&contract.SupplierId, &contract.Price, &contract.Currency, &contract.Status, &contract.CreatedBy,
+		&contract.CreatedAt, &contract.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &contract, nil
+}
+
+// scanMilestone reads milestoneColumns followed by any extra columns.
+func scanMilestone(row scanner, extra ...interface{}) (*models.Milestone, error) {
+	var milestone models.Milestone
+	dest := []interface{}{&milestone.Id, &milestone.ContractId, &milestone.Position, &milestone.Description,
+		&milestone.DueDate, &milestone.Amount, &milestone.Status, &milestone.Progress, &milestone.OverdueAt,
+		&milestone.ReviewedBy, &milestone.ReviewedAt, &milestone.ReviewComment, &milestone.UpdatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+// scanUpdate reads the update columns preceded by any extra columns.
+func scanUpdate(row scanner, extra ...interface{}) (*models.MilestoneUpdate, error) {
+	var update models.MilestoneUpdate
+	dest := append(extra, &update.Id, &update.Progress, &update.Comment, &update.Author, &update.CreatedAt)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/clock"
+	"zadanie-6105/internal/pkg/contracts"
+	"zadanie-6105/internal/pkg/tenders"
+)
+
+const maxTextLength = 2000
+
+type ContractUsecase struct {
+	r     contracts.ContractRepository
+	tr    tenders.TenderRepoPostgres
+	clock clock.Clock
+}
+
+func NewUsecase(r contracts.ContractRepository, tr tenders.TenderRepoPostgres, clk clock.Clock) *ContractUsecase {
+	return &ContractUsecase{r: r, tr: tr, clock: clk}
+}
+
+// CreateContract opens the contract for the awarded bid of the tender or, with
+// lotId, of one of its lots; the milestone amounts may not exceed the awarded
+// price.
+func (u *ContractUsecase) CreateContract(tenderId uuid.UUID, lotId *uuid.UUID, username string, contract *models.ContractRequest) (*models.Contract, error) {
+	if contract == nil || len(contract.Milestones) == 0 {
+		return nil, myErrors.ErrBadRequest
+	}
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkBuyer(username, tender.OrganizationId); err != nil {
+		return nil, err
+	}
+	award, err := u.award(tenderId, lotId)
+	if err != nil {
+		return nil, err
+	}
+	total := decimal.Zero
+	for _, milestone := range contract.Milestones {
+		if !u.validMilestone(milestone) {
+			return nil, myErrors.ErrBadRequest
+		}
+		if milestone.Amount != nil {
+			total = total.Add(*milestone.Amount)
+		}
+	}
+	if award.Price != nil && total.GreaterThan(*award.Price) {
+		return nil, myErrors.ErrBadRequest
+	}
+	return u.r.InsertContract(award, tender.OrganizationId, username, contract.Milestones)
+}
+
+func (u *ContractUsecase) GetContract(contractId uuid.UUID, username string) (*models.Contract, error) {
+	contract, err := u.r.SelectContract(contractId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkParty(contract, username); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
+
+func (u *ContractUsecase) GetTenderContract(tenderId uuid.UUID, lotId *uuid.UUID, username string) (*models.Contract, error) {
+	contract, err := u.r.SelectTenderContract(tenderId, lotId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkParty(contract, username); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
+
+func (u *ContractUsecase) award(tenderId uuid.UUID, lotId *uuid.UUID) (*models.Award, error) {
+	if lotId == nil {
+		return u.tr.SelectAward(tenderId)
+	}
+	award, err := u.tr.SelectLotAward(*lotId)
+	if err != nil {
+		return nil, err
+	}
+	if award.TenderId != tenderId {
+		return nil, myErrors.ErrAwardNotFound
+	}
+	return award, nil
+}
+
+func (u *ContractUsecase) AddMilestone(contractId uuid.UUID, username string, milestone *models.MilestoneRequest) (*models.Milestone, error) {
+	if !u.validMilestone(milestone) {
+		return nil, myErrors.ErrBadRequest
+	}
+	contract, err := u.activeContract(contractId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkBuyer(username, contract.OrganizationId); err != nil {
+		return nil, err
+	}
+	return u.r.InsertMilestone(contract, milestone)
+}
+
+func (u *ContractUsecase) UpdateProgress(contractId, milestoneId uuid.UUID, username string, progress *models.ProgressRequest) (*models.Milestone, error) {
+	if progress == nil || progress.Progress < 0 || progress.Progress > 100 ||
+		utf8.RuneCountInString(progress.Comment) > maxTextLength {
+		return nil, myErrors.ErrBadRequest
+	}
+	contract, err := u.activeContract(contractId)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := u.r.CheckSupplier(contractId, username)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrForbidden
+	}
+	milestone, err := u.r.SelectMilestone(contractId, milestoneId)
+	if err != nil {
+		return nil, err
+	}
+	if !milestone.Status.Reportable() {
+		return nil, myErrors.ErrMilestoneState
+	}
+	progress.Comment = strings.TrimSpace(progress.Comment)
+	// Full progress hands the milestone over for acceptance.
+	status := models.MilestoneInProgress
+	if progress.Progress == 100 {
+		status = models.MilestoneDelivered
+	}
+	return u.r.UpdateProgress(contract, milestoneId, username, progress, status)
+}
+
+func (u *ContractUsecase) AcceptMilestone(contractId, milestoneId uuid.UUID, username string, review *models.ReviewRequest) (*models.Milestone, error) {
+	if review == nil {
+		review = &models.ReviewRequest{}
+	}
+	milestone, err := u.reviewMilestone(contractId, milestoneId, username, models.MilestoneAccepted, review.Comment)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.completeContract(contractId); err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+// completeContract closes the contract once its last milestone is accepted.
+// The contract is read after the acceptance has been committed, so of two
+// concurrent acceptances at least the later one sees every milestone accepted.
+func (u *ContractUsecase) completeContract(contractId uuid.UUID) error {
+	contract, err := u.r.SelectContract(contractId)
+	if err != nil {
+		return err
+	}
+	if contract.Status != models.ContractActive {
+		return nil
+	}
+	for _, milestone := range contract.Milestones {
+		if milestone.Status != models.MilestoneAccepted {
+			return nil
+		}
+	}
+	if _, err = u.r.CompleteContract(contractId); err != nil && !errors.Is(err, myErrors.ErrContractCompleted) {
+		return err
+	}
+	return nil
+}
+
+// RejectMilestone sends a delivered milestone back to the supplier; the
+// buyer has to say why.
+func (u *ContractUsecase) RejectMilestone(contractId, milestoneId uuid.UUID, username string, review *models.ReviewRequest) (*models.Milestone, error) {
+	if review == nil || strings.TrimSpace(review.Comment) == "" {
+		return nil, myErrors.ErrBadRequest
+	}
+	return u.reviewMilestone(contractId, milestoneId, username, models.MilestoneRejected, review.Comment)
+}
+
+func (u *ContractUsecase) MarkOverdueMilestones(now time.Time) ([]*models.Milestone, error) {
+	overdue, err := u.r.MarkOverdueMilestones(now)
+	if err != nil {
+		return nil, err
+	}
+	return overdue, nil
+}
+
+func (u *ContractUsecase) reviewMilestone(contractId, milestoneId uuid.UUID, username string,
+	status models.MilestoneStatus, comment string) (*models.Milestone, error) {
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > maxTextLength {
+		return nil, myErrors.ErrBadRequest
+	}
+	contract, err := u.activeContract(contractId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkBuyer(username, contract.OrganizationId); err != nil {
+		return nil, err
+	}
+	milestone, err := u.r.SelectMilestone(contractId, milestoneId)
+	if err != nil {
+		return nil, err
+	}
+	if milestone.Status != models.MilestoneDelivered {
+		return nil, myErrors.ErrMilestoneState
+	}
+	return u.r.ReviewMilestone(contract, milestoneId, username, status, comment)
+}
+
+func (u *ContractUsecase) activeContract(contractId uuid.UUID) (*models.Contract, error) {
+	contract, err := u.r.SelectContract(contractId)
+	if err != nil {
+		return nil, err
+	}
+	if contract.Status != models.ContractActive {
+		return nil, myErrors.ErrContractCompleted
+	}
+	return contract, nil
+}
+
+func (u *ContractUsecase) checkBuyer(username string, organizationId uuid.UUID) error {
+	responsible, err := u.tr.CheckUsernameOrganization(username, organizationId)
+	if err != nil {
+		return err
+	}
+	if !responsible {
+		return myErrors.ErrForbidden
+	}
+	return nil
+}
+
+// checkParty lets through the buyer's responsible employees and the supplier.
+func (u *ContractUsecase) checkParty(contract *models.Contract, username string) error {
+	if err := u.checkBuyer(username, contract.OrganizationId); !errors.Is(err, myErrors.ErrForbidden) {
+		return err
+	}
+	ok, err := u.r.CheckSupplier(contract.Id, username)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return myErrors.ErrForbidden
+	}
+	return nil
+}
+
+func (u *ContractUsecase) validMilestone(milestone *models.MilestoneRequest) bool {
+	if milestone == nil {
+		return false
+	}
+	milestone.Description = strings.TrimSpace(milestone.Description)
+	if milestone.Description == "" || utf8.RuneCountInString(milestone.Description) > maxTextLength {
+		return false
+	}
+	if milestone.Amount != nil && !milestone.Amount.IsPositive() {
+		return false
+	}
+	return milestone.DueDate.After(u.clock.Now())
+}
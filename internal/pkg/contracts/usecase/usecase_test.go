@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/clock"
+	"zadanie-6105/internal/pkg/contracts"
+	"zadanie-6105/internal/pkg/tenders"
+)
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeTenders and fakeContracts implement just enough of the repositories for
+// the tests; any other call panics on the embedded nil interface. Only
+// "buyer" is responsible for the buyer's organization and only "supplier"
+// supplies the contract.
+type fakeTenders struct {
+	tenders.TenderRepoPostgres
+	award *models.Award
+}
+
+func (r *fakeTenders) SelectTender(tenderId uuid.UUID) (*models.TendersResponse, error) {
+	return &models.TendersResponse{Id: tenderId, Status: models.StatusClosed}, nil
+}
+
+func (r *fakeTenders) SelectAward(uuid.UUID) (*models.Award, error) {
+	return r.award, nil
+}
+
+func (r *fakeTenders) CheckUsernameOrganization(username string, _ uuid.UUID) (bool, error) {
+	return username == "buyer", nil
+}
+
+type fakeContracts struct {
+	contracts.ContractRepository
+	contract  *models.Contract
+	inserted  bool
+	progress  models.MilestoneStatus
+	completed bool
+}
+
+func (r *fakeContracts) InsertContract(*models.Award, uuid.UUID, string, []*models.MilestoneRequest) (*models.Contract, error) {
+	r.inserted = true
+	return r.contract, nil
+}
+
+func (r *fakeContracts) SelectContract(uuid.UUID) (*models.Contract, error) {
+	return r.contract, nil
+}
+
+func (r *fakeContracts) CheckSupplier(_ uuid.UUID, username string) (bool, error) {
+	return username == "supplier", nil
+}
+
+func (r *fakeContracts) SelectMilestone(_, milestoneId uuid.UUID) (*models.Milestone, error) {
+	for _, milestone := range r.contract.Milestones {
+		if milestone.Id == milestoneId {
+			return milestone, nil
+		}
+	}
+	return nil, myErrors.ErrMilestoneNotFound
+}
+
+func (r *fakeContracts) UpdateProgress(_ *models.Contract, milestoneId uuid.UUID, _ string, progress *models.ProgressRequest,
+	status models.MilestoneStatus) (*models.Milestone, error) {
+	r.progress = status
+	milestone, _ := r.SelectMilestone(r.contract.Id, milestoneId)
+	milestone.Progress, milestone.Status = progress.Progress, status
+	return milestone, nil
+}
+
+func (r *fakeContracts) ReviewMilestone(_ *models.Contract, milestoneId uuid.UUID, _ string, status models.MilestoneStatus,
+	_ string) (*models.Milestone, error) {
+	milestone, _ := r.SelectMilestone(r.contract.Id, milestoneId)
+	milestone.Status = status
+	return milestone, nil
+}
+
+func (r *fakeContracts) CompleteContract(uuid.UUID) (*models.Contract, error) {
+	r.completed = true
+	r.contract.Status = models.ContractCompleted
+	return r.contract, nil
+}
+
+func decPtr(s string) *decimal.Decimal {
+	d := decimal.RequireFromString(s)
+	return &d
+}
+
+func newTestContract(statuses ...models.MilestoneStatus) *models.Contract {
+	contract := &models.Contract{Id: uuid.NewV4(), TenderId: uuid.NewV4(), OrganizationId: uuid.NewV4(), Status: models.ContractActive}
+	for i, status := range statuses {
+		contract.Milestones = append(contract.Milestones, &models.Milestone{
+			Id: uuid.NewV4(), ContractId: contract.Id, Position: i + 1, Status: status,
+		})
+	}
+	return contract
+}
+
+func newTestUsecase(cr *fakeContracts, tr *fakeTenders) *ContractUsecase {
+	return NewUsecase(cr, tr, clock.NewFake(now))
+}
+
+func TestCreateContractMilestonesAbovePrice(t *testing.T) {
+	tr := &fakeTenders{award: &models.Award{TenderId: uuid.NewV4(), BidId: uuid.NewV4(), Price: decPtr("100")}}
+	milestone := func(amount string) *models.MilestoneRequest {
+		return &models.MilestoneRequest{Description: "Поставка", DueDate: now.Add(24 * time.Hour), Amount: decPtr(amount)}
+	}
+
+	tests := []struct {
+		name       string
+		milestones []*models.MilestoneRequest
+		wantErr    error
+	}{
+		{"within price", []*models.MilestoneRequest{milestone("60"), milestone("40")}, nil},
+		{"above price", []*models.MilestoneRequest{milestone("60"), milestone("40.01")}, myErrors.ErrBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := &fakeContracts{contract: newTestContract()}
+			_, err := newTestUsecase(cr, tr).CreateContract(tr.award.TenderId, nil, "buyer",
+				&models.ContractRequest{Milestones: tt.milestones})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if cr.inserted != (tt.wantErr == nil) {
+				t.Fatalf("inserted = %t", cr.inserted)
+			}
+		})
+	}
+}
+
+func TestUpdateProgressDelivers(t *testing.T) {
+	tests := []struct {
+		progress int
+		want     models.MilestoneStatus
+	}{
+		{50, models.MilestoneInProgress},
+		{100, models.MilestoneDelivered},
+	}
+	for _, tt := range tests {
+		cr := &fakeContracts{contract: newTestContract(models.MilestonePending)}
+		milestone := cr.contract.Milestones[0]
+		_, err := newTestUsecase(cr, &fakeTenders{}).UpdateProgress(cr.contract.Id, milestone.Id, "supplier",
+			&models.ProgressRequest{Progress: tt.progress})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cr.progress != tt.want {
+			t.Fatalf("progress %d: status = %s, want %s", tt.progress, cr.progress, tt.want)
+		}
+	}
+}
+
+func TestRejectMilestoneRequiresComment(t *testing.T) {
+	cr := &fakeContracts{contract: newTestContract(models.MilestoneDelivered)}
+	milestone := cr.contract.Milestones[0]
+	u := newTestUsecase(cr, &fakeTenders{})
+
+	for _, review := range []*models.ReviewRequest{nil, {Comment: "  "}} {
+		if _, err := u.RejectMilestone(cr.contract.Id, milestone.Id, "buyer", review); !errors.Is(err, myErrors.ErrBadRequest) {
+			t.Fatalf("review %+v: err = %v", review, err)
+		}
+	}
+	if milestone.Status != models.MilestoneDelivered {
+		t.Fatalf("status = %s", milestone.Status)
+	}
+	if _, err := u.RejectMilestone(cr.contract.Id, milestone.Id, "buyer", &models.ReviewRequest{Comment: "Нет актов"}); err != nil {
+		t.Fatal(err)
+	}
+	if milestone.Status != models.MilestoneRejected {
+		t.Fatalf("status = %s", milestone.Status)
+	}
+}
+
+func TestAcceptLastMilestoneCompletesContract(t *testing.T) {
+	cr := &fakeContracts{contract: newTestContract(models.MilestoneDelivered, models.MilestoneDelivered)}
+	u := newTestUsecase(cr, &fakeTenders{})
+
+	if _, err := u.AcceptMilestone(cr.contract.Id, cr.contract.Milestones[0].Id, "buyer", nil); err != nil {
+		t.Fatal(err)
+	}
+	if cr.completed {
+		t.Fatal("contract completed with a milestone still open")
+	}
+	if _, err := u.AcceptMilestone(cr.contract.Id, cr.contract.Milestones[1].Id, "buyer", nil); err != nil {
+		t.Fatal(err)
+	}
+	if !cr.completed {
+		t.Fatal("contract not completed after the last milestone")
+	}
+}
+
+func TestCheckParty(t *testing.T) {
+	cr := &fakeContracts{contract: newTestContract()}
+	u := newTestUsecase(cr, &fakeTenders{})
+
+	tests := []struct {
+		username string
+		wantErr  error
+	}{
+		{"buyer", nil},
+		{"supplier", nil},
+		{"stranger", myErrors.ErrForbidden},
+	}
+	for _, tt := range tests {
+		if err := u.checkParty(cr.contract, tt.username); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: err = %v, want %v", tt.username, err, tt.wantErr)
+		}
+	}
+}
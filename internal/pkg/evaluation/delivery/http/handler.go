@@ -0,0 +1,175 @@
+package http
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/satori/uuid"
+	"net/http"
+	"strconv"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/evaluation"
+	"zadanie-6105/internal/pkg/utils"
+)
+
+type EvaluationHandler struct {
+	u evaluation.EvaluationUsecase
+}
+
+func NewHandler(u evaluation.EvaluationUsecase) *EvaluationHandler {
+	return &EvaluationHandler{u: u}
+}
+
+func (h *EvaluationHandler) GetCriteria(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	criteria, err := h.u.GetCriteria(tenderId, r.URL.Query().Get("username"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if criteria == nil {
+		criteria = []*models.Criterion{}
+	}
+	utils.WriteJSON(w, http.StatusOK, criteria)
+}
+
+func (h *EvaluationHandler) ReplaceCriteria(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var criteriaData []models.CriterionRequest
+	if err = utils.ReadRequestData(r, &criteriaData); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	criteria, err := h.u.ReplaceCriteria(tenderId, username, criteriaData)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, criteria)
+}
+
+func (h *EvaluationHandler) ScoreBid(w http.ResponseWriter, r *http.Request) {
+	bidId, err := uuid.FromString(mux.Vars(r)["bidId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var scoresData []models.ScoreRequest
+	if err = utils.ReadRequestData(r, &scoresData); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	scores, err := h.u.ScoreBid(bidId, username, scoresData)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, scores)
+}
+
+func (h *EvaluationHandler) GetBidScores(w http.ResponseWriter, r *http.Request) {
+	bidId, err := uuid.FromString(mux.Vars(r)["bidId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	scores, err := h.u.GetBidScores(bidId, username)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if scores == nil {
+		scores = []*models.BidScore{}
+	}
+	utils.WriteJSON(w, http.StatusOK, scores)
+}
+
+func (h *EvaluationHandler) GetRanking(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	ranking, err := h.u.GetRanking(tenderId, username)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, ranking)
+}
+
+func (h *EvaluationHandler) GetRankingSnapshot(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	rankingId, err := strconv.ParseInt(vars["rankingId"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	ranking, err := h.u.GetRankingSnapshot(tenderId, rankingId, username)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, ranking)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, myErrors.ErrBadRequest):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+	case errors.Is(err, myErrors.ErrTenderNotEditable):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderNotEditable)
+	case errors.Is(err, myErrors.ErrBidsSealed):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBidsSealed)
+	case errors.Is(err, myErrors.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+	case errors.Is(err, myErrors.ErrNotInvited):
+		utils.WriteError(w, http.StatusForbidden, myErrors.ErrNotInvited)
+	case errors.Is(err, myErrors.ErrTenderNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+	case errors.Is(err, myErrors.ErrBidNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrBidNotFound)
+	case errors.Is(err, myErrors.ErrRankingNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrRankingNotFound)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+	}
+}
@@ -0,0 +1,25 @@
+package evaluation
+
+import (
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+)
+
+type EvaluationRepository interface {
+	SelectCriteria(tenderId uuid.UUID) ([]*models.Criterion, error)
+	ReplaceCriteria(tenderId uuid.UUID, criteria []models.CriterionRequest) ([]*models.Criterion, error)
+	UpsertScores(bidId uuid.UUID, username string, scores []models.ScoreRequest) ([]*models.BidScore, error)
+	SelectBidScores(bidId uuid.UUID) ([]*models.BidScore, error)
+	SelectEvaluations(tenderId uuid.UUID) ([]*models.BidEvaluation, error)
+	SelectRanking(tenderId uuid.UUID, rankingId int64) (*models.Ranking, error)
+}
+
+type EvaluationUsecase interface {
+	GetCriteria(tenderId uuid.UUID, username string) ([]*models.Criterion, error)
+	ReplaceCriteria(tenderId uuid.UUID, username string, criteria []models.CriterionRequest) ([]*models.Criterion, error)
+	ScoreBid(bidId uuid.UUID, username string, scores []models.ScoreRequest) ([]*models.BidScore, error)
+	GetBidScores(bidId uuid.UUID, username string) ([]*models.BidScore, error)
+	GetRanking(tenderId uuid.UUID, username string) (*models.Ranking, error)
+	GetRankingSnapshot(tenderId uuid.UUID, rankingId int64, username string) (*models.Ranking, error)
+	TenderRanking(tenderId uuid.UUID) (*models.Ranking, error)
+}
@@ -0,0 +1,214 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+)
+
+type EvaluationRepoPostgres struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *EvaluationRepoPostgres {
+	return &EvaluationRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *EvaluationRepoPostgres) SelectCriteria(tenderId uuid.UUID) ([]*models.Criterion, error) {
+	query := `
+		SELECT id, position, name, description, weight
+		FROM tender_criterion
+		WHERE tender_id = $1
+		ORDER BY position ASC`
+
+	rows, err := r.db.Query(query, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var criteria []*models.Criterion
+	for rows.Next() {
+		var criterion models.Criterion
+		err = rows.Scan(&criterion.Id, &criterion.Position, &criterion.Name, &criterion.Description, &criterion.Weight)
+		if err != nil {
+			return nil, err
+		}
+		criteria = append(criteria, &criterion)
+	}
+	return criteria, rows.Err()
+}
+
+func (r *EvaluationRepoPostgres) ReplaceCriteria(tenderId uuid.UUID, criteria []models.CriterionRequest) ([]*models.Criterion, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status models.TypeStatus
+	err = tx.QueryRow(`SELECT status FROM tender WHERE id = $1 FOR UPDATE`, tenderId).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrTenderNotFound
+		}
+		return nil, err
+	}
+	if status != models.StatusCreated {
+		return nil, myErrors.ErrTenderNotEditable
+	}
+
+	if _, err = tx.Exec(`DELETE FROM tender_criterion WHERE tender_id = $1`, tenderId); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO tender_criterion (tender_id, position, name, description, weight)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, position, name, description, weight`
+
+	var inserted []*models.Criterion
+	for i, criterion := range criteria {
+		var created models.Criterion
+		err = tx.QueryRow(query, tenderId, i+1, criterion.Name, criterion.Description, criterion.Weight).Scan(
+			&created.Id, &created.Position, &created.Name, &created.Description, &created.Weight)
+		if err != nil {
+			return nil, myErrors.ErrBadRequest
+		}
+		inserted = append(inserted, &created)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+func (r *EvaluationRepoPostgres) UpsertScores(bidId uuid.UUID, username string, scores []models.ScoreRequest) ([]*models.BidScore, error) {
+	query := `
+		INSERT INTO bid_score (bid_id, criterion_id, evaluator_id, score, comment)
+		SELECT b.id, c.id, e.id, $4, $5
+		FROM bid AS b
+		JOIN tender_criterion AS c ON c.tender_id = b.tender_id
+		JOIN employee AS e ON e.username = $3
+		WHERE b.id = $1 AND c.id = $2
+		ON CONFLICT (bid_id, criterion_id, evaluator_id)
+		DO UPDATE SET score = EXCLUDED.score, comment = EXCLUDED.comment, updated_at = CURRENT_TIMESTAMP`
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, score := range scores {
+		result, err := tx.Exec(query, bidId, score.CriterionId, username, score.Score, score.Comment)
+		if err != nil {
+			return nil, myErrors.ErrBadRequest
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return nil, myErrors.ErrBadRequest
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.SelectBidScores(bidId)
+}
+
+func (r *EvaluationRepoPostgres) SelectBidScores(bidId uuid.UUID) ([]*models.BidScore, error) {
+	query := `
+		SELECT s.criterion_id, e.username, s.score, s.comment, s.updated_at
+		FROM bid_score AS s
+		JOIN tender_criterion AS c ON c.id = s.criterion_id
+		JOIN employee AS e ON e.id = s.evaluator_id
+		WHERE s.bid_id = $1
+		ORDER BY c.position ASC, e.username ASC`
+
+	rows, err := r.db.Query(query, bidId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scores []*models.BidScore
+	for rows.Next() {
+		var score models.BidScore
+		if err = rows.Scan(&score.CriterionId, &score.Evaluator, &score.Score, &score.Comment, &score.UpdatedAt); err != nil {
+			return nil, err
+		}
+		scores = append(scores, &score)
+	}
+	return scores, rows.Err()
+}
+
+func (r *EvaluationRepoPostgres) SelectEvaluations(tenderId uuid.UUID) ([]*models.BidEvaluation, error) {
+	query := `
+		SELECT b.id, b.name, b.price, b.created_at, s.criterion_id, AVG(s.score)
+		FROM bid AS b
+		LEFT JOIN bid_score AS s ON s.bid_id = b.id
+		WHERE b.tender_id = $1 AND b.status = $2
+		GROUP BY b.id, b.name, b.price, b.created_at, s.criterion_id
+		ORDER BY b.created_at ASC, b.id ASC`
+
+	rows, err := r.db.Query(query, tenderId, models.StatusPublished)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		evaluations []*models.BidEvaluation
+		current     *models.BidEvaluation
+	)
+	for rows.Next() {
+		var (
+			evaluation  models.BidEvaluation
+			criterionId uuid.NullUUID
+			average     decimal.NullDecimal
+		)
+		err = rows.Scan(&evaluation.BidId, &evaluation.Name, &evaluation.Price, &evaluation.CreatedAt, &criterionId, &average)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil || current.BidId != evaluation.BidId {
+			evaluation.Scores = make(map[uuid.UUID]decimal.Decimal)
+			current = &evaluation
+			evaluations = append(evaluations, current)
+		}
+		if criterionId.Valid && average.Valid {
+			current.Scores[criterionId.UUID] = average.Decimal
+		}
+	}
+	return evaluations, rows.Err()
+}
+
+func (r *EvaluationRepoPostgres) SelectRanking(tenderId uuid.UUID, rankingId int64) (*models.Ranking, error) {
+	query := `SELECT id, ranking FROM tender_ranking WHERE id = $1 AND tender_id = $2`
+
+	var (
+		id   int64
+		data []byte
+	)
+	err := r.db.QueryRow(query, rankingId, tenderId).Scan(&id, &data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrRankingNotFound
+		}
+		return nil, err
+	}
+
+	var ranking models.Ranking
+	if err = json.Unmarshal(data, &ranking); err != nil {
+		return nil, err
+	}
+	ranking.Id = id
+	return &ranking, nil
+}
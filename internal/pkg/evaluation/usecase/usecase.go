@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"sort"
+
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/bids"
+	"zadanie-6105/internal/pkg/clock"
+	"zadanie-6105/internal/pkg/evaluation"
+	"zadanie-6105/internal/pkg/tenders"
+)
+
+const (
+	maxCriteria = 20
+	scoreScale  = 2
+)
+
+type EvaluationUsecase struct {
+	r     evaluation.EvaluationRepository
+	tr    tenders.TenderRepoPostgres
+	br    bids.BidRepository
+	clock clock.Clock
+}
+
+func NewUsecase(r evaluation.EvaluationRepository, tr tenders.TenderRepoPostgres, br bids.BidRepository, clk clock.Clock) *EvaluationUsecase {
+	return &EvaluationUsecase{r: r, tr: tr, br: br, clock: clk}
+}
+
+func (u *EvaluationUsecase) GetCriteria(tenderId uuid.UUID, username string) ([]*models.Criterion, error) {
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Status == models.StatusCreated {
+		if err = u.checkCreator(tenderId, username); err != nil {
+			return nil, err
+		}
+	}
+	if err = tenders.CheckVisible(u.tr, tender, username); err != nil {
+		return nil, err
+	}
+	return u.r.SelectCriteria(tenderId)
+}
+
+func (u *EvaluationUsecase) ReplaceCriteria(tenderId uuid.UUID, username string, criteria []models.CriterionRequest) ([]*models.Criterion, error) {
+	if err := validateCriteria(criteria); err != nil {
+		return nil, err
+	}
+	if err := u.checkCreator(tenderId, username); err != nil {
+		return nil, err
+	}
+	return u.r.ReplaceCriteria(tenderId, criteria)
+}
+
+func (u *EvaluationUsecase) ScoreBid(bidId uuid.UUID, username string, scores []models.ScoreRequest) ([]*models.BidScore, error) {
+	if err := validateScores(scores); err != nil {
+		return nil, err
+	}
+	bid, err := u.br.SelectBid(bidId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkResponsible(bid.TenderId, username); err != nil {
+		return nil, err
+	}
+	if bid.Sealed {
+		return nil, myErrors.ErrBidsSealed
+	}
+	if bid.Status != models.StatusPublished {
+		return nil, myErrors.ErrBadRequest
+	}
+	return u.r.UpsertScores(bidId, username, scores)
+}
+
+func (u *EvaluationUsecase) GetBidScores(bidId uuid.UUID, username string) ([]*models.BidScore, error) {
+	bid, err := u.br.SelectBid(bidId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkResponsible(bid.TenderId, username); err != nil {
+		return nil, err
+	}
+	return u.r.SelectBidScores(bidId)
+}
+
+func (u *EvaluationUsecase) GetRanking(tenderId uuid.UUID, username string) (*models.Ranking, error) {
+	if err := u.checkResponsible(tenderId, username); err != nil {
+		return nil, err
+	}
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Sealed && tender.OpenedAt == nil {
+		return nil, myErrors.ErrBidsSealed
+	}
+	criteria, err := u.r.SelectCriteria(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	hidePrices := models.TwoEnvelope(tender.ServiceType) && !tender.EvaluationPhase.PricesVisible()
+	return u.rank(tenderId, criteria, hidePrices)
+}
+
+func (u *EvaluationUsecase) GetRankingSnapshot(tenderId uuid.UUID, rankingId int64, username string) (*models.Ranking, error) {
+	if err := u.checkResponsible(tenderId, username); err != nil {
+		return nil, err
+	}
+	return u.r.SelectRanking(tenderId, rankingId)
+}
+
+// TenderRanking computes the current ranking without permission checks; it
+// returns nil when the tender has no evaluation criteria.
+func (u *EvaluationUsecase) TenderRanking(tenderId uuid.UUID) (*models.Ranking, error) {
+	criteria, err := u.r.SelectCriteria(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if len(criteria) == 0 {
+		return nil, nil
+	}
+	return u.rank(tenderId, criteria, false)
+}
+
+// rank computes the ranking; with hidePrices the financial envelopes are left
+// out, so prices neither show up nor break ties.
+func (u *EvaluationUsecase) rank(tenderId uuid.UUID, criteria []*models.Criterion, hidePrices bool) (*models.Ranking, error) {
+	evaluations, err := u.r.SelectEvaluations(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if hidePrices {
+		for _, evaluation := range evaluations {
+			evaluation.Price = nil
+		}
+	}
+	return &models.Ranking{
+		TenderId:   tenderId,
+		Criteria:   criteria,
+		Bids:       RankBids(criteria, evaluations),
+		ComputedAt: u.clock.Now(),
+	}, nil
+}
+
+// RankBids orders bids by weighted total. Fully scored bids come first; ties
+// are broken by lower price, then earlier submission, then bid id.
+func RankBids(criteria []*models.Criterion, evaluations []*models.BidEvaluation) []*models.RankedBid {
+	type entry struct {
+		ranked     *models.RankedBid
+		evaluation *models.BidEvaluation
+	}
+
+	entries := make([]entry, 0, len(evaluations))
+	for _, evaluation := range evaluations {
+		ranked := &models.RankedBid{
+			BidId:         evaluation.BidId,
+			Name:          evaluation.Name,
+			Price:         evaluation.Price,
+			WeightedTotal: decimal.Zero,
+		}
+		for _, criterion := range criteria {
+			score, ok := evaluation.Scores[criterion.Id]
+			if !ok {
+				continue
+			}
+			ranked.CriteriaScored++
+			ranked.WeightedTotal = ranked.WeightedTotal.Add(score.Mul(criterion.Weight))
+		}
+		ranked.WeightedTotal = ranked.WeightedTotal.Div(models.TotalWeight).Round(models.MoneyScale)
+		ranked.Complete = ranked.CriteriaScored == len(criteria)
+		entries = append(entries, entry{ranked: ranked, evaluation: evaluation})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.ranked.Complete != b.ranked.Complete {
+			return a.ranked.Complete
+		}
+		if cmp := a.ranked.WeightedTotal.Cmp(b.ranked.WeightedTotal); cmp != 0 {
+			return cmp > 0
+		}
+		switch {
+		case a.ranked.Price != nil && b.ranked.Price != nil:
+			if cmp := a.ranked.Price.Cmp(*b.ranked.Price); cmp != 0 {
+				return cmp < 0
+			}
+		case a.ranked.Price != nil:
+			return true
+		case b.ranked.Price != nil:
+			return false
+		}
+		if !a.evaluation.CreatedAt.Equal(b.evaluation.CreatedAt) {
+			return a.evaluation.CreatedAt.Before(b.evaluation.CreatedAt)
+		}
+		return a.ranked.BidId.String() < b.ranked.BidId.String()
+	})
+
+	ranked := make([]*models.RankedBid, len(entries))
+	for i, e := range entries {
+		e.ranked.Rank = i + 1
+		ranked[i] = e.ranked
+	}
+	return ranked
+}
+
+func (u *EvaluationUsecase) checkCreator(tenderId uuid.UUID, username string) error {
+	if username == "" {
+		return myErrors.ErrForbidden
+	}
+	ok, err := u.tr.CheckUsernameTender(username, tenderId)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return myErrors.ErrForbidden
+	}
+	return nil
+}
+
+func (u *EvaluationUsecase) checkResponsible(tenderId uuid.UUID, username string) error {
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return err
+	}
+	ok, err := u.tr.CheckUsernameOrganization(username, tender.OrganizationId)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return myErrors.ErrForbidden
+	}
+	return nil
+}
+
+func validateCriteria(criteria []models.CriterionRequest) error {
+	if len(criteria) == 0 || len(criteria) > maxCriteria {
+		return myErrors.ErrBadRequest
+	}
+	total := decimal.Zero
+	names := make(map[string]struct{}, len(criteria))
+	for _, criterion := range criteria {
+		if criterion.Name == "" || len(criterion.Name) > 100 {
+			return myErrors.ErrBadRequest
+		}
+		if _, duplicate := names[criterion.Name]; duplicate {
+			return myErrors.ErrBadRequest
+		}
+		names[criterion.Name] = struct{}{}
+		if !criterion.Weight.IsPositive() || criterion.Weight.Exponent() < -scoreScale {
+			return myErrors.ErrBadRequest
+		}
+		total = total.Add(criterion.Weight)
+	}
+	if !total.Equal(models.TotalWeight) {
+		return myErrors.ErrBadRequest
+	}
+	return nil
+}
+
+func validateScores(scores []models.ScoreRequest) error {
+	if len(scores) == 0 {
+		return myErrors.ErrBadRequest
+	}
+	seen := make(map[uuid.UUID]struct{}, len(scores))
+	for _, score := range scores {
+		if _, duplicate := seen[score.CriterionId]; duplicate {
+			return myErrors.ErrBadRequest
+		}
+		seen[score.CriterionId] = struct{}{}
+		if score.Score.IsNegative() || score.Score.GreaterThan(models.MaxScore) || score.Score.Exponent() < -scoreScale {
+			return myErrors.ErrBadRequest
+		}
+		if len(score.Comment) > 1000 {
+			return myErrors.ErrBadRequest
+		}
+	}
+	return nil
+}
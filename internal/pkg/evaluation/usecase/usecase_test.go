@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"zadanie-6105/internal/models"
+)
+
+func TestRankBidsTieBreak(t *testing.T) {
+	quality := &models.Criterion{Id: uuid.NewV4(), Weight: decimal.NewFromInt(60)}
+	delivery := &models.Criterion{Id: uuid.NewV4(), Weight: decimal.NewFromInt(40)}
+	criteria := []*models.Criterion{quality, delivery}
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lowId := uuid.FromStringOrNil("00000000-0000-0000-0000-000000000001")
+	highId := uuid.FromStringOrNil("00000000-0000-0000-0000-000000000002")
+	price := func(s string) *decimal.Decimal {
+		d := decimal.RequireFromString(s)
+		return &d
+	}
+	scores := func(q, d int64) map[uuid.UUID]decimal.Decimal {
+		return map[uuid.UUID]decimal.Decimal{quality.Id: decimal.NewFromInt(q), delivery.Id: decimal.NewFromInt(d)}
+	}
+	bid := func(name string, s map[uuid.UUID]decimal.Decimal, p *decimal.Decimal, created time.Duration) *models.BidEvaluation {
+		return &models.BidEvaluation{BidId: uuid.NewV4(), Name: name, Scores: s, Price: p, CreatedAt: start.Add(created)}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		evaluations []*models.BidEvaluation
+		want        []string
+	}{
+		{
+			name: "complete before higher partial total",
+			evaluations: []*models.BidEvaluation{
+				bid("partial", map[uuid.UUID]decimal.Decimal{quality.Id: decimal.NewFromInt(100)}, nil, 0),
+				bid("complete", scores(10, 10), nil, 0),
+			},
+			want: []string{"complete", "partial"},
+		},
+		{
+			name: "higher weighted total first",
+			evaluations: []*models.BidEvaluation{
+				// 0.6*50 + 0.4*100 = 70 against 0.6*80 + 0.4*40 = 64.
+				bid("64", scores(80, 40), price("10"), 0),
+				bid("70", scores(50, 100), price("90"), 0),
+			},
+			want: []string{"70", "64"},
+		},
+		{
+			name: "lower price breaks equal totals",
+			evaluations: []*models.BidEvaluation{
+				bid("expensive", scores(50, 50), price("200"), 0),
+				bid("no price", scores(50, 50), nil, -time.Hour),
+				bid("cheap", scores(50, 50), price("100"), time.Hour),
+			},
+			want: []string{"cheap", "expensive", "no price"},
+		},
+		{
+			name: "earlier submission breaks equal prices",
+			evaluations: []*models.BidEvaluation{
+				bid("late", scores(50, 50), price("100"), time.Hour),
+				bid("early", scores(50, 50), price("100"), 0),
+			},
+			want: []string{"early", "late"},
+		},
+		{
+			name: "bid id breaks everything else",
+			evaluations: []*models.BidEvaluation{
+				{BidId: highId, Name: "high", Scores: scores(50, 50), Price: price("100"), CreatedAt: start},
+				{BidId: lowId, Name: "low", Scores: scores(50, 50), Price: price("100"), CreatedAt: start},
+			},
+			want: []string{"low", "high"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ranked := RankBids(criteria, tc.evaluations)
+			if len(ranked) != len(tc.want) {
+				t.Fatalf("got %d bids, want %d", len(ranked), len(tc.want))
+			}
+			for i, name := range tc.want {
+				if ranked[i].Name != name || ranked[i].Rank != i+1 {
+					t.Fatalf("rank %d: got %q (rank %d), want %q", i+1, ranked[i].Name, ranked[i].Rank, name)
+				}
+			}
+		})
+	}
+}
+
+func TestRankBidsWeightedTotal(t *testing.T) {
+	quality := &models.Criterion{Id: uuid.NewV4(), Weight: decimal.NewFromInt(70)}
+	delivery := &models.Criterion{Id: uuid.NewV4(), Weight: decimal.NewFromInt(30)}
+	ranked := RankBids([]*models.Criterion{quality, delivery}, []*models.BidEvaluation{{
+		BidId:  uuid.NewV4(),
+		Scores: map[uuid.UUID]decimal.Decimal{quality.Id: decimal.NewFromInt(33), delivery.Id: decimal.NewFromInt(67)},
+	}})
+	if want := decimal.RequireFromString("43.2"); !ranked[0].WeightedTotal.Equal(want) || !ranked[0].Complete {
+		t.Fatalf("total = %s, complete = %t", ranked[0].WeightedTotal, ranked[0].Complete)
+	}
+}
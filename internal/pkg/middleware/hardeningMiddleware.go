@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/sirupsen/logrus"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/utils"
+)
+
+// Chain wraps h so that the first middleware is the outermost one.
+func Chain(h http.Handler, mws ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+func Recovery(log *logrus.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				log.WithFields(logrus.Fields{
+					"method": r.Method,
+					"path":   r.URL.Path,
+				}).Errorf("panic: %v\n%s", rec, debug.Stack())
+				utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				utils.WriteError(w, http.StatusRequestEntityTooLarge, myErrors.ErrRequestTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireJSON rejects requests that carry a body with a media type other than application/json.
+func RequireJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != 0 && hasBody(r.Method) {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || mediaType != "application/json" {
+				utils.WriteError(w, http.StatusUnsupportedMediaType, myErrors.ErrUnsupportedMediaType)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func hasBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
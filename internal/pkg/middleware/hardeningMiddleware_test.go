@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"zadanie-6105/internal/pkg/utils"
+)
+
+func TestMaxBodySizeChunked(t *testing.T) {
+	h := MaxBodySize(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v map[string]string
+		if err := utils.ReadRequestData(r, &v); err != nil {
+			utils.WriteRequestError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tc := range []struct {
+		name string
+		body string
+		want int
+	}{
+		{"small", `{"a":"b"}`, http.StatusOK},
+		{"too large", `{"a":"` + strings.Repeat("b", 64) + `"}`, http.StatusRequestEntityTooLarge},
+		{"malformed", `{"a":`, http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// io.MultiReader hides the length, so the request goes out chunked.
+			r := httptest.NewRequest(http.MethodPost, "/", io.MultiReader(strings.NewReader(tc.body)))
+			r.ContentLength = -1
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tc.want {
+				t.Fatalf("status = %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
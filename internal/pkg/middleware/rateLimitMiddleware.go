@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/clock"
+	"zadanie-6105/internal/pkg/ratelimit"
+	"zadanie-6105/internal/pkg/users"
+	"zadanie-6105/internal/pkg/utils"
+)
+
+type RateLimitMiddleware struct {
+	store             ratelimit.Store
+	defaultLimit      ratelimit.Limit
+	routes            map[string]ratelimit.Limit
+	users             users.UserRepository
+	trustProxyHeaders bool
+	log               *logrus.Logger
+	clock             clock.Clock
+}
+
+// NewRateLimitMiddleware creates a limiter; routes are keyed by "METHOD /path/template".
+func NewRateLimitMiddleware(store ratelimit.Store, defaultLimit ratelimit.Limit, routes map[string]ratelimit.Limit,
+	users users.UserRepository, trustProxyHeaders bool, clk clock.Clock, log *logrus.Logger) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		store:             store,
+		defaultLimit:      defaultLimit,
+		routes:            routes,
+		users:             users,
+		trustProxyHeaders: trustProxyHeaders,
+		log:               log,
+		clock:             clk,
+	}
+}
+
+func (m *RateLimitMiddleware) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.Method + " " + routeTemplate(r)
+		limit, ok := m.routes[route]
+		if !ok {
+			limit = m.defaultLimit
+		}
+
+		res, err := m.store.Take(route+"|"+m.subject(r), limit, m.clock.Now())
+		if err != nil {
+			m.log.Warn("rate limiter is unavailable, letting request through: ", err.Error())
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+		if !res.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			utils.WriteError(w, http.StatusTooManyRequests, myErrors.ErrTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// subject identifies the caller by username when it belongs to an existing
+// employee and by client IP otherwise, so users behind a shared address do not
+// throttle each other. An unknown username falls back to the IP so that
+// made-up names cannot dodge the limit.
+func (m *RateLimitMiddleware) subject(r *http.Request) string {
+	ip := "ip:" + m.clientIP(r)
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		return ip
+	}
+	ok, err := m.users.UserIsExists(username)
+	if err != nil {
+		m.log.Warn("cannot resolve username for rate limiting: ", err.Error())
+		return ip
+	}
+	if !ok {
+		return ip
+	}
+	return "user:" + username
+}
+
+func (m *RateLimitMiddleware) clientIP(r *http.Request) string {
+	if m.trustProxyHeaders {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			return realIP
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return r.URL.Path
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
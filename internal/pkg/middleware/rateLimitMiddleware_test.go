@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"zadanie-6105/internal/pkg/clock"
+	"zadanie-6105/internal/pkg/ratelimit"
+)
+
+type fakeUsers map[string]bool
+
+func (u fakeUsers) UserIsExists(username string) (bool, error) {
+	return u[username], nil
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(string, ratelimit.Limit, time.Time) (*ratelimit.Result, error) {
+	return nil, errors.New("store is down")
+}
+
+func newTestLimiter(store ratelimit.Store, clk clock.Clock) http.Handler {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	m := NewRateLimitMiddleware(store, ratelimit.Limit{Requests: 1, Per: time.Second, Burst: 1}, nil,
+		fakeUsers{"alice": true, "bob": true}, false, clk, log)
+	return m.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func request(h http.Handler, username, ip string) *httptest.ResponseRecorder {
+	target := "/api/tenders"
+	if username != "" {
+		target += "?username=" + username
+	}
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	r.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestRateLimitDeniesAndRefills(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	h := newTestLimiter(ratelimit.NewMemoryStore(), clk)
+
+	if w := request(h, "alice", "10.0.0.1"); w.Code != http.StatusOK || w.Header().Get("RateLimit-Remaining") != "0" {
+		t.Fatalf("first request: status = %d, remaining = %q", w.Code, w.Header().Get("RateLimit-Remaining"))
+	}
+	w := request(h, "alice", "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" || w.Header().Get("RateLimit-Limit") != "1" {
+		t.Fatalf("second request: status = %d, headers = %v", w.Code, w.Header())
+	}
+	clk.Advance(time.Second)
+	if w := request(h, "alice", "10.0.0.1"); w.Code != http.StatusOK {
+		t.Fatalf("after refill: status = %d", w.Code)
+	}
+}
+
+func TestRateLimitSubjects(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	h := newTestLimiter(ratelimit.NewMemoryStore(), clk)
+
+	// Users behind one address have their own buckets.
+	if request(h, "alice", "10.0.0.1").Code != http.StatusOK || request(h, "bob", "10.0.0.1").Code != http.StatusOK {
+		t.Fatal("users behind a shared address throttle each other")
+	}
+	// A denied user spends nothing from the address, so anonymous callers
+	// still get through.
+	if request(h, "alice", "10.0.0.1").Code != http.StatusTooManyRequests {
+		t.Fatal("user limit not applied")
+	}
+	if request(h, "", "10.0.0.1").Code != http.StatusOK {
+		t.Fatal("a denied user spent the address bucket")
+	}
+	// Unknown usernames share the address bucket.
+	if request(h, "mallory", "10.0.0.1").Code != http.StatusTooManyRequests {
+		t.Fatal("made-up username dodged the address limit")
+	}
+	if request(h, "", "10.0.0.2").Code != http.StatusOK {
+		t.Fatal("addresses share a bucket")
+	}
+}
+
+func TestRateLimitStoreFailureLetsRequestsThrough(t *testing.T) {
+	h := newTestLimiter(failingStore{}, clock.NewFake(time.Now()))
+	if w := request(h, "alice", "10.0.0.1"); w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+}
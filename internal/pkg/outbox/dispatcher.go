@@ -0,0 +1,86 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"zadanie-6105/internal/models"
+)
+
+// claimLease is how long claimed events stay hidden from other dispatchers;
+// events of a dispatcher that died mid-batch are picked up again after it.
+const claimLease = 5 * time.Minute
+
+type Dispatcher struct {
+	r         OutboxRepository
+	sinks     []Sink
+	interval  time.Duration
+	batchSize int
+	log       *logrus.Logger
+}
+
+func NewDispatcher(r OutboxRepository, interval time.Duration, batchSize int, log *logrus.Logger, sinks ...Sink) *Dispatcher {
+	return &Dispatcher{
+		r:         r,
+		sinks:     sinks,
+		interval:  interval,
+		batchSize: batchSize,
+		log:       log,
+	}
+}
+
+func (d *Dispatcher) AddSink(s Sink) {
+	d.sinks = append(d.sinks, s)
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		d.drain(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		events, err := d.r.ClaimBatch(d.batchSize, claimLease)
+		if err != nil {
+			d.log.Error("outbox: failed to claim batch: ", err.Error())
+			return
+		}
+		for _, event := range events {
+			if deliverErr := d.deliver(ctx, event); deliverErr != nil {
+				err = d.r.MarkFailed(event.Id, deliverErr.Error())
+			} else {
+				err = d.r.MarkDispatched(event.Id)
+			}
+			if err != nil {
+				d.log.Error("outbox: failed to record delivery of event ", event.Id, ": ", err.Error())
+			}
+		}
+		if len(events) < d.batchSize {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, event *models.Event) error {
+	for _, s := range d.sinks {
+		if err := s.Deliver(ctx, event); err != nil {
+			d.log.WithFields(logrus.Fields{
+				"event": event.Id,
+				"type":  event.Type,
+				"sink":  s.Name(),
+			}).Warn("outbox: delivery failed: ", err.Error())
+			return fmt.Errorf("sink %s: %w", s.Name(), err)
+		}
+	}
+	return nil
+}
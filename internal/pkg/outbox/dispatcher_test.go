@@ -0,0 +1,81 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/pkg/outbox"
+	"zadanie-6105/internal/pkg/outbox/sink"
+)
+
+type fakeRepo struct {
+	pending    []*models.Event
+	dispatched []int64
+	failed     map[int64]string
+}
+
+func (r *fakeRepo) ClaimBatch(limit int, _ time.Duration) ([]*models.Event, error) {
+	n := min(limit, len(r.pending))
+	events := r.pending[:n]
+	r.pending = r.pending[n:]
+	return events, nil
+}
+
+func (r *fakeRepo) MarkDispatched(id int64) error {
+	r.dispatched = append(r.dispatched, id)
+	return nil
+}
+
+func (r *fakeRepo) MarkFailed(id int64, reason string) error {
+	r.failed[id] = reason
+	return nil
+}
+
+type failingSink struct{ id int64 }
+
+func (s failingSink) Name() string { return "failing" }
+
+func (s failingSink) Deliver(_ context.Context, event *models.Event) error {
+	if event.Id == s.id {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+func TestDispatcherDeliversClaimedEvents(t *testing.T) {
+	repo := &fakeRepo{failed: map[int64]string{}}
+	for id := int64(1); id <= 5; id++ {
+		repo.pending = append(repo.pending, &models.Event{Id: id, Type: models.EventTenderCreated})
+	}
+	memory := sink.NewMemorySink()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := outbox.NewDispatcher(repo, time.Hour, 2, log, memory, failingSink{id: 3})
+	go func() {
+		// The first drain runs before Run waits on the ticker.
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	d.Run(ctx)
+
+	if got := len(memory.Events()); got != 5 {
+		t.Fatalf("memory sink got %d events, want 5", got)
+	}
+	if len(repo.pending) != 0 {
+		t.Fatalf("%d events left unclaimed", len(repo.pending))
+	}
+	if want := []int64{1, 2, 4, 5}; !slices.Equal(repo.dispatched, want) {
+		t.Fatalf("dispatched = %v, want %v", repo.dispatched, want)
+	}
+	if reason, ok := repo.failed[3]; !ok || reason == "" {
+		t.Fatalf("event 3 was not marked as failed: %v", repo.failed)
+	}
+}
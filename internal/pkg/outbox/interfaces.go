@@ -0,0 +1,21 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"zadanie-6105/internal/models"
+)
+
+type OutboxRepository interface {
+	ClaimBatch(limit int, lease time.Duration) ([]*models.Event, error)
+	MarkDispatched(id int64) error
+	MarkFailed(id int64, reason string) error
+}
+
+// Sink receives dispatched events. Delivery is at least once, so sinks must
+// tolerate duplicates; Event.Id is stable across retries.
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, event *models.Event) error
+}
@@ -0,0 +1,89 @@
+package repo
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"zadanie-6105/internal/models"
+)
+
+const maxBackoffSeconds = 600
+
+type OutboxRepoPostgres struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *OutboxRepoPostgres {
+	return &OutboxRepoPostgres{
+		db: db,
+	}
+}
+
+// Insert stores an event inside the caller's transaction, so it is committed
+// or rolled back together with the change that produced it.
+func Insert(tx *sql.Tx, event *models.Event) error {
+	query := `
+		INSERT INTO outbox_event (event_type, aggregate_type, aggregate_id, tender_id, organization_id, recipient_id, payload)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`
+
+	return tx.QueryRow(query, event.Type, event.AggregateType, event.AggregateId, event.TenderId,
+		event.OrganizationId, event.RecipientId, []byte(event.Payload)).Scan(&event.Id, &event.CreatedAt)
+}
+
+// ClaimBatch takes up to limit pending events and pushes their next attempt
+// lease seconds ahead, so other dispatchers skip them while they are being
+// delivered. The claim commits on its own; no lock is held during delivery.
+func (r *OutboxRepoPostgres) ClaimBatch(limit int, lease time.Duration) ([]*models.Event, error) {
+	query := `
+		UPDATE outbox_event
+		SET next_attempt_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 second'
+		WHERE id IN (
+			SELECT id
+			FROM outbox_event
+			WHERE dispatched_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, aggregate_type, aggregate_id, tender_id, organization_id, recipient_id, payload, created_at`
+
+	rows, err := r.db.Query(query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var events []*models.Event
+	for rows.Next() {
+		var event models.Event
+		if err = rows.Scan(&event.Id, &event.Type, &event.AggregateType, &event.AggregateId, &event.TenderId,
+			&event.OrganizationId, &event.RecipientId, &event.Payload, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Id < events[j].Id })
+	return events, nil
+}
+
+func (r *OutboxRepoPostgres) MarkDispatched(id int64) error {
+	query := `UPDATE outbox_event SET dispatched_at = CURRENT_TIMESTAMP, last_error = NULL WHERE id = $1`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+// MarkFailed records the error and schedules a retry with exponential backoff.
+func (r *OutboxRepoPostgres) MarkFailed(id int64, reason string) error {
+	query := `
+		UPDATE outbox_event
+		SET attempts = attempts + 1,
+		    last_error = $1,
+		    next_attempt_at = CURRENT_TIMESTAMP + LEAST(POWER(2, attempts + 1), $2) * INTERVAL '1 second'
+		WHERE id = $3`
+	_, err := r.db.Exec(query, reason, maxBackoffSeconds, id)
+	return err
+}
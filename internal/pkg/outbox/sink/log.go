@@ -0,0 +1,30 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"zadanie-6105/internal/models"
+)
+
+type LogSink struct {
+	log *logrus.Logger
+}
+
+func NewLogSink(log *logrus.Logger) *LogSink {
+	return &LogSink{log: log}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Deliver(_ context.Context, event *models.Event) error {
+	s.log.WithFields(logrus.Fields{
+		"event":     event.Id,
+		"type":      event.Type,
+		"aggregate": event.AggregateId,
+		"tender":    event.TenderId,
+	}).Info("domain event")
+	return nil
+}
@@ -0,0 +1,43 @@
+package sink
+
+import (
+	"context"
+	"sync"
+
+	"zadanie-6105/internal/models"
+)
+
+// MemorySink keeps delivered events in memory, it is meant for tests and local runs.
+type MemorySink struct {
+	mu     sync.Mutex
+	events []*models.Event
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Name() string {
+	return "memory"
+}
+
+func (s *MemorySink) Deliver(_ context.Context, event *models.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *MemorySink) Events() []*models.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := make([]*models.Event, len(s.events))
+	copy(events, s.events)
+	return events
+}
+
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = nil
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/satori/uuid"
+	"net/http"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/qualifications"
+	"zadanie-6105/internal/pkg/utils"
+)
+
+type QualificationHandler struct {
+	u qualifications.QualificationUsecase
+}
+
+func NewHandler(u qualifications.QualificationUsecase) *QualificationHandler {
+	return &QualificationHandler{u: u}
+}
+
+func (h *QualificationHandler) GetRequirements(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	requirements, err := h.u.GetRequirements(tenderId, r.URL.Query().Get("username"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if requirements == nil {
+		requirements = []*models.Requirement{}
+	}
+	utils.WriteJSON(w, http.StatusOK, requirements)
+}
+
+func (h *QualificationHandler) ReplaceRequirements(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var requirementsData []*models.RequirementRequest
+	if err = utils.ReadRequestData(r, &requirementsData); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	requirements, err := h.u.ReplaceRequirements(tenderId, r.URL.Query().Get("username"), requirementsData)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, requirements)
+}
+
+func (h *QualificationHandler) Apply(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var applicationData models.ApplicationRequest
+	if err = utils.ReadRequestData(r, &applicationData); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	application, err := h.u.Apply(tenderId, r.URL.Query().Get("username"), &applicationData)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, application)
+}
+
+func (h *QualificationHandler) GetApplications(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	applications, err := h.u.GetApplications(tenderId, r.URL.Query().Get("username"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if applications == nil {
+		applications = []*models.Application{}
+	}
+	utils.WriteJSON(w, http.StatusOK, applications)
+}
+
+func (h *QualificationHandler) ApproveApplication(w http.ResponseWriter, r *http.Request) {
+	h.reviewApplication(w, r, h.u.ApproveApplication)
+}
+
+func (h *QualificationHandler) RejectApplication(w http.ResponseWriter, r *http.Request) {
+	h.reviewApplication(w, r, h.u.RejectApplication)
+}
+
+type reviewFunc func(applicationId uuid.UUID, username string, review *models.ReviewRequest) (*models.Application, error)
+
+func (h *QualificationHandler) reviewApplication(w http.ResponseWriter, r *http.Request, review reviewFunc) {
+	applicationId, err := uuid.FromString(mux.Vars(r)["applicationId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var reviewData models.ReviewRequest
+	if r.ContentLength != 0 {
+		if err = utils.ReadRequestData(r, &reviewData); err != nil {
+			utils.WriteRequestError(w, err)
+			return
+		}
+	}
+	application, err := review(applicationId, r.URL.Query().Get("username"), &reviewData)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, application)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, myErrors.ErrBadRequest):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+	case errors.Is(err, myErrors.ErrDeadlinePassed):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrDeadlinePassed)
+	case errors.Is(err, myErrors.ErrRequirementsLocked):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrRequirementsLocked)
+	case errors.Is(err, myErrors.ErrApplicationExists):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrApplicationExists)
+	case errors.Is(err, myErrors.ErrApplicationReviewed):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrApplicationReviewed)
+	case errors.Is(err, myErrors.ErrUserNotFound):
+		utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
+	case errors.Is(err, myErrors.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+	case errors.Is(err, myErrors.ErrNotInvited):
+		utils.WriteError(w, http.StatusForbidden, myErrors.ErrNotInvited)
+	case errors.Is(err, myErrors.ErrTenderNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+	case errors.Is(err, myErrors.ErrApplicationNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrApplicationNotFound)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+	}
+}
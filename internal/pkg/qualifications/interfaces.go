@@ -0,0 +1,28 @@
+package qualifications
+
+import (
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+)
+
+type QualificationRepository interface {
+	ReplaceRequirements(tenderId uuid.UUID, requirements []*models.RequirementRequest) ([]*models.Requirement, error)
+	SelectRequirements(tenderId uuid.UUID) ([]*models.Requirement, error)
+	GetUserIdByUsername(username string) (uuid.UUID, error)
+	InsertApplication(application *models.Application, organizationId uuid.UUID) (*models.Application, error)
+	SelectApplication(applicationId uuid.UUID) (*models.Application, error)
+	SelectApplications(tenderId uuid.UUID) ([]*models.Application, error)
+	SelectUserApplications(tenderId uuid.UUID, username string) ([]*models.Application, error)
+	ReviewApplication(applicationId uuid.UUID, username string, status models.ApplicationStatus, comment string, organizationId uuid.UUID) (*models.Application, error)
+	CheckPrequalified(tenderId uuid.UUID, authorType models.TypeAuthor, authorId uuid.UUID) (bool, error)
+}
+
+type QualificationUsecase interface {
+	GetRequirements(tenderId uuid.UUID, username string) ([]*models.Requirement, error)
+	ReplaceRequirements(tenderId uuid.UUID, username string, requirements []*models.RequirementRequest) ([]*models.Requirement, error)
+	Apply(tenderId uuid.UUID, username string, application *models.ApplicationRequest) (*models.Application, error)
+	GetApplications(tenderId uuid.UUID, username string) ([]*models.Application, error)
+	ApproveApplication(applicationId uuid.UUID, username string, review *models.ReviewRequest) (*models.Application, error)
+	RejectApplication(applicationId uuid.UUID, username string, review *models.ReviewRequest) (*models.Application, error)
+	CheckPrequalified(tender *models.TendersResponse, authorType models.TypeAuthor, authorId uuid.UUID) error
+}
@@ -0,0 +1,301 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	repoOutbox "zadanie-6105/internal/pkg/outbox/repo"
+)
+
+const requirementColumns = `id, tender_id, position, kind, description, min_years`
+
+const applicationColumns = `id, tender_id, author_type, author_id, status, evidence, submitted_by, submitted_at,
+	COALESCE(reviewed_by, ''), reviewed_at, COALESCE(review_comment, '')`
+
+// authoredBy matches applications made by the employee named by $2 or by an
+// organization they are responsible for.
+const authoredBy = `EXISTS (
+		SELECT 1
+		FROM employee
+		WHERE employee.username = $2 AND (
+			(prequalification.author_type = 'User' AND prequalification.author_id = employee.id)
+			OR (prequalification.author_type = 'Organization' AND prequalification.author_id IN (
+				SELECT organization_id FROM organization_responsible WHERE user_id = employee.id))))`
+
+type QualificationRepoPostgres struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *QualificationRepoPostgres {
+	return &QualificationRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *QualificationRepoPostgres) ReplaceRequirements(tenderId uuid.UUID, requirements []*models.RequirementRequest) ([]*models.Requirement, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(`SELECT 1 FROM tender WHERE id = $1 FOR UPDATE`, tenderId); err != nil {
+		return nil, err
+	}
+	// Requirements are fixed once anyone has applied or bid under them.
+	var applications, bids int
+	err = tx.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM prequalification WHERE tender_id = $1), (SELECT COUNT(*) FROM bid WHERE tender_id = $1)`,
+		tenderId).Scan(&applications, &bids)
+	if err != nil {
+		return nil, err
+	}
+	if applications > 0 || bids > 0 {
+		return nil, myErrors.ErrRequirementsLocked
+	}
+	if _, err = tx.Exec(`DELETE FROM tender_requirement WHERE tender_id = $1`, tenderId); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO tender_requirement (tender_id, position, kind, description, min_years)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + requirementColumns
+
+	inserted := make([]*models.Requirement, 0, len(requirements))
+	for i, requirement := range requirements {
+		created, err := scanRequirement(tx.QueryRow(query, tenderId, i+1, requirement.Kind, requirement.Description,
+			requirement.MinYears))
+		if err != nil {
+			return nil, err
+		}
+		inserted = append(inserted, created)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+func (r *QualificationRepoPostgres) SelectRequirements(tenderId uuid.UUID) ([]*models.Requirement, error) {
+	query := `
+		SELECT ` + requirementColumns + `
+		FROM tender_requirement
+		WHERE tender_id = $1
+		ORDER BY position ASC`
+
+	rows, err := r.db.Query(query, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requirements []*models.Requirement
+	for rows.Next() {
+		requirement, err := scanRequirement(rows)
+		if err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, requirement)
+	}
+	return requirements, rows.Err()
+}
+
+func (r *QualificationRepoPostgres) GetUserIdByUsername(username string) (uuid.UUID, error) {
+	var userId uuid.UUID
+	err := r.db.QueryRow(`SELECT id FROM employee WHERE username = $1`, username).Scan(&userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, myErrors.ErrUserNotFound
+		}
+		return uuid.Nil, err
+	}
+	return userId, nil
+}
+
+// InsertApplication stores the application unless the author already has one
+// pending or approved for the tender; rejected authors may apply again.
+func (r *QualificationRepoPostgres) InsertApplication(application *models.Application, organizationId uuid.UUID) (*models.Application, error) {
+	evidence, err := json.Marshal(application.Evidence)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO prequalification (tender_id, author_type, author_id, status, evidence, submitted_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING
+		RETURNING ` + applicationColumns
+
+	inserted, err := scanApplication(tx.QueryRow(query, application.TenderId, application.AuthorType, application.AuthorId,
+		models.ApplicationPending, evidence, application.SubmittedBy))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrApplicationExists
+		}
+		return nil, err
+	}
+	if err = repoOutbox.Insert(tx, models.NewApplicationEvent(models.EventApplicationSubmitted, inserted, organizationId)); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+func (r *QualificationRepoPostgres) SelectApplication(applicationId uuid.UUID) (*models.Application, error) {
+	query := `SELECT ` + applicationColumns + ` FROM prequalification WHERE id = $1`
+
+	application, err := scanApplication(r.db.QueryRow(query, applicationId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrApplicationNotFound
+		}
+		return nil, err
+	}
+	return application, nil
+}
+
+func (r *QualificationRepoPostgres) SelectApplications(tenderId uuid.UUID) ([]*models.Application, error) {
+	query := `
+		SELECT ` + applicationColumns + `
+		FROM prequalification
+		WHERE tender_id = $1
+		ORDER BY submitted_at ASC`
+
+	rows, err := r.db.Query(query, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanApplications(rows)
+}
+
+func (r *QualificationRepoPostgres) SelectUserApplications(tenderId uuid.UUID, username string) ([]*models.Application, error) {
+	query := `
+		SELECT ` + applicationColumns + `
+		FROM prequalification
+		WHERE tender_id = $1 AND ` + authoredBy + `
+		ORDER BY submitted_at ASC`
+
+	rows, err := r.db.Query(query, tenderId, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanApplications(rows)
+}
+
+func (r *QualificationRepoPostgres) ReviewApplication(applicationId uuid.UUID, username string, status models.ApplicationStatus,
+	comment string, organizationId uuid.UUID) (*models.Application, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE prequalification
+		SET status = $2, reviewed_by = $3, reviewed_at = now(), review_comment = $4
+		WHERE id = $1 AND status = $5
+		RETURNING ` + applicationColumns
+
+	application, err := scanApplication(tx.QueryRow(query, applicationId, status, username, nullString(comment),
+		models.ApplicationPending))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrApplicationReviewed
+		}
+		return nil, err
+	}
+
+	eventType := models.EventApplicationRejected
+	if status == models.ApplicationApproved {
+		eventType = models.EventApplicationApproved
+	}
+	if err = repoOutbox.Insert(tx, models.NewApplicationEvent(eventType, application, organizationId)); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// CheckPrequalified reports whether the bid author holds an approved
+// application; an employee also qualifies through their organization's.
+func (r *QualificationRepoPostgres) CheckPrequalified(tenderId uuid.UUID, authorType models.TypeAuthor, authorId uuid.UUID) (bool, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM prequalification
+		WHERE tender_id = $1 AND status = $4 AND (
+			($2::text = 'User' AND author_type = 'User' AND author_id = $3)
+			OR (author_type = 'Organization' AND author_id IN (
+				SELECT organization_id FROM organization_responsible WHERE user_id = $3)))`
+
+	var count int
+	if err := r.db.QueryRow(query, tenderId, authorType, authorId, models.ApplicationApproved).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRequirement(row scanner) (*models.Requirement, error) {
+	var requirement models.Requirement
+	err := row.Scan(&requirement.Id, &requirement.TenderId, &requirement.Position, &requirement.Kind,
+		&requirement.Description, &requirement.MinYears)
+	if err != nil {
+		return nil, err
+	}
+	return &requirement, nil
+}
+
+func scanApplication(row scanner) (*models.Application, error) {
+	var (
+		application models.Application
+		evidence    []byte
+	)
+	err := row.Scan(&application.Id, &application.TenderId, &application.AuthorType, &application.AuthorId,
+		&application.Status, &evidence, &application.SubmittedBy, &application.SubmittedAt, &application.ReviewedBy,
+		&application.ReviewedAt, &application.ReviewComment)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(evidence, &application.Evidence); err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+func scanApplications(rows *sql.Rows) ([]*models.Application, error) {
+	var applications []*models.Application
+	for rows.Next() {
+		application, err := scanApplication(rows)
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, application)
+	}
+	return applications, rows.Err()
+}
@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/clock"
+	"zadanie-6105/internal/pkg/qualifications"
+	"zadanie-6105/internal/pkg/tenders"
+)
+
+const (
+	maxTextLength   = 2000
+	maxRequirements = 50
+)
+
+type QualificationUsecase struct {
+	r     qualifications.QualificationRepository
+	tr    tenders.TenderRepoPostgres
+	clock clock.Clock
+}
+
+func NewUsecase(r qualifications.QualificationRepository, tr tenders.TenderRepoPostgres, clk clock.Clock) *QualificationUsecase {
+	return &QualificationUsecase{r: r, tr: tr, clock: clk}
+}
+
+func (u *QualificationUsecase) GetRequirements(tenderId uuid.UUID, username string) ([]*models.Requirement, error) {
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if err = tenders.CheckVisible(u.tr, tender, username); err != nil {
+		return nil, err
+	}
+	requirements, err := u.r.SelectRequirements(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	return requirements, nil
+}
+
+// ReplaceRequirements sets the qualifications a Construction tender demands;
+// an empty list turns prequalification off. The list is frozen once suppliers
+// have applied.
+func (u *QualificationUsecase) ReplaceRequirements(tenderId uuid.UUID, username string, requirements []*models.RequirementRequest) ([]*models.Requirement, error) {
+	if len(requirements) > maxRequirements {
+		return nil, myErrors.ErrBadRequest
+	}
+	for _, requirement := range requirements {
+		if !validRequirement(requirement) {
+			return nil, myErrors.ErrBadRequest
+		}
+	}
+	tender, err := u.responsibleTender(tenderId, username)
+	if err != nil {
+		return nil, err
+	}
+	if tender.ServiceType != models.ServiceTypeConstruction {
+		return nil, myErrors.ErrBadRequest
+	}
+	if tender.Status != models.StatusCreated && tender.Status != models.StatusPublished {
+		return nil, myErrors.ErrRequirementsLocked
+	}
+	return u.r.ReplaceRequirements(tenderId, requirements)
+}
+
+// Apply submits prequalification evidence for every requirement of the tender
+// on behalf of the employee or an organization they are responsible for.
+func (u *QualificationUsecase) Apply(tenderId uuid.UUID, username string, application *models.ApplicationRequest) (*models.Application, error) {
+	if application == nil {
+		return nil, myErrors.ErrBadRequest
+	}
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Status != models.StatusPublished {
+		return nil, myErrors.ErrTenderNotFound
+	}
+	if tender.SubmissionDeadline != nil && !u.clock.Now().Before(*tender.SubmissionDeadline) {
+		return nil, myErrors.ErrDeadlinePassed
+	}
+	if err = tenders.CheckVisible(u.tr, tender, username); err != nil {
+		return nil, err
+	}
+	requirements, err := u.r.SelectRequirements(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if len(requirements) == 0 || !coversRequirements(requirements, application.Evidence) {
+		return nil, myErrors.ErrBadRequest
+	}
+
+	applicant := &models.Application{TenderId: tenderId, Evidence: application.Evidence, SubmittedBy: username}
+	if application.OrganizationId != nil {
+		if *application.OrganizationId == tender.OrganizationId {
+			return nil, myErrors.ErrBadRequest
+		}
+		ok, err := u.tr.CheckUsernameOrganization(username, *application.OrganizationId)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, myErrors.ErrForbidden
+		}
+		applicant.AuthorType, applicant.AuthorId = models.Organization, *application.OrganizationId
+	} else {
+		if applicant.AuthorId, err = u.r.GetUserIdByUsername(username); err != nil {
+			return nil, err
+		}
+		applicant.AuthorType = models.User
+	}
+	return u.r.InsertApplication(applicant, tender.OrganizationId)
+}
+
+// GetApplications returns every application to the tender's responsible
+// employees and only the applicant's own ones to everyone else.
+func (u *QualificationUsecase) GetApplications(tenderId uuid.UUID, username string) ([]*models.Application, error) {
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	responsible, err := u.tr.CheckUsernameOrganization(username, tender.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+	if responsible {
+		return u.r.SelectApplications(tenderId)
+	}
+	return u.r.SelectUserApplications(tenderId, username)
+}
+
+func (u *QualificationUsecase) ApproveApplication(applicationId uuid.UUID, username string, review *models.ReviewRequest) (*models.Application, error) {
+	if review == nil {
+		review = &models.ReviewRequest{}
+	}
+	return u.reviewApplication(applicationId, username, models.ApplicationApproved, review.Comment)
+}
+
+func (u *QualificationUsecase) RejectApplication(applicationId uuid.UUID, username string, review *models.ReviewRequest) (*models.Application, error) {
+	if review == nil || strings.TrimSpace(review.Comment) == "" {
+		return nil, myErrors.ErrBadRequest
+	}
+	return u.reviewApplication(applicationId, username, models.ApplicationRejected, review.Comment)
+}
+
+// CheckPrequalified lets a bid through unless the tender is a Construction
+// tender with requirements and the author holds no approved application.
+func (u *QualificationUsecase) CheckPrequalified(tender *models.TendersResponse, authorType models.TypeAuthor, authorId uuid.UUID) error {
+	if tender.ServiceType != models.ServiceTypeConstruction {
+		return nil
+	}
+	requirements, err := u.r.SelectRequirements(tender.Id)
+	if err != nil {
+		return err
+	}
+	if len(requirements) == 0 {
+		return nil
+	}
+	ok, err := u.r.CheckPrequalified(tender.Id, authorType, authorId)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return myErrors.ErrNotPrequalified
+	}
+	return nil
+}
+
+func (u *QualificationUsecase) reviewApplication(applicationId uuid.UUID, username string, status models.ApplicationStatus,
+	comment string) (*models.Application, error) {
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > maxTextLength {
+		return nil, myErrors.ErrBadRequest
+	}
+	application, err := u.r.SelectApplication(applicationId)
+	if err != nil {
+		return nil, err
+	}
+	tender, err := u.responsibleTender(application.TenderId, username)
+	if err != nil {
+		return nil, err
+	}
+	if application.Status != models.ApplicationPending {
+		return nil, myErrors.ErrApplicationReviewed
+	}
+	return u.r.ReviewApplication(applicationId, username, status, comment, tender.OrganizationId)
+}
+
+func (u *QualificationUsecase) responsibleTender(tenderId uuid.UUID, username string) (*models.TendersResponse, error) {
+	tender, err := u.tr.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := u.tr.CheckUsernameOrganization(username, tender.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrForbidden
+	}
+	return tender, nil
+}
+
+func validRequirement(requirement *models.RequirementRequest) bool {
+	if requirement == nil || !requirement.Kind.IsValid() || !validText(requirement.Description) || requirement.MinYears < 0 {
+		return false
+	}
+	requirement.Description = strings.TrimSpace(requirement.Description)
+	return requirement.Kind == models.RequirementExperience || requirement.MinYears == 0
+}
+
+// coversRequirements requires exactly one piece of evidence per requirement;
+// experience evidence has to state at least the required years.
+func coversRequirements(requirements []*models.Requirement, evidence []*models.Evidence) bool {
+	if len(evidence) != len(requirements) {
+		return false
+	}
+	byId := make(map[uuid.UUID]*models.Requirement, len(requirements))
+	for _, requirement := range requirements {
+		byId[requirement.Id] = requirement
+	}
+	for _, item := range evidence {
+		if item == nil || !validText(item.Description) || utf8.RuneCountInString(item.DocumentUrl) > maxTextLength {
+			return false
+		}
+		requirement, ok := byId[item.RequirementId]
+		if !ok {
+			return false
+		}
+		if requirement.Kind == models.RequirementExperience && item.Years < requirement.MinYears {
+			return false
+		}
+		item.Description = strings.TrimSpace(item.Description)
+		delete(byId, item.RequirementId)
+	}
+	return true
+}
+
+func validText(text string) bool {
+	text = strings.TrimSpace(text)
+	return text != "" && utf8.RuneCountInString(text) <= maxTextLength
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/clock"
+	"zadanie-6105/internal/pkg/qualifications"
+	"zadanie-6105/internal/pkg/tenders"
+)
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeTenders and fakeQualifications implement just enough of the
+// repositories for the tests; any other call panics on the embedded nil
+// interface.
+type fakeTenders struct {
+	tenders.TenderRepoPostgres
+	tender *models.TendersResponse
+}
+
+func (r *fakeTenders) SelectTender(uuid.UUID) (*models.TendersResponse, error) {
+	return r.tender, nil
+}
+
+func (r *fakeTenders) CheckUsernameOrganization(string, uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+type fakeQualifications struct {
+	qualifications.QualificationRepository
+	requirements []*models.Requirement
+	// prequalified holds the authors with an approved application.
+	prequalified map[uuid.UUID]models.TypeAuthor
+	// submissions counts the applications and bids already made.
+	submissions int
+	replaced    bool
+}
+
+func (r *fakeQualifications) SelectRequirements(uuid.UUID) ([]*models.Requirement, error) {
+	return r.requirements, nil
+}
+
+func (r *fakeQualifications) CheckPrequalified(_ uuid.UUID, authorType models.TypeAuthor, authorId uuid.UUID) (bool, error) {
+	approved, ok := r.prequalified[authorId]
+	return ok && approved == authorType, nil
+}
+
+func (r *fakeQualifications) ReplaceRequirements(uuid.UUID, []*models.RequirementRequest) ([]*models.Requirement, error) {
+	if r.submissions > 0 {
+		return nil, myErrors.ErrRequirementsLocked
+	}
+	r.replaced = true
+	return nil, nil
+}
+
+func newTestUsecase(r *fakeQualifications, tender *models.TendersResponse) *QualificationUsecase {
+	return NewUsecase(r, &fakeTenders{tender: tender}, clock.NewFake(now))
+}
+
+func TestCoversRequirements(t *testing.T) {
+	licence := &models.Requirement{Id: uuid.NewV4(), Kind: models.RequirementLicence}
+	experience := &models.Requirement{Id: uuid.NewV4(), Kind: models.RequirementExperience, MinYears: 5}
+	requirements := []*models.Requirement{licence, experience}
+	evidence := func(requirement *models.Requirement, years int) *models.Evidence {
+		return &models.Evidence{RequirementId: requirement.Id, Description: "Подтверждено", Years: years}
+	}
+
+	tests := []struct {
+		name     string
+		evidence []*models.Evidence
+		want     bool
+	}{
+		{"all covered", []*models.Evidence{evidence(licence, 0), evidence(experience, 5)}, true},
+		{"missing", []*models.Evidence{evidence(licence, 0)}, false},
+		{"duplicate", []*models.Evidence{evidence(licence, 0), evidence(licence, 0)}, false},
+		{"under years", []*models.Evidence{evidence(licence, 0), evidence(experience, 4)}, false},
+		{"unknown requirement", []*models.Evidence{evidence(licence, 0), {RequirementId: uuid.NewV4(), Description: "Прочее"}}, false},
+		{"empty description", []*models.Evidence{evidence(licence, 0), {RequirementId: experience.Id, Description: " ", Years: 5}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := coversRequirements(requirements, tt.evidence); got != tt.want {
+				t.Fatalf("coversRequirements = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPrequalified(t *testing.T) {
+	userId, organizationId := uuid.NewV4(), uuid.NewV4()
+	r := &fakeQualifications{
+		requirements: []*models.Requirement{{Id: uuid.NewV4(), Kind: models.RequirementLicence}},
+		prequalified: map[uuid.UUID]models.TypeAuthor{userId: models.User, organizationId: models.Organization},
+	}
+	construction := &models.TendersResponse{Id: uuid.NewV4(), ServiceType: models.ServiceTypeConstruction}
+	u := newTestUsecase(r, construction)
+
+	tests := []struct {
+		name       string
+		authorType models.TypeAuthor
+		authorId   uuid.UUID
+		wantErr    error
+	}{
+		{"approved user", models.User, userId, nil},
+		{"approved organization", models.Organization, organizationId, nil},
+		{"user of another type", models.Organization, userId, myErrors.ErrNotPrequalified},
+		{"unknown user", models.User, uuid.NewV4(), myErrors.ErrNotPrequalified},
+		{"unknown organization", models.Organization, uuid.NewV4(), myErrors.ErrNotPrequalified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := u.CheckPrequalified(construction, tt.authorType, tt.authorId); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	delivery := &models.TendersResponse{Id: uuid.NewV4(), ServiceType: models.ServiceTypeDelivery}
+	if err := u.CheckPrequalified(delivery, models.User, uuid.NewV4()); err != nil {
+		t.Fatalf("delivery tender: err = %v", err)
+	}
+	r.requirements = nil
+	if err := u.CheckPrequalified(construction, models.User, uuid.NewV4()); err != nil {
+		t.Fatalf("no requirements: err = %v", err)
+	}
+}
+
+func TestReplaceRequirementsLocked(t *testing.T) {
+	requirements := []*models.RequirementRequest{{Kind: models.RequirementLicence, Description: "Допуск СРО"}}
+
+	tests := []struct {
+		name        string
+		status      models.TypeStatus
+		submissions int
+		wantErr     error
+	}{
+		{"created", models.StatusCreated, 0, nil},
+		{"published without submissions", models.StatusPublished, 0, nil},
+		{"published with submissions", models.StatusPublished, 1, myErrors.ErrRequirementsLocked},
+		{"closed", models.StatusClosed, 0, myErrors.ErrRequirementsLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeQualifications{submissions: tt.submissions}
+			tender := &models.TendersResponse{Id: uuid.NewV4(), ServiceType: models.ServiceTypeConstruction, Status: tt.status}
+			_, err := newTestUsecase(r, tender).ReplaceRequirements(tender.Id, "user", requirements)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if r.replaced != (tt.wantErr == nil) {
+				t.Fatalf("replaced = %t", r.replaced)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// Take refills a token bucket holding tokens at time last up to now and tries
+// to spend one token from it. It returns the new token count and the result.
+func Take(tokens float64, last, now time.Time, limit Limit) (float64, *Result) {
+	rate := limit.rate()
+	if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+		tokens = math.Min(float64(limit.Burst), tokens+elapsed*rate)
+	}
+
+	res := &Result{Limit: limit.Burst}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+	res.Remaining = int(math.Floor(tokens))
+	res.Reset = secondsToDuration((float64(limit.Burst) - tokens) / rate)
+	return tokens, res
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestTakeDeniesEmptyBucket(t *testing.T) {
+	limit := Limit{Requests: 2, Per: time.Second, Burst: 2}
+	tokens := float64(limit.Burst)
+	for i := 0; i < limit.Burst; i++ {
+		var res *Result
+		tokens, res = Take(tokens, start, start, limit)
+		if !res.Allowed || res.Remaining != limit.Burst-i-1 {
+			t.Fatalf("request %d: allowed = %t, remaining = %d", i, res.Allowed, res.Remaining)
+		}
+	}
+	tokens, res := Take(tokens, start, start, limit)
+	if res.Allowed || res.Remaining != 0 || res.RetryAfter != 500*time.Millisecond || res.Reset != time.Second {
+		t.Fatalf("denied result = %+v", res)
+	}
+	if tokens != 0 {
+		t.Fatalf("denied request spent a token: %v left", tokens)
+	}
+}
+
+func TestTakeRefills(t *testing.T) {
+	limit := Limit{Requests: 1, Per: time.Minute, Burst: 3}
+	// Half a minute refills half a token: still not enough.
+	if _, res := Take(0, start, start.Add(30*time.Second), limit); res.Allowed {
+		t.Fatal("allowed before a token refilled")
+	}
+	tokens, res := Take(0, start, start.Add(time.Minute), limit)
+	if !res.Allowed || tokens != 0 {
+		t.Fatalf("allowed = %t, tokens = %v", res.Allowed, tokens)
+	}
+	// A long pause refills the bucket only up to the burst.
+	tokens, res = Take(0, start, start.Add(time.Hour), limit)
+	if !res.Allowed || tokens != float64(limit.Burst-1) {
+		t.Fatalf("allowed = %t, tokens = %v", res.Allowed, tokens)
+	}
+}
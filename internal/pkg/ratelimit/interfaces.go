@@ -0,0 +1,23 @@
+package ratelimit
+
+import (
+	"time"
+)
+
+type Limit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+type Store interface {
+	Take(key string, limit Limit, now time.Time) (*Result, error)
+}
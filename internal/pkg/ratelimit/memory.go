@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+const sweepInterval = 5 * time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full is when the bucket refills completely; from then on it is
+	// equivalent to an absent one.
+	full time.Time
+}
+
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket)}
+}
+
+func (s *MemoryStore) Take(key string, limit Limit, now time.Time) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+	var res *Result
+	b.tokens, res = Take(b.tokens, b.last, now, limit)
+	b.last, b.full = now, now.Add(res.Reset)
+	return res, nil
+}
+
+// sweep drops buckets that have refilled completely, so a limit with a long
+// period is never reset early.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
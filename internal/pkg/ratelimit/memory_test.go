@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreDeniesAndRefills(t *testing.T) {
+	s := NewMemoryStore()
+	limit := Limit{Requests: 1, Per: time.Second, Burst: 1}
+	if res, _ := s.Take("a", limit, start); !res.Allowed {
+		t.Fatal("first request denied")
+	}
+	if res, _ := s.Take("a", limit, start); res.Allowed {
+		t.Fatal("second request allowed")
+	}
+	if res, _ := s.Take("b", limit, start); !res.Allowed {
+		t.Fatal("another key shares the bucket")
+	}
+	if res, _ := s.Take("a", limit, start.Add(time.Second)); !res.Allowed {
+		t.Fatal("bucket did not refill")
+	}
+}
+
+func TestMemoryStoreKeepsSlowBuckets(t *testing.T) {
+	s := NewMemoryStore()
+	// One request per hour: the bucket stays empty far longer than a sweep.
+	limit := Limit{Requests: 1, Per: time.Hour, Burst: 1}
+	s.Take("slow", limit, start)
+	if res, _ := s.Take("slow", limit, start.Add(2*sweepInterval)); res.Allowed {
+		t.Fatal("sweep reset a bucket that had not refilled")
+	}
+	fast := Limit{Requests: 1, Per: time.Second, Burst: 1}
+	s.Take("fast", fast, start)
+	s.Take("slow", limit, start.Add(4*sweepInterval))
+	if _, ok := s.buckets["fast"]; ok {
+		t.Fatal("refilled bucket was not swept")
+	}
+	if _, ok := s.buckets["slow"]; !ok {
+		t.Fatal("empty bucket was swept")
+	}
+}
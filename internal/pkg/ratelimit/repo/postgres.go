@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"zadanie-6105/internal/pkg/ratelimit"
+)
+
+const sweepInterval = 5 * time.Minute
+
+type RateLimitRepoPostgres struct {
+	db        *sql.DB
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewRepository(db *sql.DB) *RateLimitRepoPostgres {
+	return &RateLimitRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *RateLimitRepoPostgres) Take(key string, limit ratelimit.Limit, now time.Time) (*ratelimit.Result, error) {
+	if err := r.sweep(now); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryInit := `
+		INSERT INTO rate_limit_bucket (key, tokens, updated_at, full_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (key) DO NOTHING`
+	if _, err = tx.Exec(queryInit, key, limit.Burst, now); err != nil {
+		return nil, err
+	}
+
+	var tokens float64
+	var last time.Time
+	querySelect := `SELECT tokens, updated_at FROM rate_limit_bucket WHERE key = $1 FOR UPDATE`
+	if err = tx.QueryRow(querySelect, key).Scan(&tokens, &last); err != nil {
+		return nil, err
+	}
+
+	tokens, res := ratelimit.Take(tokens, last, now, limit)
+
+	// The bucket may be dropped once it has refilled completely.
+	queryUpdate := `UPDATE rate_limit_bucket SET tokens = $1, updated_at = $2, full_at = $3 WHERE key = $4`
+	if _, err = tx.Exec(queryUpdate, tokens, now, now.Add(res.Reset), key); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *RateLimitRepoPostgres) sweep(now time.Time) error {
+	r.mu.Lock()
+	if now.Sub(r.lastSweep) < sweepInterval {
+		r.mu.Unlock()
+		return nil
+	}
+	r.lastSweep = now
+	r.mu.Unlock()
+
+	_, err := r.db.Exec(`DELETE FROM rate_limit_bucket WHERE full_at <= $1`, now)
+	return err
+}
@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/pkg/ratelimit"
+)
+
+// The store is exercised against a migrated database named by
+// TEST_POSTGRES_CONN; without it the test is skipped.
+func TestPostgresStoreDeniesAndRefills(t *testing.T) {
+	conn := os.Getenv("TEST_POSTGRES_CONN")
+	if conn == "" {
+		t.Skip("TEST_POSTGRES_CONN is not set")
+	}
+	db, err := sql.Open("postgres", conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store := NewRepository(db)
+	key := "test|" + uuid.NewV4().String()
+	defer db.Exec(`DELETE FROM rate_limit_bucket WHERE key = $1`, key)
+
+	limit := ratelimit.Limit{Requests: 1, Per: time.Second, Burst: 1}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if res, err := store.Take(key, limit, now); err != nil || !res.Allowed {
+		t.Fatalf("first take: %+v, %v", res, err)
+	}
+	res, err := store.Take(key, limit, now)
+	if err != nil || res.Allowed || res.RetryAfter != time.Second {
+		t.Fatalf("second take: %+v, %v", res, err)
+	}
+	if res, err := store.Take(key, limit, now.Add(time.Second)); err != nil || !res.Allowed {
+		t.Fatalf("after refill: %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+)
+
+// AdvisoryLocker serialises jobs across replicas with Postgres session-level
+// advisory locks, held on a dedicated connection for the duration of the job.
+type AdvisoryLocker struct {
+	db *sql.DB
+}
+
+func NewAdvisoryLocker(db *sql.DB) *AdvisoryLocker {
+	return &AdvisoryLocker{db: db}
+}
+
+func (l *AdvisoryLocker) WithLock(ctx context.Context, name string, fn func() error) (bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, name).Scan(&locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, name)
+
+	return true, fn()
+}
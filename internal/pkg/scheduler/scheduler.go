@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"zadanie-6105/internal/pkg/clock"
+)
+
+type Job interface {
+	Name() string
+	Run(ctx context.Context, now time.Time) error
+}
+
+// Locker guarantees that a job runs on at most one replica at a time.
+type Locker interface {
+	WithLock(ctx context.Context, name string, fn func() error) (bool, error)
+}
+
+type jobFunc struct {
+	name string
+	run  func(ctx context.Context, now time.Time) error
+}
+
+func (j *jobFunc) Name() string {
+	return j.name
+}
+
+func (j *jobFunc) Run(ctx context.Context, now time.Time) error {
+	return j.run(ctx, now)
+}
+
+func NewJob(name string, run func(ctx context.Context, now time.Time) error) Job {
+	return &jobFunc{name: name, run: run}
+}
+
+type Scheduler struct {
+	locker   Locker
+	clock    clock.Clock
+	interval time.Duration
+	jobs     []Job
+	log      *logrus.Logger
+}
+
+func NewScheduler(locker Locker, clk clock.Clock, interval time.Duration, log *logrus.Logger) *Scheduler {
+	return &Scheduler{
+		locker:   locker,
+		clock:    clk,
+		interval: interval,
+		log:      log,
+	}
+}
+
+func (s *Scheduler) AddJob(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.Tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick runs every job once at the current clock time.
+func (s *Scheduler) Tick(ctx context.Context) {
+	for _, job := range s.jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		now := s.clock.Now()
+		ran, err := s.locker.WithLock(ctx, job.Name(), func() error {
+			return job.Run(ctx, now)
+		})
+		if err != nil {
+			s.log.WithField("job", job.Name()).Error("scheduler: job failed: ", err.Error())
+			continue
+		}
+		if !ran {
+			s.log.WithField("job", job.Name()).Debug("scheduler: job is running on another replica")
+		}
+	}
+}
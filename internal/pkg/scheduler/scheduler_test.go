@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"zadanie-6105/internal/pkg/clock"
+)
+
+type fakeLocker struct {
+	busy map[string]bool
+}
+
+func (l *fakeLocker) WithLock(_ context.Context, name string, fn func() error) (bool, error) {
+	if l.busy[name] {
+		return false, nil
+	}
+	return true, fn()
+}
+
+func TestTickRunsJobsAtClockTime(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(start)
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	s := NewScheduler(&fakeLocker{busy: map[string]bool{"locked": true}}, clk, time.Minute, log)
+
+	var seen []time.Time
+	var lockedRan bool
+	s.AddJob(NewJob("failing", func(context.Context, time.Time) error {
+		return errors.New("boom")
+	}))
+	s.AddJob(NewJob("locked", func(context.Context, time.Time) error {
+		lockedRan = true
+		return nil
+	}))
+	s.AddJob(NewJob("record", func(_ context.Context, now time.Time) error {
+		seen = append(seen, now)
+		return nil
+	}))
+
+	s.Tick(context.Background())
+	clk.Advance(time.Hour)
+	s.Tick(context.Background())
+
+	if len(seen) != 2 || !seen[0].Equal(start) || !seen[1].Equal(start.Add(time.Hour)) {
+		t.Fatalf("job saw %v", seen)
+	}
+	if lockedRan {
+		t.Fatal("job ran without its lock")
+	}
+}
@@ -0,0 +1,94 @@
+package sealing
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+
+	"github.com/satori/uuid"
+)
+
+const keySize = 32
+
+var ErrMalformed = errors.New("sealing: malformed ciphertext")
+
+// Sealer encrypts bid envelopes with per-tender data keys. Data keys are
+// stored wrapped by the master key and bound to their tender id.
+type Sealer struct {
+	master cipher.AEAD
+}
+
+func NewSealer(encodedMasterKey string) (*Sealer, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedMasterKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != keySize {
+		return nil, errors.New("sealing: master key must be 32 bytes")
+	}
+	master, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealer{master: master}, nil
+}
+
+// NewTenderKey returns a fresh data key wrapped by the master key.
+func (s *Sealer) NewTenderKey(tenderId uuid.UUID) ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return seal(s.master, key, tenderId.Bytes())
+}
+
+func (s *Sealer) Seal(wrappedKey []byte, tenderId uuid.UUID, plaintext []byte) ([]byte, error) {
+	aead, err := s.tenderAEAD(wrappedKey, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	return seal(aead, plaintext, tenderId.Bytes())
+}
+
+func (s *Sealer) Open(wrappedKey []byte, tenderId uuid.UUID, ciphertext []byte) ([]byte, error) {
+	aead, err := s.tenderAEAD(wrappedKey, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, ciphertext, tenderId.Bytes())
+}
+
+func (s *Sealer) tenderAEAD(wrappedKey []byte, tenderId uuid.UUID) (cipher.AEAD, error) {
+	key, err := open(s.master, wrappedKey, tenderId.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return newAEAD(key)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+func open(aead cipher.AEAD, ciphertext, additional []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, additional)
+}
@@ -0,0 +1,112 @@
+package sealing
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/satori/uuid"
+)
+
+func newTestSealer(t *testing.T, fill byte) *Sealer {
+	t.Helper()
+	s, err := NewSealer(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{fill}, keySize)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func sealTestEnvelope(t *testing.T, s *Sealer, tenderId uuid.UUID, plaintext []byte) ([]byte, []byte) {
+	t.Helper()
+	key, err := s.NewTenderKey(tenderId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := s.Seal(key, tenderId, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, ciphertext
+}
+
+func TestNewSealerRejectsBadKeys(t *testing.T) {
+	for _, key := range []string{"not base64!", base64.StdEncoding.EncodeToString(make([]byte, 16))} {
+		if _, err := NewSealer(key); err == nil {
+			t.Errorf("NewSealer(%q) accepted", key)
+		}
+	}
+}
+
+func TestSealRoundTrip(t *testing.T) {
+	s := newTestSealer(t, 1)
+	tenderId := uuid.NewV4()
+	plaintext := []byte(`{"price":"100.50","currency":"RUB"}`)
+
+	key, ciphertext := sealTestEnvelope(t, s, tenderId, plaintext)
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Fatal("ciphertext contains the plaintext")
+	}
+	opened, err := s.Open(key, tenderId, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Fatalf("opened %q, want %q", opened, plaintext)
+	}
+
+	// Nonces are random, so sealing twice never repeats a ciphertext.
+	again, err := s.Seal(key, tenderId, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(again, ciphertext) {
+		t.Fatal("sealing is deterministic")
+	}
+}
+
+func TestOpenWrongTender(t *testing.T) {
+	s := newTestSealer(t, 1)
+	tenderId := uuid.NewV4()
+	key, ciphertext := sealTestEnvelope(t, s, tenderId, []byte("bid"))
+
+	// The tender id is the additional data of both the key and the envelope.
+	if _, err := s.Open(key, uuid.NewV4(), ciphertext); err == nil {
+		t.Fatal("opened with another tender id")
+	}
+	otherKey, _ := sealTestEnvelope(t, s, uuid.NewV4(), []byte("bid"))
+	if _, err := s.Open(otherKey, tenderId, ciphertext); err == nil {
+		t.Fatal("opened with the key of another tender")
+	}
+}
+
+func TestOpenWrongMasterKey(t *testing.T) {
+	tenderId := uuid.NewV4()
+	key, ciphertext := sealTestEnvelope(t, newTestSealer(t, 1), tenderId, []byte("bid"))
+	if _, err := newTestSealer(t, 2).Open(key, tenderId, ciphertext); err == nil {
+		t.Fatal("opened with another master key")
+	}
+}
+
+func TestOpenTampered(t *testing.T) {
+	s := newTestSealer(t, 1)
+	tenderId := uuid.NewV4()
+	key, ciphertext := sealTestEnvelope(t, s, tenderId, []byte("bid"))
+
+	for i := range ciphertext {
+		tampered := bytes.Clone(ciphertext)
+		tampered[i] ^= 0x01
+		if _, err := s.Open(key, tenderId, tampered); err == nil {
+			t.Fatalf("opened with byte %d flipped", i)
+		}
+	}
+	wrappedKey := bytes.Clone(key)
+	wrappedKey[len(wrappedKey)-1] ^= 0x01
+	if _, err := s.Open(wrappedKey, tenderId, ciphertext); err == nil {
+		t.Fatal("opened with a tampered data key")
+	}
+	if _, err := s.Open(key, tenderId, ciphertext[:4]); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("truncated ciphertext: err = %v, want %v", err, ErrMalformed)
+	}
+}
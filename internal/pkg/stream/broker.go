@@ -0,0 +1,57 @@
+package stream
+
+import (
+	"sync"
+
+	"zadanie-6105/internal/models"
+)
+
+const subscriberBuffer = 64
+
+type subscriber struct {
+	ch     chan *models.Event
+	filter func(event *models.Event) bool
+}
+
+// Broker fans events out to the streams connected to this replica.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[*subscriber]struct{})}
+}
+
+func (b *Broker) Subscribe(filter func(event *models.Event) bool) (<-chan *models.Event, func()) {
+	sub := &subscriber{ch: make(chan *models.Event, subscriberBuffer), filter: filter}
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub.ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[sub]; ok {
+			delete(b.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+func (b *Broker) Publish(event *models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers {
+		if !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			// A slow client is disconnected; it resumes with Last-Event-ID.
+			delete(b.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/stream"
+	"zadanie-6105/internal/pkg/utils"
+)
+
+type StreamHandler struct {
+	u         stream.StreamUsecase
+	heartbeat time.Duration
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewHandler(u stream.StreamUsecase, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{u: u, heartbeat: heartbeat, done: make(chan struct{})}
+}
+
+// Shutdown ends all open streams so that the server can stop gracefully.
+func (h *StreamHandler) Shutdown() {
+	h.closeOnce.Do(func() {
+		close(h.done)
+	})
+}
+
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	lastEventIdStr := r.Header.Get("Last-Event-ID")
+	if lastEventIdStr == "" {
+		lastEventIdStr = r.URL.Query().Get("lastEventId")
+	}
+	var lastEventId int64
+	if lastEventIdStr != "" {
+		var err error
+		lastEventId, err = strconv.ParseInt(lastEventIdStr, 10, 64)
+		if err != nil || lastEventId < 0 {
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		}
+	}
+
+	rc := http.NewResponseController(w)
+	// The stream outlives the server write timeout.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	sub, err := h.u.Subscribe(username, lastEventId)
+	if err != nil {
+		if errors.Is(err, myErrors.ErrUserNotFound) {
+			utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Outbox events are dispatched at least once and not strictly in id order,
+	// so duplicates are recognised by id rather than by the highest id sent.
+	seen := newSeenIds(max(dedupWindow, len(sub.Backlog)))
+	for _, event := range sub.Backlog {
+		if err = writeEvent(w, event); err != nil {
+			return
+		}
+		seen.add(event.Id)
+	}
+	if err = rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.done:
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if !seen.add(event.Id) {
+				continue
+			}
+			if err = writeEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err = fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err = rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// dedupWindow is how many recently sent event ids a stream remembers.
+const dedupWindow = 1024
+
+// seenIds remembers the last size ids in insertion order.
+type seenIds struct {
+	ids  map[int64]struct{}
+	ring []int64
+	next int
+}
+
+func newSeenIds(size int) *seenIds {
+	return &seenIds{ids: make(map[int64]struct{}, size), ring: make([]int64, 0, size)}
+}
+
+// add records id and reports whether it was new.
+func (s *seenIds) add(id int64) bool {
+	if _, ok := s.ids[id]; ok {
+		return false
+	}
+	if len(s.ring) < cap(s.ring) {
+		s.ring = append(s.ring, id)
+	} else {
+		delete(s.ids, s.ring[s.next])
+		s.ring[s.next] = id
+		s.next = (s.next + 1) % len(s.ring)
+	}
+	s.ids[id] = struct{}{}
+	return true
+}
+
+func writeEvent(w http.ResponseWriter, event *models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data)
+	return err
+}
@@ -0,0 +1,23 @@
+package http
+
+import "testing"
+
+func TestSeenIds(t *testing.T) {
+	seen := newSeenIds(3)
+	// Out-of-order ids are new, repeats are not.
+	for _, id := range []int64{5, 3, 4} {
+		if !seen.add(id) {
+			t.Fatalf("id %d reported as seen", id)
+		}
+	}
+	if seen.add(3) {
+		t.Fatal("repeated id 3 reported as new")
+	}
+	// The oldest id falls out of the window.
+	if !seen.add(6) || !seen.add(5) {
+		t.Fatal("window did not evict the oldest id")
+	}
+	if seen.add(4) || seen.add(6) {
+		t.Fatal("ids inside the window reported as new")
+	}
+}
@@ -0,0 +1,26 @@
+package stream
+
+import (
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+)
+
+type StreamRepository interface {
+	SelectUserId(username string) (uuid.UUID, error)
+	SelectUserOrganizations(username string) ([]uuid.UUID, error)
+	SelectEvent(eventId int64) (*models.Event, error)
+	SelectEventsAfter(eventId int64, organizationIds []uuid.UUID, userId uuid.UUID, limit int) ([]*models.Event, error)
+	Notify(eventId int64) error
+}
+
+type StreamUsecase interface {
+	Subscribe(username string, lastEventId int64) (*Subscription, error)
+}
+
+// Subscription holds the events missed since Last-Event-ID followed by a live
+// feed. Events is closed when the subscriber falls too far behind.
+type Subscription struct {
+	Backlog []*models.Event
+	Events  <-chan *models.Event
+	Close   func()
+}
@@ -0,0 +1,90 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"zadanie-6105/internal/models"
+)
+
+const Channel = "domain_events"
+
+// Listener receives event ids published with NOTIFY by any replica and
+// forwards the events to the local broker.
+type Listener struct {
+	conn   string
+	r      StreamRepository
+	broker *Broker
+	log    *logrus.Logger
+}
+
+func NewListener(conn string, r StreamRepository, broker *Broker, log *logrus.Logger) *Listener {
+	return &Listener{conn: conn, r: r, broker: broker, log: log}
+}
+
+func (l *Listener) Run(ctx context.Context) {
+	listener := pq.NewListener(l.conn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			l.log.Warn("stream: listener: ", err.Error())
+		}
+	})
+	defer listener.Close()
+
+	// The database may not accept connections yet; keep trying with backoff.
+	for backoff := time.Second; ; backoff = min(2*backoff, time.Minute) {
+		err := listener.Listen(Channel)
+		if err == nil || errors.Is(err, pq.ErrChannelAlreadyOpen) {
+			break
+		}
+		l.log.Error("stream: failed to listen, retrying in ", backoff, ": ", err.Error())
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-listener.Notify:
+			if n == nil {
+				continue
+			}
+			eventId, err := strconv.ParseInt(n.Extra, 10, 64)
+			if err != nil {
+				continue
+			}
+			event, err := l.r.SelectEvent(eventId)
+			if err != nil {
+				l.log.Warn("stream: failed to load event: ", err.Error())
+				continue
+			}
+			l.broker.Publish(event)
+		case <-time.After(90 * time.Second):
+			go listener.Ping()
+		}
+	}
+}
+
+// NotifySink is an outbox sink that announces dispatched events to all replicas.
+type NotifySink struct {
+	r StreamRepository
+}
+
+func NewNotifySink(r StreamRepository) *NotifySink {
+	return &NotifySink{r: r}
+}
+
+func (s *NotifySink) Name() string {
+	return "stream"
+}
+
+func (s *NotifySink) Deliver(_ context.Context, event *models.Event) error {
+	return s.r.Notify(event.Id)
+}
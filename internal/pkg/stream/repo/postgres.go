@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/lib/pq"
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/stream"
+)
+
+type StreamRepoPostgres struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *StreamRepoPostgres {
+	return &StreamRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *StreamRepoPostgres) SelectUserOrganizations(username string) ([]uuid.UUID, error) {
+	query := `
+		SELECT o_r.organization_id
+		FROM organization_responsible AS o_r
+		JOIN employee AS e ON e.id = o_r.user_id
+		WHERE e.username = $1`
+
+	rows, err := r.db.Query(query, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *StreamRepoPostgres) SelectUserId(username string) (uuid.UUID, error) {
+	var userId uuid.UUID
+	err := r.db.QueryRow(`SELECT id FROM employee WHERE username = $1`, username).Scan(&userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, myErrors.ErrUserNotFound
+		}
+		return uuid.Nil, err
+	}
+	return userId, nil
+}
+
+func (r *StreamRepoPostgres) SelectEvent(eventId int64) (*models.Event, error) {
+	query := `
+		SELECT id, event_type, aggregate_type, aggregate_id, tender_id, organization_id, recipient_id, payload, created_at
+		FROM outbox_event
+		WHERE id = $1`
+
+	var event models.Event
+	err := r.db.QueryRow(query, eventId).Scan(&event.Id, &event.Type, &event.AggregateType, &event.AggregateId,
+		&event.TenderId, &event.OrganizationId, &event.RecipientId, &event.Payload, &event.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrBadRequest
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+// SelectEventsAfter returns the dispatched events after eventId addressed to
+// one of the organizations or to the employee.
+func (r *StreamRepoPostgres) SelectEventsAfter(eventId int64, organizationIds []uuid.UUID, userId uuid.UUID, limit int) ([]*models.Event, error) {
+	ids := make([]string, 0, len(organizationIds))
+	for _, id := range organizationIds {
+		ids = append(ids, id.String())
+	}
+	query := `
+		SELECT id, event_type, aggregate_type, aggregate_id, tender_id, organization_id, recipient_id, payload, created_at
+		FROM outbox_event
+		WHERE id > $1 AND (organization_id = ANY($2::uuid[]) OR recipient_id = $4) AND dispatched_at IS NOT NULL
+		ORDER BY id
+		LIMIT $3`
+
+	rows, err := r.db.Query(query, eventId, pq.Array(ids), limit, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*models.Event
+	for rows.Next() {
+		var event models.Event
+		if err = rows.Scan(&event.Id, &event.Type, &event.AggregateType, &event.AggregateId,
+			&event.TenderId, &event.OrganizationId, &event.RecipientId, &event.Payload, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+func (r *StreamRepoPostgres) Notify(eventId int64) error {
+	_, err := r.db.Exec(`SELECT pg_notify($1, $2)`, stream.Channel, strconv.FormatInt(eventId, 10))
+	return err
+}
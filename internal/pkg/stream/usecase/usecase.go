@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/pkg/stream"
+)
+
+type StreamUsecase struct {
+	r           stream.StreamRepository
+	broker      *stream.Broker
+	replayLimit int
+}
+
+func NewUsecase(r stream.StreamRepository, broker *stream.Broker, replayLimit int) *StreamUsecase {
+	return &StreamUsecase{
+		r:           r,
+		broker:      broker,
+		replayLimit: replayLimit,
+	}
+}
+
+// Subscribe streams events of tenders owned by the caller's organizations,
+// of bids on them and events addressed to the caller personally. With
+// lastEventId > 0 the missed events are replayed first.
+func (u *StreamUsecase) Subscribe(username string, lastEventId int64) (*stream.Subscription, error) {
+	userId, err := u.r.SelectUserId(username)
+	if err != nil {
+		return nil, err
+	}
+	organizationIds, err := u.r.SelectUserOrganizations(username)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make(map[uuid.UUID]struct{}, len(organizationIds))
+	for _, id := range organizationIds {
+		allowed[id] = struct{}{}
+	}
+
+	// Subscribe before reading the backlog so that nothing published in
+	// between is lost; duplicates are dropped by the handler using event ids.
+	events, unsubscribe := u.broker.Subscribe(func(event *models.Event) bool {
+		if event.RecipientId != nil && *event.RecipientId == userId {
+			return true
+		}
+		_, ok := allowed[event.OrganizationId]
+		return ok
+	})
+
+	sub := &stream.Subscription{Events: events, Close: unsubscribe}
+	if lastEventId > 0 {
+		sub.Backlog, err = u.r.SelectEventsAfter(lastEventId, organizationIds, userId, u.replayLimit)
+		if err != nil {
+			unsubscribe()
+			return nil, err
+		}
+	}
+	return sub, nil
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/pkg/stream"
+)
+
+// fakeRepo implements just enough of the repository for the tests; any other
+// call panics on the embedded nil interface.
+type fakeRepo struct {
+	stream.StreamRepository
+	userId        uuid.UUID
+	organizations []uuid.UUID
+}
+
+func (r *fakeRepo) SelectUserId(string) (uuid.UUID, error) {
+	return r.userId, nil
+}
+
+func (r *fakeRepo) SelectUserOrganizations(string) ([]uuid.UUID, error) {
+	return r.organizations, nil
+}
+
+func TestSubscribeDeliversPersonalEvents(t *testing.T) {
+	repo := &fakeRepo{userId: uuid.NewV4()}
+	broker := stream.NewBroker()
+	sub, err := NewUsecase(repo, broker, 10).Subscribe("bidder", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	other := uuid.NewV4()
+	broker.Publish(&models.Event{Id: 1, OrganizationId: uuid.NewV4()})
+	broker.Publish(&models.Event{Id: 2, RecipientId: &other})
+	broker.Publish(&models.Event{Id: 3, RecipientId: &repo.userId})
+
+	select {
+	case event := <-sub.Events:
+		if event.Id != 3 {
+			t.Fatalf("got event %d, want 3", event.Id)
+		}
+	default:
+		t.Fatal("personal event not delivered")
+	}
+	select {
+	case event := <-sub.Events:
+		t.Fatalf("unexpected event %d", event.Id)
+	default:
+	}
+}
@@ -27,7 +27,7 @@ func (h *TenderHandler) GetTendersList(w http.ResponseWriter, r *http.Request) {
 	}
 	serviceType := r.URL.Query()["service_type"]
 
-	tendersList, err := h.u.GetTendersList(limit, offset, serviceType)
+	tendersList, err := h.u.GetTendersList(limit, offset, serviceType, r.URL.Query().Get("username"))
 	if err != nil {
 		if !errors.Is(err, myErrors.ErrBadRequest) {
 			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
@@ -43,7 +43,7 @@ func (h *TenderHandler) GetTendersList(w http.ResponseWriter, r *http.Request) {
 func (h *TenderHandler) CreateNewTender(w http.ResponseWriter, r *http.Request) {
 	var tenderData *models.TendersRequest
 	if err := utils.ReadRequestData(r, &tenderData); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		utils.WriteRequestError(w, err)
 		return
 	}
 	newTender, err := h.u.CreateNewTender(tenderData)
@@ -52,6 +52,9 @@ func (h *TenderHandler) CreateNewTender(w http.ResponseWriter, r *http.Request)
 		case errors.Is(err, myErrors.ErrBadRequest):
 			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
 			return
+		case errors.Is(err, myErrors.ErrSealingDisabled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrSealingDisabled)
+			return
 		case errors.Is(err, myErrors.ErrForbidden):
 			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
 			return
@@ -87,6 +90,35 @@ func (h *TenderHandler) GetUserTenders(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJSON(w, http.StatusOK, tendersList)
 }
 
+func (h *TenderHandler) GetTender(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	tender, err := h.u.GetTender(tenderId, r.URL.Query().Get("username"))
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		case errors.Is(err, myErrors.ErrNotInvited):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrNotInvited)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, tender)
+}
+
 func (h *TenderHandler) GetTenderStatus(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	tenderIdStr := vars["tenderId"]
@@ -129,6 +161,9 @@ func (h *TenderHandler) EditTenderStatus(w http.ResponseWriter, r *http.Request)
 		case errors.Is(err, myErrors.ErrBadRequest):
 			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
 			return
+		case errors.Is(err, myErrors.ErrTenderCanceled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderCanceled)
+			return
 		case errors.Is(err, myErrors.ErrForbidden):
 			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
 			return
@@ -146,6 +181,74 @@ func (h *TenderHandler) EditTenderStatus(w http.ResponseWriter, r *http.Request)
 	utils.WriteJSON(w, http.StatusOK, tender)
 }
 
+func (h *TenderHandler) CancelTender(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var cancellation *models.CancelRequest
+	if err = utils.ReadRequestData(r, &cancellation); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	tender, err := h.u.CancelTender(tenderId, username, cancellation)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotCancelable):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderNotCancelable)
+			return
+		case errors.Is(err, myErrors.ErrUserNotFound):
+			utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, tender)
+}
+
+func (h *TenderHandler) GetAward(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	award, err := h.u.GetAward(tenderId, r.URL.Query().Get("username"))
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrNotInvited):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrNotInvited)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		case errors.Is(err, myErrors.ErrAwardNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrAwardNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, award)
+}
+
 func (h *TenderHandler) EditTender(w http.ResponseWriter, r *http.Request) {
 	var editedData *models.TenderEditRequest
 	vars := mux.Vars(r)
@@ -161,7 +264,7 @@ func (h *TenderHandler) EditTender(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err = utils.ReadRequestData(r, &editedData); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		utils.WriteRequestError(w, err)
 		return
 	}
 	tender, err := h.u.EditTender(tenderId, username, editedData)
@@ -176,6 +279,273 @@ func (h *TenderHandler) EditTender(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, myErrors.ErrUserNotFound):
 			utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
 			return
+		case errors.Is(err, myErrors.ErrWrongPhase):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrWrongPhase)
+			return
+		case errors.Is(err, myErrors.ErrAuctionAfterDeadline):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrAuctionAfterDeadline)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotEditable):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderNotEditable)
+			return
+		case errors.Is(err, myErrors.ErrCurrencyLocked):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrCurrencyLocked)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, tender)
+}
+
+func (h *TenderHandler) SchedulePublication(w http.ResponseWriter, r *http.Request) {
+	var publication *models.PublicationRequest
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	if err = utils.ReadRequestData(r, &publication); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	tender, err := h.u.SchedulePublication(tenderId, username, publication)
+	if err != nil {
+		writePublicationError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, tender)
+}
+
+func (h *TenderHandler) CancelPublication(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	tender, err := h.u.CancelPublication(tenderId, username)
+	if err != nil {
+		writePublicationError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, tender)
+}
+
+func writePublicationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, myErrors.ErrBadRequest):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+	case errors.Is(err, myErrors.ErrPublicationNotAllowed):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrPublicationNotAllowed)
+	case errors.Is(err, myErrors.ErrUserNotFound):
+		utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
+	case errors.Is(err, myErrors.ErrTenderNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+	}
+}
+
+func (h *TenderHandler) GetTenderItems(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	items, err := h.u.GetTenderItems(tenderId, r.URL.Query().Get("username"))
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		case errors.Is(err, myErrors.ErrNotInvited):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrNotInvited)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	if items == nil {
+		items = []*models.TenderItem{}
+	}
+	utils.WriteJSON(w, http.StatusOK, items)
+}
+
+func (h *TenderHandler) ReplaceTenderItems(w http.ResponseWriter, r *http.Request) {
+	var items []models.TenderItemRequest
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	if err = utils.ReadRequestData(r, &items); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	tender, err := h.u.ReplaceTenderItems(tenderId, username, items)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotEditable):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderNotEditable)
+			return
+		case errors.Is(err, myErrors.ErrUserNotFound):
+			utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, tender)
+}
+
+func (h *TenderHandler) ConfigureAuction(w http.ResponseWriter, r *http.Request) {
+	var auctionData *models.AuctionRequest
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	if err = utils.ReadRequestData(r, &auctionData); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	auction, err := h.u.ConfigureAuction(tenderId, username, auctionData)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrAuctionStarted):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrAuctionStarted)
+			return
+		case errors.Is(err, myErrors.ErrAuctionNotAllowed):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrAuctionNotAllowed)
+			return
+		case errors.Is(err, myErrors.ErrAuctionAfterDeadline):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrAuctionAfterDeadline)
+			return
+		case errors.Is(err, myErrors.ErrTenderCanceled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderCanceled)
+			return
+		case errors.Is(err, myErrors.ErrUserNotFound):
+			utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, auction)
+}
+
+func (h *TenderHandler) GetTenderLots(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	lots, err := h.u.GetTenderLots(tenderId, r.URL.Query().Get("username"))
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		case errors.Is(err, myErrors.ErrNotInvited):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrNotInvited)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	if lots == nil {
+		lots = []*models.Lot{}
+	}
+	utils.WriteJSON(w, http.StatusOK, lots)
+}
+
+func (h *TenderHandler) ReplaceTenderLots(w http.ResponseWriter, r *http.Request) {
+	var lots []models.LotRequest
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	if err = utils.ReadRequestData(r, &lots); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	tender, err := h.u.ReplaceTenderLots(tenderId, username, lots)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotEditable):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderNotEditable)
+			return
+		case errors.Is(err, myErrors.ErrUserNotFound):
+			utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
+			return
 		case errors.Is(err, myErrors.ErrTenderNotFound):
 			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
 			return
@@ -186,3 +556,188 @@ func (h *TenderHandler) EditTender(w http.ResponseWriter, r *http.Request) {
 	}
 	utils.WriteJSON(w, http.StatusOK, tender)
 }
+
+func (h *TenderHandler) CancelLot(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	lotId, err := uuid.FromString(vars["lotId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	lot, err := h.u.CancelLot(tenderId, lotId, username)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrLotClosed):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrLotClosed)
+			return
+		case errors.Is(err, myErrors.ErrLotNotClosable):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrLotNotClosable)
+			return
+		case errors.Is(err, myErrors.ErrTenderCanceled):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrTenderCanceled)
+			return
+		case errors.Is(err, myErrors.ErrUserNotFound):
+			utils.WriteError(w, http.StatusUnauthorized, myErrors.ErrUserNotFound)
+			return
+		case errors.Is(err, myErrors.ErrTenderNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+			return
+		case errors.Is(err, myErrors.ErrLotNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrLotNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, lot)
+}
+
+func (h *TenderHandler) GetTenderInvitations(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	invitations, err := h.u.GetTenderInvitations(tenderId, username)
+	if err != nil {
+		writeInvitationError(w, err)
+		return
+	}
+	if invitations == nil {
+		invitations = []*models.Invitation{}
+	}
+	utils.WriteJSON(w, http.StatusOK, invitations)
+}
+
+func (h *TenderHandler) InviteToTender(w http.ResponseWriter, r *http.Request) {
+	tenderId, err := uuid.FromString(mux.Vars(r)["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var invitationData *models.InvitationRequest
+	if err = utils.ReadRequestData(r, &invitationData); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	invitation, err := h.u.InviteToTender(tenderId, username, invitationData)
+	if err != nil {
+		writeInvitationError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, invitation)
+}
+
+func (h *TenderHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenderId, err := uuid.FromString(vars["tenderId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	invitationId, err := uuid.FromString(vars["invitationId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	if err = h.u.RevokeInvitation(tenderId, invitationId, username); err != nil {
+		writeInvitationError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TenderHandler) GetUserInvitations(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := utils.ReadLimitOffset(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	invitations, err := h.u.GetUserInvitations(limit, offset, username)
+	if err != nil {
+		writeInvitationError(w, err)
+		return
+	}
+	if invitations == nil {
+		invitations = []*models.Invitation{}
+	}
+	utils.WriteJSON(w, http.StatusOK, invitations)
+}
+
+func (h *TenderHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	h.respondInvitation(w, r, models.InvitationAccepted)
+}
+
+func (h *TenderHandler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	h.respondInvitation(w, r, models.InvitationDeclined)
+}
+
+func (h *TenderHandler) respondInvitation(w http.ResponseWriter, r *http.Request, status models.InvitationStatus) {
+	invitationId, err := uuid.FromString(mux.Vars(r)["invitationId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	invitation, err := h.u.RespondInvitation(invitationId, username, status)
+	if err != nil {
+		writeInvitationError(w, err)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, invitation)
+}
+
+func writeInvitationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, myErrors.ErrBadRequest):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+	case errors.Is(err, myErrors.ErrInvitationResponded):
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrInvitationResponded)
+	case errors.Is(err, myErrors.ErrForbidden):
+		utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+	case errors.Is(err, myErrors.ErrTenderNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrTenderNotFound)
+	case errors.Is(err, myErrors.ErrInvitationNotFound):
+		utils.WriteError(w, http.StatusNotFound, myErrors.ErrInvitationNotFound)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+	}
+}
@@ -2,25 +2,80 @@ package tenders
 
 import (
 	"github.com/satori/uuid"
+	"time"
 	"zadanie-6105/internal/models"
 )
 
 type TenderRepoPostgres interface {
-	SelectTendersList(limit, offset int32, serviceType []string) ([]*models.TendersResponse, error)
+	SelectTendersList(limit, offset int32, serviceType []string, username string) ([]*models.TendersResponse, error)
 	CheckUsernameOrganization(creatorUsername string, organizationId uuid.UUID) (bool, error)
 	CheckUsernameTender(username string, tenderId uuid.UUID) (bool, error)
-	CreateTender(tenderData *models.TendersRequest) (*models.TendersResponse, error)
+	CheckTenderVisible(username string, tenderId uuid.UUID) (bool, error)
+	CheckBidderInvited(tenderId uuid.UUID, authorType models.TypeAuthor, authorId uuid.UUID) (bool, error)
+	CreateTender(tenderData *models.TendersRequest, publication *models.Publication,
+		newKey func(tenderId uuid.UUID) ([]byte, error)) (*models.TendersResponse, error)
 	SelectUserTenders(limit, offset int32, username string) ([]*models.TendersResponse, error)
+	SelectTender(tenderId uuid.UUID) (*models.TendersResponse, error)
+	SelectTenderDetails(tenderId uuid.UUID) (*models.TenderDetails, error)
+	SelectOrganization(organizationId uuid.UUID) (*models.OrganizationInfo, error)
+	SelectTenderKey(tenderId uuid.UUID) ([]byte, error)
 	SelectTenderStatus(tenderId uuid.UUID) (string, error)
 	EditStatusTender(tenderId uuid.UUID, status string) (*models.TendersResponse, error)
+	CancelTender(tenderId uuid.UUID, reason string) (*models.TendersResponse, error)
+	SelectAward(tenderId uuid.UUID) (*models.Award, error)
+	SelectLotAward(lotId uuid.UUID) (*models.Award, error)
+	SelectLotAwards(tenderId uuid.UUID) ([]*models.Award, error)
 	EditTender(tenderId uuid.UUID, editedData *models.TenderEditRequest) (*models.TendersResponse, error)
+	CloseExpiredTenders(now time.Time) ([]*models.TendersResponse, error)
+	SchedulePublication(tenderId uuid.UUID, publication *models.Publication) (*models.TendersResponse, error)
+	SelectDuePublications(now time.Time) ([]*models.ScheduledPublication, error)
+	FailPublication(tenderId uuid.UUID, reason string, retryAt *time.Time) error
+	SelectTenderItems(tenderId uuid.UUID) ([]*models.TenderItem, error)
+	ReplaceTenderItems(tenderId uuid.UUID, items []models.TenderItemRequest) (*models.TendersResponse, error)
+	SelectAuction(tenderId uuid.UUID) (*models.Auction, error)
+	UpsertAuction(tenderId uuid.UUID, auction *models.AuctionRequest, now time.Time) (*models.Auction, error)
+	SelectTenderLots(tenderId uuid.UUID) ([]*models.Lot, error)
+	ReplaceTenderLots(tenderId uuid.UUID, lots []models.LotRequest) (*models.TendersResponse, error)
+	CancelLot(tenderId, lotId uuid.UUID) (*models.Lot, error)
+	DecideLot(lotId, bidId uuid.UUID, decision models.TypeDecision, username string) (*models.Lot, error)
+	CreateInvitation(tenderId uuid.UUID, invitation *models.InvitationRequest, invitedBy string) (*models.Invitation, error)
+	SelectTenderInvitations(tenderId uuid.UUID) ([]*models.Invitation, error)
+	SelectUserInvitations(limit, offset int32, username string) ([]*models.Invitation, error)
+	SelectInvitation(invitationId uuid.UUID) (*models.Invitation, error)
+	CheckInvitee(invitationId uuid.UUID, username string) (bool, error)
+	RespondInvitation(invitationId uuid.UUID, username string, status models.InvitationStatus) (*models.Invitation, error)
+	DeleteInvitation(invitationId uuid.UUID) error
+}
+
+type Sealer interface {
+	NewTenderKey(tenderId uuid.UUID) ([]byte, error)
+	Seal(wrappedKey []byte, tenderId uuid.UUID, plaintext []byte) ([]byte, error)
+	Open(wrappedKey []byte, tenderId uuid.UUID, ciphertext []byte) ([]byte, error)
 }
 
 type TenderUsecase interface {
-	GetTendersList(limit, offset int32, serviceType []string) ([]*models.TendersResponse, error)
+	GetTendersList(limit, offset int32, serviceType []string, username string) ([]*models.TendersResponse, error)
 	CreateNewTender(tenderData *models.TendersRequest) (*models.TendersResponse, error)
 	GetUserTender(limit, offset int32, username string) ([]*models.TendersResponse, error)
+	GetTender(tenderId uuid.UUID, username string) (*models.TenderDetails, error)
 	GetTenderStatus(tenderId uuid.UUID, username string) (string, error)
 	EditTenderStatus(tenderId uuid.UUID, username, status string) (*models.TendersResponse, error)
+	CancelTender(tenderId uuid.UUID, username string, cancellation *models.CancelRequest) (*models.TendersResponse, error)
+	GetAward(tenderId uuid.UUID, username string) (*models.TenderAward, error)
 	EditTender(tenderId uuid.UUID, username string, editedData *models.TenderEditRequest) (*models.TendersResponse, error)
+	CloseExpiredTenders(now time.Time) ([]*models.TendersResponse, error)
+	SchedulePublication(tenderId uuid.UUID, username string, publication *models.PublicationRequest) (*models.TendersResponse, error)
+	CancelPublication(tenderId uuid.UUID, username string) (*models.TendersResponse, error)
+	PublishScheduledTenders(now time.Time) ([]*models.TendersResponse, error)
+	GetTenderItems(tenderId uuid.UUID, username string) ([]*models.TenderItem, error)
+	ReplaceTenderItems(tenderId uuid.UUID, username string, items []models.TenderItemRequest) (*models.TendersResponse, error)
+	ConfigureAuction(tenderId uuid.UUID, username string, auction *models.AuctionRequest) (*models.Auction, error)
+	GetTenderLots(tenderId uuid.UUID, username string) ([]*models.Lot, error)
+	ReplaceTenderLots(tenderId uuid.UUID, username string, lots []models.LotRequest) (*models.TendersResponse, error)
+	CancelLot(tenderId, lotId uuid.UUID, username string) (*models.Lot, error)
+	GetTenderInvitations(tenderId uuid.UUID, username string) ([]*models.Invitation, error)
+	InviteToTender(tenderId uuid.UUID, username string, invitation *models.InvitationRequest) (*models.Invitation, error)
+	RevokeInvitation(tenderId, invitationId uuid.UUID, username string) error
+	GetUserInvitations(limit, offset int32, username string) ([]*models.Invitation, error)
+	RespondInvitation(invitationId uuid.UUID, username string, status models.InvitationStatus) (*models.Invitation, error)
 }
@@ -5,10 +5,16 @@ import (
 	"fmt"
 	"github.com/lib/pq"
 	"github.com/satori/uuid"
+	"time"
 	"zadanie-6105/internal/models"
 	"zadanie-6105/internal/myErrors"
+	repoOutbox "zadanie-6105/internal/pkg/outbox/repo"
 )
 
+const tenderColumns = `id, name, description, status, service_type, organization_id, created_at, version, submission_deadline, publish_at, publish_timezone,
+    estimated_budget, max_price, currency, sealed, opening_at, opened_at, evaluation_phase, clarification_deadline, visibility,
+    cancellation_reason, canceled_at, publish_error`
+
 type TenderRepoPostgres struct {
 	db *sql.DB
 }
@@ -19,17 +25,17 @@ func NewRepository(db *sql.DB) *TenderRepoPostgres {
 	}
 }
 
-func (r *TenderRepoPostgres) SelectTendersList(limit, offset int32, serviceType []string) ([]*models.TendersResponse, error) {
+func (r *TenderRepoPostgres) SelectTendersList(limit, offset int32, serviceType []string, username string) ([]*models.TendersResponse, error) {
 	var args []interface{}
-	args = append(args, models.StatusPublished, limit, offset)
+	args = append(args, models.StatusPublished, limit, offset, username)
 
 	query := `
-        SELECT id, name, description, status, service_type, created_at, version, organization_id
+        SELECT ` + tenderColumns + `
         FROM tender
-        WHERE status = $1`
+        WHERE status = $1 AND ` + visibleTo("$4")
 
 	if len(serviceType) > 0 && len(serviceType) < 3 {
-		query += " AND service_type = ANY($4)"
+		query += " AND service_type = ANY($5)"
 		args = append(args, pq.Array(serviceType))
 	}
 
@@ -43,16 +49,7 @@ func (r *TenderRepoPostgres) SelectTendersList(limit, offset int32, serviceType
 	}
 	defer rows.Close()
 
-	var tenders []*models.TendersResponse
-	for rows.Next() {
-		var tender models.TendersResponse
-		if err = rows.Scan(&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServiceType, &tender.CreatedAt, &tender.Version, &tender.OrganizationId); err != nil {
-			return nil, err
-		}
-		tenders = append(tenders, &tender)
-	}
-
-	return tenders, nil
+	return scanTenders(rows)
 }
 func (r *TenderRepoPostgres) CheckUsernameOrganization(creatorUsername string, organizationId uuid.UUID) (bool, error) {
 	query := `
@@ -87,24 +84,91 @@ func (r *TenderRepoPostgres) CheckUsernameTender(username string, tenderId uuid.
 	}
 	return count > 0, nil
 }
-func (r *TenderRepoPostgres) CreateTender(tenderData *models.TendersRequest) (*models.TendersResponse, error) {
+
+func (r *TenderRepoPostgres) CheckTenderVisible(username string, tenderId uuid.UUID) (bool, error) {
 	query := `
-        INSERT INTO tender (name, description, service_type, organization_id, creator_username, status)
-        VALUES ($1, $2, $3, $4, $5, $6)
-        RETURNING id, name, description, status, service_type, organization_id, created_at, version`
+        SELECT COUNT(*)
+        FROM tender
+        WHERE id = $1 AND ` + visibleTo("$2")
+
+	var count int
+	err := r.db.QueryRow(query, tenderId, username).Scan(&count)
+	if err != nil {
+		return false, myErrors.ErrBadRequest
+	}
+	return count > 0, nil
+}
+
+// CheckBidderInvited reports whether the bid author holds an accepted
+// invitation: through an invited organization they represent or, when bidding
+// as a user, personally. The author is always an employee.
+func (r *TenderRepoPostgres) CheckBidderInvited(tenderId uuid.UUID, authorType models.TypeAuthor, authorId uuid.UUID) (bool, error) {
+	query := `
+        SELECT COUNT(*)
+        FROM tender_invitation
+        WHERE tender_id = $1 AND status = $4 AND (
+            ($2::text = 'User' AND employee_id = $3)
+            OR organization_id IN (SELECT organization_id FROM organization_responsible WHERE user_id = $3))`
+
+	var count int
+	err := r.db.QueryRow(query, tenderId, authorType, authorId, models.InvitationAccepted).Scan(&count)
+	if err != nil {
+		return false, myErrors.ErrBadRequest
+	}
+	return count > 0, nil
+}
+
+// CreateTender stores the tender; for sealed tenders newKey returns the wrapped
+// bid encryption key, which is saved in the same transaction.
+func (r *TenderRepoPostgres) CreateTender(tenderData *models.TendersRequest, publication *models.Publication,
+	newKey func(tenderId uuid.UUID) ([]byte, error)) (*models.TendersResponse, error) {
+	query := `
+        INSERT INTO tender (name, description, service_type, organization_id, creator_username, status, submission_deadline,
+                            publish_at, publish_timezone, estimated_budget, max_price, currency, sealed, opening_at,
+                            clarification_deadline, visibility)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+        RETURNING ` + tenderColumns
+
+	publishAt, timezone := publicationArgs(publication)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-	var tender models.TendersResponse
-	err := r.db.QueryRow(query, tenderData.Name, tenderData.Description, tenderData.ServiceType, tenderData.OrganizationId, tenderData.CreatorUsername, models.StatusCreated).Scan(
-		&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServiceType, &tender.OrganizationId, &tender.CreatedAt, &tender.Version)
+	tender, err := scanTender(tx.QueryRow(query, tenderData.Name, tenderData.Description, tenderData.ServiceType,
+		tenderData.OrganizationId, tenderData.CreatorUsername, models.StatusCreated, tenderData.SubmissionDeadline,
+		publishAt, timezone, tenderData.EstimatedBudget, tenderData.MaxPrice, tenderData.Currency,
+		tenderData.Sealed, tenderData.OpeningAt, tenderData.ClarificationDeadline, tenderData.Visibility))
 	if err != nil {
 		return nil, myErrors.ErrBadRequest
 	}
 
-	return &tender, nil
+	if tender.Items, err = insertTenderItems(tx, tender.Id, tenderData.Items); err != nil {
+		return nil, err
+	}
+	if tender.Lots, err = insertTenderLots(tx, tender.Id, tenderData.Lots); err != nil {
+		return nil, err
+	}
+	if newKey != nil {
+		key, err := newKey(tender.Id)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = tx.Exec(`INSERT INTO tender_key (tender_id, wrapped_key) VALUES ($1, $2)`, tender.Id, key); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = commitWithEvent(tx, models.NewTenderEvent(models.EventTenderCreated, tender)); err != nil {
+		return nil, err
+	}
+	return tender, nil
 }
 func (r *TenderRepoPostgres) SelectUserTenders(limit, offset int32, username string) ([]*models.TendersResponse, error) {
 	query := `
-        SELECT id, name, description, status, service_type, organization_id, created_at, version
+        SELECT ` + tenderColumns + `
         FROM tender
         WHERE creator_username = $1
         ORDER BY name ASC
@@ -116,17 +180,87 @@ func (r *TenderRepoPostgres) SelectUserTenders(limit, offset int32, username str
 	}
 	defer rows.Close()
 
-	var tenders []*models.TendersResponse
-	for rows.Next() {
-		var tender models.TendersResponse
-		if err = rows.Scan(&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServiceType, &tender.OrganizationId, &tender.CreatedAt, &tender.Version); err != nil {
-			return nil, err
+	return scanTenders(rows)
+}
+func (r *TenderRepoPostgres) SelectTender(tenderId uuid.UUID) (*models.TendersResponse, error) {
+	query := `SELECT ` + tenderColumns + ` FROM tender WHERE id = $1`
+
+	tender, err := scanTender(r.db.QueryRow(query, tenderId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrTenderNotFound
+		}
+		return nil, myErrors.ErrBadRequest
+	}
+	return tender, nil
+}
+
+// SelectTenderDetails loads the tender with its organization, latest version
+// and counts of submitted bids.
+func (r *TenderRepoPostgres) SelectTenderDetails(tenderId uuid.UUID) (*models.TenderDetails, error) {
+	tender, err := r.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+        SELECT organization.id, organization.name, COALESCE(organization.description, ''), COALESCE(organization.type::text, ''),
+               tender.version, tender.updated_at,
+               COUNT(bid.id) FILTER (WHERE bid.status NOT IN ($2, $5)),
+               COUNT(bid.id) FILTER (WHERE bid.decision = $3),
+               COUNT(bid.id) FILTER (WHERE bid.decision = $4)
+        FROM tender
+        JOIN organization ON organization.id = tender.organization_id
+        LEFT JOIN bid ON bid.tender_id = tender.id
+        WHERE tender.id = $1
+        GROUP BY organization.id, tender.id`
+
+	details := &models.TenderDetails{
+		TendersResponse: tender,
+		Organization:    &models.OrganizationInfo{},
+		Bids:            &models.BidCounts{},
+		LatestVersion:   &models.VersionInfo{},
+	}
+	err = r.db.QueryRow(query, tenderId, models.StatusCreated, models.DecisionApproved, models.DecisionRejected,
+		models.StatusCanceled).Scan(
+		&details.Organization.Id, &details.Organization.Name, &details.Organization.Description, &details.Organization.Type,
+		&details.LatestVersion.Version, &details.LatestVersion.UpdatedAt,
+		&details.Bids.Submitted, &details.Bids.Approved, &details.Bids.Rejected)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrTenderNotFound
 		}
-		tenders = append(tenders, &tender)
+		return nil, err
 	}
+	return details, nil
+}
+
+func (r *TenderRepoPostgres) SelectOrganization(organizationId uuid.UUID) (*models.OrganizationInfo, error) {
+	query := `
+        SELECT id, name, COALESCE(description, ''), COALESCE(type::text, '')
+        FROM organization
+        WHERE id = $1`
 
-	return tenders, nil
+	var organization models.OrganizationInfo
+	err := r.db.QueryRow(query, organizationId).Scan(&organization.Id, &organization.Name, &organization.Description, &organization.Type)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrBadRequest
+		}
+		return nil, err
+	}
+	return &organization, nil
+}
 
+func (r *TenderRepoPostgres) SelectTenderKey(tenderId uuid.UUID) ([]byte, error) {
+	var key []byte
+	err := r.db.QueryRow(`SELECT wrapped_key FROM tender_key WHERE tender_id = $1`, tenderId).Scan(&key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrTenderNotFound
+		}
+		return nil, err
+	}
+	return key, nil
 }
 func (r *TenderRepoPostgres) SelectTenderStatus(tenderId uuid.UUID) (string, error) {
 	query := `SELECT status FROM tender WHERE id = $1`
@@ -144,13 +278,17 @@ func (r *TenderRepoPostgres) SelectTenderStatus(tenderId uuid.UUID) (string, err
 func (r *TenderRepoPostgres) EditStatusTender(tenderId uuid.UUID, status string) (*models.TendersResponse, error) {
 	query := `
         UPDATE tender
-        SET status = $1, updated_at = CURRENT_TIMESTAMP
+        SET status = $1, publish_at = NULL, publish_timezone = NULL, updated_at = CURRENT_TIMESTAMP
         WHERE id = $2
-        RETURNING id, name, description, status, service_type, organization_id, created_at, version`
+        RETURNING ` + tenderColumns
 
-	var tender models.TendersResponse
-	err := r.db.QueryRow(query, status, tenderId).Scan(
-		&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServiceType, &tender.OrganizationId, &tender.CreatedAt, &tender.Version)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tender, err := scanTender(tx.QueryRow(query, status, tenderId))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, myErrors.ErrTenderNotFound
@@ -158,7 +296,92 @@ func (r *TenderRepoPostgres) EditStatusTender(tenderId uuid.UUID, status string)
 		return nil, myErrors.ErrBadRequest
 	}
 
-	return &tender, nil
+	if err = commitWithEvent(tx, models.NewTenderEvent(models.TenderStatusEvent(tender.Status), tender)); err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+
+// CancelTender cancels a Created or Published tender with its open lots and
+// bids. The cancellation is a new version of the tender; every bidder's
+// organization is notified with the reason.
+func (r *TenderRepoPostgres) CancelTender(tenderId uuid.UUID, reason string) (*models.TendersResponse, error) {
+	query := `
+        UPDATE tender
+        SET status = $2, cancellation_reason = $3, canceled_at = now(), publish_at = NULL, publish_timezone = NULL,
+            version = version + 1, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status IN ($4, $5)
+        RETURNING ` + tenderColumns
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tender, err := scanTender(tx.QueryRow(query, tenderId, models.StatusCanceled, reason,
+		models.StatusCreated, models.StatusPublished))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrTenderNotCancelable
+		}
+		return nil, err
+	}
+
+	_, err = tx.Exec(`UPDATE tender_lot SET status = $2, closed_at = CURRENT_TIMESTAMP WHERE tender_id = $1 AND status = $3`,
+		tenderId, models.LotCancelled, models.LotOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	queryBids := `
+        WITH canceled AS (
+            UPDATE bid
+            SET status = $2, canceled_by = $5, updated_at = CURRENT_TIMESTAMP
+            WHERE tender_id = $1 AND status IN ($3, $4)
+            RETURNING id, author_id
+        )
+        SELECT canceled.id, canceled.author_id, COALESCE((
+            SELECT organization_id
+            FROM organization_responsible
+            WHERE user_id = canceled.author_id
+            ORDER BY organization_id
+            LIMIT 1), '00000000-0000-0000-0000-000000000000')
+        FROM canceled`
+
+	rows, err := tx.Query(queryBids, tenderId, models.StatusCanceled, models.StatusCreated, models.StatusPublished,
+		models.CanceledByTender)
+	if err != nil {
+		return nil, err
+	}
+	var events []*models.Event
+	for rows.Next() {
+		var bidId, authorId, organizationId uuid.UUID
+		if err = rows.Scan(&bidId, &authorId, &organizationId); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		events = append(events, models.NewBidCancellationEvent(&models.BidCancellation{
+			BidId:      bidId,
+			TenderId:   tenderId,
+			Reason:     reason,
+			CanceledAt: *tender.CanceledAt,
+		}, organizationId, authorId))
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		if err = repoOutbox.Insert(tx, event); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = commitWithEvent(tx, models.NewTenderEvent(models.EventTenderCanceled, tender)); err != nil {
+		return nil, err
+	}
+	return tender, nil
 }
 func (r *TenderRepoPostgres) EditTender(tenderId uuid.UUID, editedData *models.TenderEditRequest) (*models.TendersResponse, error) {
 	query := `UPDATE tender SET `
@@ -180,15 +403,63 @@ func (r *TenderRepoPostgres) EditTender(tenderId uuid.UUID, editedData *models.T
 		args = append(args, editedData.ServiceType)
 		argCounter++
 	}
+	if editedData.SubmissionDeadline != nil {
+		query += `submission_deadline = $` + fmt.Sprint(argCounter) + `, `
+		args = append(args, *editedData.SubmissionDeadline)
+		argCounter++
+	}
+	if editedData.ClarificationDeadline != nil {
+		query += `clarification_deadline = $` + fmt.Sprint(argCounter) + `, `
+		args = append(args, *editedData.ClarificationDeadline)
+		argCounter++
+	}
+	if editedData.EstimatedBudget != nil {
+		query += `estimated_budget = $` + fmt.Sprint(argCounter) + `, `
+		args = append(args, *editedData.EstimatedBudget)
+		argCounter++
+	}
+	if editedData.MaxPrice != nil {
+		query += `max_price = $` + fmt.Sprint(argCounter) + `, `
+		args = append(args, *editedData.MaxPrice)
+		argCounter++
+	}
+	if editedData.Currency != "" {
+		query += `currency = $` + fmt.Sprint(argCounter) + `, `
+		args = append(args, editedData.Currency)
+		argCounter++
+	}
 
 	query += `version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = $` + fmt.Sprint(argCounter)
 	args = append(args, tenderId)
 
-	query += ` RETURNING id, name, description, status, service_type, organization_id, created_at, version`
+	query += ` RETURNING ` + tenderColumns
 
-	var tender models.TendersResponse
-	err := r.db.QueryRow(query, args...).Scan(
-		&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServiceType, &tender.OrganizationId, &tender.CreatedAt, &tender.Version)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Bids are priced in the tender currency, so it is frozen once one exists.
+	if editedData.Currency != "" {
+		queryLocked := `
+        SELECT currency <> $2 AND EXISTS (SELECT 1 FROM bid WHERE bid.tender_id = tender.id)
+        FROM tender
+        WHERE id = $1
+        FOR UPDATE`
+		var locked bool
+		if err = tx.QueryRow(queryLocked, tenderId, editedData.Currency).Scan(&locked); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, myErrors.ErrTenderNotFound
+			}
+			return nil, err
+		}
+		if locked {
+			return nil, myErrors.ErrCurrencyLocked
+		}
+	}
+
+	tender, err := scanTender(tx.QueryRow(query, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, myErrors.ErrTenderNotFound
@@ -196,5 +467,880 @@ func (r *TenderRepoPostgres) EditTender(tenderId uuid.UUID, editedData *models.T
 		return nil, myErrors.ErrBadRequest
 	}
 
+	if err = commitWithEvent(tx, models.NewTenderEvent(models.EventTenderEdited, tender)); err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+func (r *TenderRepoPostgres) CloseExpiredTenders(now time.Time) ([]*models.TendersResponse, error) {
+	query := `
+        UPDATE tender
+        SET status = $1, updated_at = CURRENT_TIMESTAMP
+        WHERE status = $2 AND submission_deadline <= $3
+        RETURNING ` + tenderColumns
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(query, models.StatusClosed, models.StatusPublished, now)
+	if err != nil {
+		return nil, err
+	}
+	closed, err := scanTenders(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tender := range closed {
+		if err = repoOutbox.Insert(tx, models.NewTenderEvent(models.EventTenderClosed, tender)); err != nil {
+			return nil, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return closed, nil
+}
+func (r *TenderRepoPostgres) SchedulePublication(tenderId uuid.UUID, publication *models.Publication) (*models.TendersResponse, error) {
+	query := `
+        UPDATE tender
+        SET publish_at = $1, publish_timezone = $2, publish_attempts = 0, publish_error = NULL, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $3 AND status = $4
+        RETURNING ` + tenderColumns
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	publishAt, timezone := publicationArgs(publication)
+	tender, err := scanTender(tx.QueryRow(query, publishAt, timezone, tenderId, models.StatusCreated))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrPublicationNotAllowed
+		}
+		return nil, myErrors.ErrBadRequest
+	}
+
+	eventType := models.EventPublicationScheduled
+	if publication == nil {
+		eventType = models.EventPublicationCancelled
+	}
+	if err = commitWithEvent(tx, models.NewTenderEvent(eventType, tender)); err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+func (r *TenderRepoPostgres) SelectDuePublications(now time.Time) ([]*models.ScheduledPublication, error) {
+	query := `
+        SELECT id, creator_username, publish_attempts
+        FROM tender
+        WHERE status = $1 AND publish_at <= $2
+        ORDER BY publish_at ASC`
+
+	rows, err := r.db.Query(query, models.StatusCreated, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []*models.ScheduledPublication
+	for rows.Next() {
+		var publication models.ScheduledPublication
+		if err = rows.Scan(&publication.TenderId, &publication.CreatorUsername, &publication.Attempts); err != nil {
+			return nil, err
+		}
+		due = append(due, &publication)
+	}
+	return due, rows.Err()
+}
+
+// FailPublication records a failed scheduled publication. With retryAt the
+// attempt is repeated then; without it the schedule is dropped and the
+// organization is notified.
+func (r *TenderRepoPostgres) FailPublication(tenderId uuid.UUID, reason string, retryAt *time.Time) error {
+	if retryAt != nil {
+		query := `
+        UPDATE tender
+        SET publish_at = $1, publish_attempts = publish_attempts + 1, publish_error = $2
+        WHERE id = $3 AND status = $4 AND publish_at IS NOT NULL`
+		_, err := r.db.Exec(query, *retryAt, reason, tenderId, models.StatusCreated)
+		return err
+	}
+
+	query := `
+        UPDATE tender
+        SET publish_at = NULL, publish_timezone = NULL, publish_attempts = publish_attempts + 1, publish_error = $1,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $2 AND status = $3 AND publish_at IS NOT NULL
+        RETURNING ` + tenderColumns
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	tender, err := scanTender(tx.QueryRow(query, reason, tenderId, models.StatusCreated))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	return commitWithEvent(tx, models.NewTenderEvent(models.EventPublicationFailed, tender))
+}
+
+func (r *TenderRepoPostgres) SelectTenderItems(tenderId uuid.UUID) ([]*models.TenderItem, error) {
+	query := `
+        SELECT id, position, name, quantity, unit, mandatory
+        FROM tender_item
+        WHERE tender_id = $1
+        ORDER BY position ASC`
+
+	rows, err := r.db.Query(query, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*models.TenderItem
+	for rows.Next() {
+		var item models.TenderItem
+		if err = rows.Scan(&item.Id, &item.Position, &item.Name, &item.Quantity, &item.Unit, &item.Mandatory); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	return items, rows.Err()
+}
+func (r *TenderRepoPostgres) ReplaceTenderItems(tenderId uuid.UUID, items []models.TenderItemRequest) (*models.TendersResponse, error) {
+	query := `
+        UPDATE tender
+        SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = $2
+        RETURNING ` + tenderColumns
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tender, err := scanTender(tx.QueryRow(query, tenderId, models.StatusCreated))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrTenderNotEditable
+		}
+		return nil, myErrors.ErrBadRequest
+	}
+
+	if _, err = tx.Exec(`DELETE FROM tender_item WHERE tender_id = $1`, tenderId); err != nil {
+		return nil, err
+	}
+	if tender.Items, err = insertTenderItems(tx, tenderId, items); err != nil {
+		return nil, err
+	}
+
+	if err = commitWithEvent(tx, models.NewTenderEvent(models.EventTenderEdited, tender)); err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+
+func (r *TenderRepoPostgres) SelectAuction(tenderId uuid.UUID) (*models.Auction, error) {
+	query := `
+        SELECT tender_id, starts_at, ends_at, min_decrement, extension_window, extension
+        FROM tender_auction
+        WHERE tender_id = $1`
+
+	auction, err := scanAuction(r.db.QueryRow(query, tenderId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrAuctionNotFound
+		}
+		return nil, err
+	}
+	return auction, nil
+}
+
+// UpsertAuction stores the auction settings unless the auction has already
+// started by now.
+func (r *TenderRepoPostgres) UpsertAuction(tenderId uuid.UUID, auction *models.AuctionRequest, now time.Time) (*models.Auction, error) {
+	query := `
+        INSERT INTO tender_auction (tender_id, starts_at, ends_at, min_decrement, extension_window, extension)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (tender_id) DO UPDATE
+        SET starts_at = EXCLUDED.starts_at, ends_at = EXCLUDED.ends_at, min_decrement = EXCLUDED.min_decrement,
+            extension_window = EXCLUDED.extension_window, extension = EXCLUDED.extension
+        WHERE tender_auction.starts_at > $7
+        RETURNING tender_id, starts_at, ends_at, min_decrement, extension_window, extension`
+
+	saved, err := scanAuction(r.db.QueryRow(query, tenderId, auction.StartsAt, auction.EndsAt, auction.MinDecrement,
+		*auction.ExtensionWindow, *auction.Extension, now))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrAuctionStarted
+		}
+		return nil, err
+	}
+	return saved, nil
+}
+
+func scanAuction(row scanner) (*models.Auction, error) {
+	var auction models.Auction
+	err := row.Scan(&auction.TenderId, &auction.StartsAt, &auction.EndsAt, &auction.MinDecrement,
+		&auction.ExtensionWindow, &auction.Extension)
+	if err != nil {
+		return nil, err
+	}
+	return &auction, nil
+}
+
+func (r *TenderRepoPostgres) SelectTenderLots(tenderId uuid.UUID) ([]*models.Lot, error) {
+	query := `
+        SELECT ` + lotColumns + `
+        FROM tender_lot
+        WHERE tender_id = $1
+        ORDER BY position ASC`
+
+	rows, err := r.db.Query(query, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*models.Lot
+	for rows.Next() {
+		lot, err := scanLot(rows)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+	return lots, rows.Err()
+}
+
+// ReplaceTenderLots swaps the whole lot list while the tender is still in Created status.
+func (r *TenderRepoPostgres) ReplaceTenderLots(tenderId uuid.UUID, lots []models.LotRequest) (*models.TendersResponse, error) {
+	query := `
+        UPDATE tender
+        SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = $2
+        RETURNING ` + tenderColumns
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tender, err := scanTender(tx.QueryRow(query, tenderId, models.StatusCreated))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrTenderNotEditable
+		}
+		return nil, myErrors.ErrBadRequest
+	}
+
+	if _, err = tx.Exec(`DELETE FROM tender_lot WHERE tender_id = $1`, tenderId); err != nil {
+		return nil, err
+	}
+	if tender.Lots, err = insertTenderLots(tx, tenderId, lots); err != nil {
+		return nil, err
+	}
+
+	if err = commitWithEvent(tx, models.NewTenderEvent(models.EventTenderEdited, tender)); err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+
+func (r *TenderRepoPostgres) CancelLot(tenderId, lotId uuid.UUID) (*models.Lot, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	lot, err := lockOpenLot(tx, lotId)
+	if err != nil {
+		return nil, err
+	}
+	if lot.TenderId != tenderId {
+		return nil, myErrors.ErrLotNotFound
+	}
+	organizationId, err := lockLotTender(tx, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+        UPDATE tender_lot
+        SET status = $1, closed_at = CURRENT_TIMESTAMP
+        WHERE id = $2
+        RETURNING ` + lotColumns
+	if lot, err = scanLot(tx.QueryRow(query, models.LotCancelled, lotId)); err != nil {
+		return nil, err
+	}
+	if err = finishLot(tx, models.EventLotCancelled, lot, organizationId); err != nil {
+		return nil, err
+	}
+	return lot, nil
+}
+
+// DecideLot records the decision on one lot of a bid. Approving awards the
+// lot to the bid, records the award and rejects the remaining undecided bids
+// for that lot.
+func (r *TenderRepoPostgres) DecideLot(lotId, bidId uuid.UUID, decision models.TypeDecision, username string) (*models.Lot, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	lot, err := lockOpenLot(tx, lotId)
+	if err != nil {
+		return nil, err
+	}
+	organizationId, err := lockLotTender(tx, lot.TenderId)
+	if err != nil {
+		return nil, err
+	}
+	res, err := tx.Exec(`
+        UPDATE bid_lot
+        SET decision = $1, decided_by = $2, decided_at = CURRENT_TIMESTAMP
+        WHERE bid_id = $3 AND lot_id = $4 AND decision IS NULL`, decision, username, bidId, lotId)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		var exists bool
+		err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM bid_lot WHERE bid_id = $1 AND lot_id = $2)`, bidId, lotId).Scan(&exists)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, myErrors.ErrBidDecided
+		}
+		return nil, myErrors.ErrLotNotFound
+	}
+	if decision != models.DecisionApproved {
+		rejection := &models.LotRejection{TenderId: lot.TenderId, LotId: lotId, BidId: bidId}
+		if err = commitWithEvent(tx, models.NewLotRejectionEvent(rejection, organizationId)); err != nil {
+			return nil, err
+		}
+		return lot, nil
+	}
+
+	_, err = tx.Exec(`
+        UPDATE bid_lot
+        SET decision = $1, decided_by = $2, decided_at = CURRENT_TIMESTAMP
+        WHERE lot_id = $3 AND bid_id <> $4 AND decision IS NULL
+          AND bid_id IN (SELECT id FROM bid WHERE status <> $5)`,
+		models.DecisionRejected, username, lotId, bidId, models.StatusCanceled)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+        UPDATE tender_lot
+        SET status = $1, awarded_bid_id = $2, closed_at = CURRENT_TIMESTAMP
+        WHERE id = $3
+        RETURNING ` + lotColumns
+	if lot, err = scanLot(tx.QueryRow(query, models.LotAwarded, bidId, lotId)); err != nil {
+		return nil, err
+	}
+	award, err := insertLotAward(tx, lotId, bidId, username)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`UPDATE bid SET award_status = $1 WHERE id = $2`, models.AwardStatusAwarded, bidId)
+	if err != nil {
+		return nil, err
+	}
+	if err = repoOutbox.Insert(tx, models.NewAwardEvent(award, organizationId)); err != nil {
+		return nil, err
+	}
+	if err = finishLot(tx, models.EventLotAwarded, lot, organizationId); err != nil {
+		return nil, err
+	}
+	return lot, nil
+}
+
+const lotColumns = `id, tender_id, position, name, description, status, awarded_bid_id, closed_at`
+
+func lockOpenLot(tx *sql.Tx, lotId uuid.UUID) (*models.Lot, error) {
+	lot, err := scanLot(tx.QueryRow(`SELECT `+lotColumns+` FROM tender_lot WHERE id = $1 FOR UPDATE`, lotId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrLotNotFound
+		}
+		return nil, err
+	}
+	if lot.Status != models.LotOpen {
+		return nil, myErrors.ErrLotClosed
+	}
+	return lot, nil
+}
+
+// lockLotTender locks the tender of a lot and returns its organization. Lots
+// are decided and cancelled while the tender is published or closed by its
+// submission deadline; open lots guard against deciding them twice.
+func lockLotTender(tx *sql.Tx, tenderId uuid.UUID) (uuid.UUID, error) {
+	var (
+		organizationId uuid.UUID
+		status         models.TypeStatus
+	)
+	err := tx.QueryRow(`SELECT organization_id, status FROM tender WHERE id = $1 FOR UPDATE`, tenderId).Scan(&organizationId, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, myErrors.ErrTenderNotFound
+		}
+		return uuid.Nil, err
+	}
+	switch {
+	case status.Awardable():
+		return organizationId, nil
+	case status == models.StatusCanceled:
+		return uuid.Nil, myErrors.ErrTenderCanceled
+	default:
+		return uuid.Nil, myErrors.ErrLotNotClosable
+	}
+}
+
+// finishLot records the lot event and, once none of the lots is open any more,
+// marks the bids that won no lot as not awarded and closes the tender, then
+// commits. The tender is locked by lockLotTender.
+func finishLot(tx *sql.Tx, eventType models.EventType, lot *models.Lot, organizationId uuid.UUID) error {
+	if err := repoOutbox.Insert(tx, models.NewLotEvent(eventType, lot, organizationId)); err != nil {
+		return err
+	}
+
+	var open int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM tender_lot WHERE tender_id = $1 AND status = $2`, lot.TenderId, models.LotOpen).Scan(&open); err != nil {
+		return err
+	}
+	if open > 0 {
+		return tx.Commit()
+	}
+	_, err := tx.Exec(`UPDATE bid SET award_status = $1 WHERE tender_id = $2 AND award_status IS NULL AND status <> $3`,
+		models.AwardStatusNotAwarded, lot.TenderId, models.StatusCanceled)
+	if err != nil {
+		return err
+	}
+	tender, err := CloseTender(tx, lot.TenderId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return tx.Commit()
+		}
+		return err
+	}
+	return commitWithEvent(tx, models.NewTenderEvent(models.EventTenderClosed, tender))
+}
+
+// CloseTender closes the tender within tx; sql.ErrNoRows means it was already
+// closed.
+func CloseTender(tx *sql.Tx, tenderId uuid.UUID) (*models.TendersResponse, error) {
+	query := `
+        UPDATE tender
+        SET status = $1, publish_at = NULL, publish_timezone = NULL, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $2 AND status <> $1
+        RETURNING ` + tenderColumns
+	return scanTender(tx.QueryRow(query, models.StatusClosed, tenderId))
+}
+
+const awardColumns = `tender_id, lot_id, bid_id, author_type, author_id, price, COALESCE(currency, ''), awarded_at,
+    awarded_by, ranking_id`
+
+// SelectAward returns the award of a tender without lots.
+func (r *TenderRepoPostgres) SelectAward(tenderId uuid.UUID) (*models.Award, error) {
+	return selectAward(r.db.QueryRow(`SELECT `+awardColumns+` FROM tender_award WHERE tender_id = $1 AND lot_id IS NULL`, tenderId))
+}
+
+// SelectLotAwards returns the awards of the lots of a tender in lot order.
+func (r *TenderRepoPostgres) SelectLotAwards(tenderId uuid.UUID) ([]*models.Award, error) {
+	query := `
+        SELECT ` + awardColumns + `
+        FROM tender_award
+        WHERE tender_id = $1 AND lot_id IS NOT NULL
+        ORDER BY (SELECT position FROM tender_lot WHERE tender_lot.id = tender_award.lot_id)`
+	rows, err := r.db.Query(query, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var awards []*models.Award
+	for rows.Next() {
+		award, err := scanAward(rows)
+		if err != nil {
+			return nil, err
+		}
+		awards = append(awards, award)
+	}
+	return awards, rows.Err()
+}
+
+func (r *TenderRepoPostgres) SelectLotAward(lotId uuid.UUID) (*models.Award, error) {
+	return selectAward(r.db.QueryRow(`SELECT `+awardColumns+` FROM tender_award WHERE lot_id = $1`, lotId))
+}
+
+func selectAward(row scanner) (*models.Award, error) {
+	award, err := scanAward(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrAwardNotFound
+		}
+		return nil, err
+	}
+	return award, nil
+}
+
+func scanAward(row scanner) (*models.Award, error) {
+	var award models.Award
+	err := row.Scan(&award.TenderId, &award.LotId, &award.BidId, &award.AuthorType, &award.AuthorId,
+		&award.Price, &award.Currency, &award.AwardedAt, &award.AwardedBy, &award.RankingId)
+	if err != nil {
+		return nil, err
+	}
+	return &award, nil
+}
+
+// insertLotAward records the winner of a lot. Bids carry no per-lot prices,
+// so the award keeps the price of the whole bid.
+func insertLotAward(tx *sql.Tx, lotId, bidId uuid.UUID, username string) (*models.Award, error) {
+	query := `
+        INSERT INTO tender_award (tender_id, lot_id, bid_id, author_type, author_id, price, currency, awarded_by)
+        SELECT tender_id, $1, id, author_type, author_id, price, currency, $3
+        FROM bid
+        WHERE id = $2
+        RETURNING ` + awardColumns
+	return scanAward(tx.QueryRow(query, lotId, bidId, username))
+}
+
+func insertTenderLots(tx *sql.Tx, tenderId uuid.UUID, lots []models.LotRequest) ([]*models.Lot, error) {
+	query := `
+        INSERT INTO tender_lot (tender_id, position, name, description, status)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING ` + lotColumns
+
+	var inserted []*models.Lot
+	for i, lot := range lots {
+		created, err := scanLot(tx.QueryRow(query, tenderId, i+1, lot.Name, lot.Description, models.LotOpen))
+		if err != nil {
+			return nil, myErrors.ErrBadRequest
+		}
+		inserted = append(inserted, created)
+	}
+	return inserted, nil
+}
+
+func scanLot(row scanner) (*models.Lot, error) {
+	var lot models.Lot
+	err := row.Scan(&lot.Id, &lot.TenderId, &lot.Position, &lot.Name, &lot.Description, &lot.Status,
+		&lot.AwardedBidId, &lot.ClosedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+const invitationColumns = `tender_invitation.id, tender_invitation.tender_id, COALESCE(employee.username, ''),
+    tender_invitation.organization_id, tender_invitation.status, tender_invitation.invited_by, tender_invitation.created_at,
+    COALESCE(tender_invitation.responded_by, ''), tender_invitation.responded_at`
+
+const invitationFrom = `
+        FROM tender_invitation
+        LEFT JOIN employee ON employee.id = tender_invitation.employee_id`
+
+func (r *TenderRepoPostgres) CreateInvitation(tenderId uuid.UUID, invitation *models.InvitationRequest, invitedBy string) (*models.Invitation, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var organizationId uuid.UUID
+	err = tx.QueryRow(`SELECT organization_id FROM tender WHERE id = $1`, tenderId).Scan(&organizationId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrTenderNotFound
+		}
+		return nil, err
+	}
+
+	var employeeId *uuid.UUID
+	if invitation.Username != "" {
+		var id uuid.UUID
+		err = tx.QueryRow(`SELECT id FROM employee WHERE username = $1`, invitation.Username).Scan(&id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, myErrors.ErrBadRequest
+			}
+			return nil, err
+		}
+		employeeId = &id
+	}
+
+	var id uuid.UUID
+	query := `
+        INSERT INTO tender_invitation (tender_id, employee_id, organization_id, invited_by)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id`
+	if err = tx.QueryRow(query, tenderId, employeeId, invitation.OrganizationId, invitedBy).Scan(&id); err != nil {
+		return nil, myErrors.ErrBadRequest
+	}
+	created, err := scanInvitation(tx.QueryRow(`SELECT `+invitationColumns+invitationFrom+` WHERE tender_invitation.id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = commitWithEvent(tx, models.NewInvitationEvent(models.EventInvitationSent, created, organizationId)); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *TenderRepoPostgres) SelectTenderInvitations(tenderId uuid.UUID) ([]*models.Invitation, error) {
+	query := `SELECT ` + invitationColumns + invitationFrom + `
+        WHERE tender_invitation.tender_id = $1
+        ORDER BY tender_invitation.created_at ASC`
+
+	rows, err := r.db.Query(query, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanInvitations(rows)
+}
+
+// SelectUserInvitations returns invitations addressed to the employee and to
+// the organizations they are responsible for.
+func (r *TenderRepoPostgres) SelectUserInvitations(limit, offset int32, username string) ([]*models.Invitation, error) {
+	query := `SELECT ` + invitationColumns + invitationFrom + `
+        WHERE employee.username = $1 OR tender_invitation.organization_id IN (
+            SELECT organization_responsible.organization_id
+            FROM organization_responsible
+            JOIN employee ON organization_responsible.user_id = employee.id
+            WHERE employee.username = $1)
+        ORDER BY tender_invitation.created_at DESC
+        LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Query(query, username, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanInvitations(rows)
+}
+
+func (r *TenderRepoPostgres) SelectInvitation(invitationId uuid.UUID) (*models.Invitation, error) {
+	query := `SELECT ` + invitationColumns + invitationFrom + ` WHERE tender_invitation.id = $1`
+
+	invitation, err := scanInvitation(r.db.QueryRow(query, invitationId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, myErrors.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (r *TenderRepoPostgres) RespondInvitation(invitationId uuid.UUID, username string, status models.InvitationStatus) (*models.Invitation, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE tender_invitation
+        SET status = $2, responded_by = $3, responded_at = now()
+        WHERE id = $1 AND status = $4`
+	res, err := tx.Exec(query, invitationId, status, username, models.InvitationPending)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if _, err = r.SelectInvitation(invitationId); err != nil {
+			return nil, err
+		}
+		return nil, myErrors.ErrInvitationResponded
+	}
+	invitation, err := scanInvitation(tx.QueryRow(`SELECT `+invitationColumns+invitationFrom+` WHERE tender_invitation.id = $1`, invitationId))
+	if err != nil {
+		return nil, err
+	}
+
+	var organizationId uuid.UUID
+	err = tx.QueryRow(`SELECT organization_id FROM tender WHERE id = $1`, invitation.TenderId).Scan(&organizationId)
+	if err != nil {
+		return nil, err
+	}
+	eventType := models.EventInvitationAccepted
+	if status == models.InvitationDeclined {
+		eventType = models.EventInvitationDeclined
+	}
+	if err = commitWithEvent(tx, models.NewInvitationEvent(eventType, invitation, organizationId)); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (r *TenderRepoPostgres) DeleteInvitation(invitationId uuid.UUID) error {
+	res, err := r.db.Exec(`DELETE FROM tender_invitation WHERE id = $1`, invitationId)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return myErrors.ErrInvitationNotFound
+	}
+	return nil
+}
+
+// CheckInvitee reports whether the employee may answer the invitation: it is
+// addressed to them or to an organization they are responsible for.
+func (r *TenderRepoPostgres) CheckInvitee(invitationId uuid.UUID, username string) (bool, error) {
+	query := `
+        SELECT COUNT(*)
+        FROM tender_invitation
+        JOIN employee ON employee.username = $2
+        WHERE tender_invitation.id = $1 AND (tender_invitation.employee_id = employee.id
+            OR tender_invitation.organization_id IN (
+                SELECT organization_id FROM organization_responsible WHERE user_id = employee.id))`
+
+	var count int
+	err := r.db.QueryRow(query, invitationId, username).Scan(&count)
+	if err != nil {
+		return false, myErrors.ErrBadRequest
+	}
+	return count > 0, nil
+}
+
+// visibleTo restricts tender rows to public tenders and to invite-only ones the
+// employee named by param is responsible for or holds an open invitation to.
+func visibleTo(param string) string {
+	return `(tender.visibility = 'Public' OR EXISTS (
+            SELECT 1
+            FROM employee
+            WHERE employee.username = ` + param + ` AND (
+                tender.organization_id IN (
+                    SELECT organization_id FROM organization_responsible WHERE user_id = employee.id)
+                OR EXISTS (
+                    SELECT 1
+                    FROM tender_invitation
+                    WHERE tender_invitation.tender_id = tender.id AND tender_invitation.status <> 'Declined'
+                      AND (tender_invitation.employee_id = employee.id OR tender_invitation.organization_id IN (
+                          SELECT organization_id FROM organization_responsible WHERE user_id = employee.id))))))`
+}
+
+func scanInvitation(row scanner) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := row.Scan(&invitation.Id, &invitation.TenderId, &invitation.Username, &invitation.OrganizationId,
+		&invitation.Status, &invitation.InvitedBy, &invitation.CreatedAt, &invitation.RespondedBy, &invitation.RespondedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func scanInvitations(rows *sql.Rows) ([]*models.Invitation, error) {
+	var invitations []*models.Invitation
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+	return invitations, rows.Err()
+}
+
+func insertTenderItems(tx *sql.Tx, tenderId uuid.UUID, items []models.TenderItemRequest) ([]*models.TenderItem, error) {
+	query := `
+        INSERT INTO tender_item (tender_id, position, name, quantity, unit, mandatory)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, position, name, quantity, unit, mandatory`
+
+	var inserted []*models.TenderItem
+	for i, item := range items {
+		var created models.TenderItem
+		err := tx.QueryRow(query, tenderId, i+1, item.Name, item.Quantity, item.Unit, item.Mandatory).Scan(
+			&created.Id, &created.Position, &created.Name, &created.Quantity, &created.Unit, &created.Mandatory)
+		if err != nil {
+			return nil, myErrors.ErrBadRequest
+		}
+		inserted = append(inserted, &created)
+	}
+	return inserted, nil
+}
+
+func publicationArgs(publication *models.Publication) (*time.Time, *string) {
+	if publication == nil {
+		return nil, nil
+	}
+	return &publication.PublishAt, &publication.Timezone
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTender(row scanner) (*models.TendersResponse, error) {
+	var (
+		tender    models.TendersResponse
+		publishAt sql.NullTime
+		timezone  sql.NullString
+		phase     sql.NullString
+		reason    sql.NullString
+		pubError  sql.NullString
+	)
+	err := row.Scan(&tender.Id, &tender.Name, &tender.Description, &tender.Status, &tender.ServiceType,
+		&tender.OrganizationId, &tender.CreatedAt, &tender.Version, &tender.SubmissionDeadline, &publishAt, &timezone,
+		&tender.EstimatedBudget, &tender.MaxPrice, &tender.Currency, &tender.Sealed, &tender.OpeningAt, &tender.OpenedAt,
+		&phase, &tender.ClarificationDeadline, &tender.Visibility, &reason, &tender.CanceledAt, &pubError)
+	if err != nil {
+		return nil, err
+	}
+	tender.CancellationReason = reason.String
+	tender.PublicationError = pubError.String
+	if models.TwoEnvelope(tender.ServiceType) {
+		tender.EvaluationPhase = models.PhaseTechnicalReview
+		if phase.Valid {
+			tender.EvaluationPhase = models.EvaluationPhase(phase.String)
+		}
+	}
+	if publishAt.Valid {
+		tender.Publication = &models.Publication{PublishAt: publishAt.Time, Timezone: timezone.String}
+		if loc, err := time.LoadLocation(timezone.String); err == nil {
+			tender.Publication.PublishAt = publishAt.Time.In(loc)
+		}
+	}
 	return &tender, nil
 }
+
+func scanTenders(rows *sql.Rows) ([]*models.TendersResponse, error) {
+	var tenders []*models.TendersResponse
+	for rows.Next() {
+		tender, err := scanTender(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenders = append(tenders, tender)
+	}
+	return tenders, rows.Err()
+}
+
+func commitWithEvent(tx *sql.Tx, event *models.Event) error {
+	if err := repoOutbox.Insert(tx, event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
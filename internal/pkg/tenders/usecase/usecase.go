@@ -1,30 +1,88 @@
 package usecase
 
 import (
+	"errors"
+	"fmt"
 	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"strings"
+	"time"
+	"unicode/utf8"
 	"zadanie-6105/internal/models"
 	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/clock"
 	"zadanie-6105/internal/pkg/tenders"
 )
 
+const maxCancellationReason = 1000
+
 type TenderUsecase struct {
-	r tenders.TenderRepoPostgres
+	r      tenders.TenderRepoPostgres
+	clock  clock.Clock
+	sealer tenders.Sealer
 }
 
-func NewUsecase(r tenders.TenderRepoPostgres) *TenderUsecase {
+// NewUsecase accepts a nil sealer, in which case sealed tenders are rejected.
+func NewUsecase(r tenders.TenderRepoPostgres, clk clock.Clock, sealer tenders.Sealer) *TenderUsecase {
 	return &TenderUsecase{
-		r: r,
+		r:      r,
+		clock:  clk,
+		sealer: sealer,
 	}
 }
 
-func (u *TenderUsecase) GetTendersList(limit, offset int32, serviceType []string) ([]*models.TendersResponse, error) {
-	tendersList, err := u.r.SelectTendersList(limit, offset, serviceType)
+func (u *TenderUsecase) GetTendersList(limit, offset int32, serviceType []string, username string) ([]*models.TendersResponse, error) {
+	tendersList, err := u.r.SelectTendersList(limit, offset, serviceType, username)
 	if err != nil {
 		return nil, err
 	}
 	return tendersList, nil
 }
 func (u *TenderUsecase) CreateNewTender(tenderData *models.TendersRequest) (*models.TendersResponse, error) {
+	if tenderData == nil {
+		return nil, myErrors.ErrBadRequest
+	}
+	if tenderData.SubmissionDeadline != nil && !tenderData.SubmissionDeadline.After(u.clock.Now()) {
+		return nil, myErrors.ErrBadRequest
+	}
+	if !u.validClarification(tenderData.ClarificationDeadline, tenderData.SubmissionDeadline) {
+		return nil, myErrors.ErrBadRequest
+	}
+	if tenderData.Currency == "" {
+		tenderData.Currency = models.DefaultCurrency
+	}
+	if tenderData.Visibility == "" {
+		tenderData.Visibility = models.VisibilityPublic
+	}
+	if !tenderData.Visibility.IsValid() {
+		return nil, myErrors.ErrBadRequest
+	}
+	if !validBudget(tenderData.EstimatedBudget, tenderData.MaxPrice, tenderData.Currency) {
+		return nil, myErrors.ErrBadRequest
+	}
+	if !validItems(tenderData.Items) || !validLots(tenderData.Lots) {
+		return nil, myErrors.ErrBadRequest
+	}
+	var newKey func(tenderId uuid.UUID) ([]byte, error)
+	if tenderData.Sealed {
+		if u.sealer == nil {
+			return nil, myErrors.ErrSealingDisabled
+		}
+		if !u.validOpening(tenderData.OpeningAt, tenderData.SubmissionDeadline) {
+			return nil, myErrors.ErrBadRequest
+		}
+		newKey = u.sealer.NewTenderKey
+	} else if tenderData.OpeningAt != nil {
+		return nil, myErrors.ErrBadRequest
+	}
+	var publication *models.Publication
+	if tenderData.Publication != nil {
+		var err error
+		publication, err = u.resolvePublication(tenderData.Publication, tenderData.SubmissionDeadline)
+		if err != nil {
+			return nil, err
+		}
+	}
 	ok, err := u.r.CheckUsernameOrganization(tenderData.CreatorUsername, tenderData.OrganizationId)
 	if err != nil {
 		return nil, myErrors.ErrBadRequest
@@ -32,7 +90,7 @@ func (u *TenderUsecase) CreateNewTender(tenderData *models.TendersRequest) (*mod
 	if !ok {
 		return nil, myErrors.ErrForbidden
 	}
-	newTender, err := u.r.CreateTender(tenderData)
+	newTender, err := u.r.CreateTender(tenderData, publication, newKey)
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +103,37 @@ func (u *TenderUsecase) GetUserTender(limit, offset int32, username string) ([]*
 	}
 	return userTenders, nil
 }
+
+// GetTender returns the full tender. Drafts are visible only to their creator,
+// invite-only tenders only to responsible employees and invitees.
+func (u *TenderUsecase) GetTender(tenderId uuid.UUID, username string) (*models.TenderDetails, error) {
+	tender, err := u.r.SelectTenderDetails(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Status == models.StatusCreated {
+		if username == "" {
+			return nil, myErrors.ErrForbidden
+		}
+		ok, err := u.r.CheckUsernameTender(username, tenderId)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, myErrors.ErrForbidden
+		}
+	}
+	if err = tenders.CheckVisible(u.r, tender.TendersResponse, username); err != nil {
+		return nil, err
+	}
+	if tender.Items, err = u.r.SelectTenderItems(tenderId); err != nil {
+		return nil, err
+	}
+	if tender.Lots, err = u.r.SelectTenderLots(tenderId); err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
 func (u *TenderUsecase) GetTenderStatus(tenderId uuid.UUID, username string) (string, error) {
 	ok, err := u.r.CheckUsernameTender(username, tenderId)
 	if err != nil {
@@ -67,13 +156,87 @@ func (u *TenderUsecase) EditTenderStatus(tenderId uuid.UUID, username, status st
 	if !ok {
 		return nil, myErrors.ErrUserNotFound
 	}
+	if models.TypeStatus(status) == models.StatusCanceled {
+		return nil, myErrors.ErrBadRequest
+	}
+	current, err := u.r.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status == models.StatusCanceled {
+		return nil, myErrors.ErrTenderCanceled
+	}
 	editedTender, err := u.r.EditStatusTender(tenderId, status)
 	if err != nil {
 		return nil, err
 	}
 	return editedTender, nil
 }
+
+// CancelTender abandons the tender. Unlike closing, it requires a reason, which
+// is kept on the tender and sent to every bidder.
+func (u *TenderUsecase) CancelTender(tenderId uuid.UUID, username string, cancellation *models.CancelRequest) (*models.TendersResponse, error) {
+	if cancellation == nil {
+		return nil, myErrors.ErrBadRequest
+	}
+	reason := strings.TrimSpace(cancellation.Reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxCancellationReason {
+		return nil, myErrors.ErrBadRequest
+	}
+	ok, err := u.r.CheckUsernameTender(username, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrUserNotFound
+	}
+	if _, err = u.r.SelectTender(tenderId); err != nil {
+		return nil, err
+	}
+	tender, err := u.r.CancelTender(tenderId, reason)
+	if err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+
+// GetAward returns the award of a tender without lots or the awards of the
+// lots decided so far.
+func (u *TenderUsecase) GetAward(tenderId uuid.UUID, username string) (*models.TenderAward, error) {
+	tender, err := u.r.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if err = tenders.CheckVisible(u.r, tender, username); err != nil {
+		return nil, err
+	}
+	lots, err := u.r.SelectTenderLots(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if len(lots) > 0 {
+		awards, err := u.r.SelectLotAwards(tenderId)
+		if err != nil {
+			return nil, err
+		}
+		if len(awards) == 0 {
+			return nil, myErrors.ErrAwardNotFound
+		}
+		return &models.TenderAward{Lots: awards}, nil
+	}
+	award, err := u.r.SelectAward(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TenderAward{Award: award}, nil
+}
 func (u *TenderUsecase) EditTender(tenderId uuid.UUID, username string, editedData *models.TenderEditRequest) (*models.TendersResponse, error) {
+	if editedData == nil {
+		return nil, myErrors.ErrBadRequest
+	}
+	if editedData.SubmissionDeadline != nil && !editedData.SubmissionDeadline.After(u.clock.Now()) {
+		return nil, myErrors.ErrBadRequest
+	}
 	ok, err := u.r.CheckUsernameTender(username, tenderId)
 	if err != nil {
 		return nil, err
@@ -81,9 +244,515 @@ func (u *TenderUsecase) EditTender(tenderId uuid.UUID, username string, editedDa
 	if !ok {
 		return nil, myErrors.ErrUserNotFound
 	}
+	if editedData.EstimatedBudget != nil || editedData.MaxPrice != nil || editedData.Currency != "" || editedData.SubmissionDeadline != nil ||
+		editedData.ServiceType != "" || editedData.ClarificationDeadline != nil {
+		tender, err := u.r.SelectTender(tenderId)
+		if err != nil {
+			return nil, err
+		}
+		if editedData.ServiceType != "" && editedData.ServiceType != tender.ServiceType &&
+			tender.EvaluationPhase != "" && tender.EvaluationPhase != models.PhaseTechnicalReview {
+			return nil, myErrors.ErrWrongPhase
+		}
+		if editedData.ServiceType != "" && editedData.ServiceType != tender.ServiceType {
+			if _, err = u.r.SelectAuction(tenderId); !errors.Is(err, myErrors.ErrAuctionNotFound) {
+				if err != nil {
+					return nil, err
+				}
+				return nil, myErrors.ErrTenderNotEditable
+			}
+		}
+		if tender.Sealed && editedData.SubmissionDeadline != nil && !u.validOpening(tender.OpeningAt, editedData.SubmissionDeadline) {
+			return nil, myErrors.ErrBadRequest
+		}
+		if editedData.SubmissionDeadline != nil {
+			auction, err := u.r.SelectAuction(tenderId)
+			switch {
+			case err == nil:
+				if auction.EndsAt.After(*editedData.SubmissionDeadline) {
+					return nil, myErrors.ErrAuctionAfterDeadline
+				}
+			case !errors.Is(err, myErrors.ErrAuctionNotFound):
+				return nil, err
+			}
+		}
+		if editedData.ClarificationDeadline != nil || editedData.SubmissionDeadline != nil {
+			clarification, deadline := tender.ClarificationDeadline, tender.SubmissionDeadline
+			if editedData.ClarificationDeadline != nil {
+				clarification = editedData.ClarificationDeadline
+			}
+			if editedData.SubmissionDeadline != nil {
+				deadline = editedData.SubmissionDeadline
+			}
+			if editedData.ClarificationDeadline != nil && !u.validClarification(clarification, deadline) {
+				return nil, myErrors.ErrBadRequest
+			}
+			if clarification != nil && deadline != nil && clarification.After(*deadline) {
+				return nil, myErrors.ErrBadRequest
+			}
+		}
+		budget, maxPrice, currency := tender.EstimatedBudget, tender.MaxPrice, tender.Currency
+		if editedData.EstimatedBudget != nil {
+			budget = editedData.EstimatedBudget
+		}
+		if editedData.MaxPrice != nil {
+			maxPrice = editedData.MaxPrice
+		}
+		if editedData.Currency != "" {
+			currency = editedData.Currency
+		}
+		if !validBudget(budget, maxPrice, currency) {
+			return nil, myErrors.ErrBadRequest
+		}
+	}
 	editedTender, err := u.r.EditTender(tenderId, editedData)
 	if err != nil {
 		return nil, err
 	}
 	return editedTender, nil
 }
+func (u *TenderUsecase) CloseExpiredTenders(now time.Time) ([]*models.TendersResponse, error) {
+	closed, err := u.r.CloseExpiredTenders(now)
+	if err != nil {
+		return nil, err
+	}
+	return closed, nil
+}
+func (u *TenderUsecase) SchedulePublication(tenderId uuid.UUID, username string, publication *models.PublicationRequest) (*models.TendersResponse, error) {
+	if publication == nil {
+		return nil, myErrors.ErrBadRequest
+	}
+	ok, err := u.r.CheckUsernameTender(username, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrUserNotFound
+	}
+	tender, err := u.r.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Status != models.StatusCreated {
+		return nil, myErrors.ErrPublicationNotAllowed
+	}
+	resolved, err := u.resolvePublication(publication, tender.SubmissionDeadline)
+	if err != nil {
+		return nil, err
+	}
+	scheduled, err := u.r.SchedulePublication(tenderId, resolved)
+	if err != nil {
+		return nil, err
+	}
+	return scheduled, nil
+}
+func (u *TenderUsecase) CancelPublication(tenderId uuid.UUID, username string) (*models.TendersResponse, error) {
+	ok, err := u.r.CheckUsernameTender(username, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrUserNotFound
+	}
+	tender, err := u.r.SchedulePublication(tenderId, nil)
+	if err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+
+const (
+	maxPublishAttempts  = 5
+	publishRetryBackoff = time.Minute
+)
+
+// PublishScheduledTenders publishes due tenders on behalf of their creators
+// through EditTenderStatus, so scheduled and manual publication share checks and events.
+// A failed publication is retried with exponential backoff and dropped after
+// maxPublishAttempts, keeping the last error on the tender.
+func (u *TenderUsecase) PublishScheduledTenders(now time.Time) ([]*models.TendersResponse, error) {
+	due, err := u.r.SelectDuePublications(now)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		published []*models.TendersResponse
+		errs      []error
+	)
+	for _, publication := range due {
+		tender, err := u.EditTenderStatus(publication.TenderId, publication.CreatorUsername, string(models.StatusPublished))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tender %s: %w", publication.TenderId, err))
+			if err = u.r.FailPublication(publication.TenderId, err.Error(), publishRetryAt(now, publication.Attempts)); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		published = append(published, tender)
+	}
+	return published, errors.Join(errs...)
+}
+
+// publishRetryAt doubles the delay after every failed attempt; nil means the
+// publication is given up.
+func publishRetryAt(now time.Time, attempts int) *time.Time {
+	if attempts+1 >= maxPublishAttempts {
+		return nil
+	}
+	retryAt := now.Add(publishRetryBackoff << attempts)
+	return &retryAt
+}
+
+func (u *TenderUsecase) GetTenderItems(tenderId uuid.UUID, username string) ([]*models.TenderItem, error) {
+	tender, err := u.r.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Status == models.StatusCreated {
+		if username == "" {
+			return nil, myErrors.ErrForbidden
+		}
+		ok, err := u.r.CheckUsernameTender(username, tenderId)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, myErrors.ErrForbidden
+		}
+	}
+	if err = tenders.CheckVisible(u.r, tender, username); err != nil {
+		return nil, err
+	}
+	items, err := u.r.SelectTenderItems(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+func (u *TenderUsecase) ReplaceTenderItems(tenderId uuid.UUID, username string, items []models.TenderItemRequest) (*models.TendersResponse, error) {
+	if !validItems(items) {
+		return nil, myErrors.ErrBadRequest
+	}
+	ok, err := u.r.CheckUsernameTender(username, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrUserNotFound
+	}
+	if _, err = u.r.SelectAuction(tenderId); !errors.Is(err, myErrors.ErrAuctionNotFound) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, myErrors.ErrTenderNotEditable
+	}
+	tender, err := u.r.ReplaceTenderItems(tenderId, items)
+	if err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+
+func (u *TenderUsecase) GetTenderLots(tenderId uuid.UUID, username string) ([]*models.Lot, error) {
+	tender, err := u.r.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Status == models.StatusCreated {
+		if username == "" {
+			return nil, myErrors.ErrForbidden
+		}
+		ok, err := u.r.CheckUsernameTender(username, tenderId)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, myErrors.ErrForbidden
+		}
+	}
+	if err = tenders.CheckVisible(u.r, tender, username); err != nil {
+		return nil, err
+	}
+	lots, err := u.r.SelectTenderLots(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	return lots, nil
+}
+func (u *TenderUsecase) ReplaceTenderLots(tenderId uuid.UUID, username string, lots []models.LotRequest) (*models.TendersResponse, error) {
+	if !validLots(lots) {
+		return nil, myErrors.ErrBadRequest
+	}
+	ok, err := u.r.CheckUsernameTender(username, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrUserNotFound
+	}
+	if _, err = u.r.SelectAuction(tenderId); !errors.Is(err, myErrors.ErrAuctionNotFound) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, myErrors.ErrTenderNotEditable
+	}
+	tender, err := u.r.ReplaceTenderLots(tenderId, lots)
+	if err != nil {
+		return nil, err
+	}
+	return tender, nil
+}
+func (u *TenderUsecase) CancelLot(tenderId, lotId uuid.UUID, username string) (*models.Lot, error) {
+	ok, err := u.r.CheckUsernameTender(username, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrUserNotFound
+	}
+	lot, err := u.r.CancelLot(tenderId, lotId)
+	if err != nil {
+		return nil, err
+	}
+	return lot, nil
+}
+
+func (u *TenderUsecase) GetTenderInvitations(tenderId uuid.UUID, username string) ([]*models.Invitation, error) {
+	if _, err := u.responsibleTender(tenderId, username); err != nil {
+		return nil, err
+	}
+	invitations, err := u.r.SelectTenderInvitations(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (u *TenderUsecase) InviteToTender(tenderId uuid.UUID, username string, invitation *models.InvitationRequest) (*models.Invitation, error) {
+	if invitation == nil || !invitation.IsValid() {
+		return nil, myErrors.ErrBadRequest
+	}
+	tender, err := u.responsibleTender(tenderId, username)
+	if err != nil {
+		return nil, err
+	}
+	if tender.Visibility != models.VisibilityInviteOnly || tender.Status == models.StatusClosed {
+		return nil, myErrors.ErrBadRequest
+	}
+	if invitation.OrganizationId != nil && *invitation.OrganizationId == tender.OrganizationId {
+		return nil, myErrors.ErrBadRequest
+	}
+	created, err := u.r.CreateInvitation(tenderId, invitation, username)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (u *TenderUsecase) RevokeInvitation(tenderId, invitationId uuid.UUID, username string) error {
+	if _, err := u.responsibleTender(tenderId, username); err != nil {
+		return err
+	}
+	invitation, err := u.r.SelectInvitation(invitationId)
+	if err != nil {
+		return err
+	}
+	if invitation.TenderId != tenderId {
+		return myErrors.ErrInvitationNotFound
+	}
+	return u.r.DeleteInvitation(invitationId)
+}
+
+func (u *TenderUsecase) GetUserInvitations(limit, offset int32, username string) ([]*models.Invitation, error) {
+	invitations, err := u.r.SelectUserInvitations(limit, offset, username)
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (u *TenderUsecase) RespondInvitation(invitationId uuid.UUID, username string, status models.InvitationStatus) (*models.Invitation, error) {
+	if status != models.InvitationAccepted && status != models.InvitationDeclined {
+		return nil, myErrors.ErrBadRequest
+	}
+	ok, err := u.r.CheckInvitee(invitationId, username)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if _, err = u.r.SelectInvitation(invitationId); err != nil {
+			return nil, err
+		}
+		return nil, myErrors.ErrForbidden
+	}
+	invitation, err := u.r.RespondInvitation(invitationId, username, status)
+	if err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (u *TenderUsecase) responsibleTender(tenderId uuid.UUID, username string) (*models.TendersResponse, error) {
+	tender, err := u.r.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := u.r.CheckUsernameOrganization(username, tender.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrForbidden
+	}
+	return tender, nil
+}
+
+// ConfigureAuction turns a Delivery tender into a reverse auction. Auctions are
+// priced as a lump sum, so tenders with line items or sealed bids are rejected.
+func (u *TenderUsecase) ConfigureAuction(tenderId uuid.UUID, username string, auction *models.AuctionRequest) (*models.Auction, error) {
+	if auction == nil {
+		return nil, myErrors.ErrBadRequest
+	}
+	if auction.ExtensionWindow == nil {
+		window := models.DefaultAuctionExtensionWindow
+		auction.ExtensionWindow = &window
+	}
+	if auction.Extension == nil {
+		extension := models.DefaultAuctionExtension
+		auction.Extension = &extension
+	}
+	now := u.clock.Now()
+	if !auction.StartsAt.After(now) || !auction.EndsAt.After(auction.StartsAt) || !models.ValidAmount(&auction.MinDecrement) ||
+		*auction.ExtensionWindow < 0 || *auction.Extension < 0 {
+		return nil, myErrors.ErrBadRequest
+	}
+	ok, err := u.r.CheckUsernameTender(username, tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, myErrors.ErrUserNotFound
+	}
+	tender, err := u.r.SelectTender(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	switch tender.Status {
+	case models.StatusCanceled:
+		return nil, myErrors.ErrTenderCanceled
+	case models.StatusClosed:
+		return nil, myErrors.ErrAuctionNotAllowed
+	}
+	if tender.ServiceType != models.ServiceTypeDelivery || tender.Sealed {
+		return nil, myErrors.ErrBadRequest
+	}
+	// The deadline job closes the tender at the submission deadline, which
+	// would cut the auction short.
+	if tender.SubmissionDeadline != nil && auction.EndsAt.After(*tender.SubmissionDeadline) {
+		return nil, myErrors.ErrAuctionAfterDeadline
+	}
+	items, err := u.r.SelectTenderItems(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	lots, err := u.r.SelectTenderLots(tenderId)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) > 0 || len(lots) > 0 {
+		return nil, myErrors.ErrBadRequest
+	}
+	saved, err := u.r.UpsertAuction(tenderId, auction, now)
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// validOpening requires bids to be opened in the future and not before the submission deadline.
+func (u *TenderUsecase) validOpening(openingAt, deadline *time.Time) bool {
+	if openingAt == nil || !openingAt.After(u.clock.Now()) {
+		return false
+	}
+	return deadline == nil || !openingAt.Before(*deadline)
+}
+
+// validClarification requires questions to close in the future and no later
+// than the submission deadline.
+func (u *TenderUsecase) validClarification(clarification, deadline *time.Time) bool {
+	if clarification == nil {
+		return true
+	}
+	if !clarification.After(u.clock.Now()) {
+		return false
+	}
+	return deadline == nil || !clarification.After(*deadline)
+}
+
+const maxTenderItems = 1000
+
+func validItems(items []models.TenderItemRequest) bool {
+	if len(items) > maxTenderItems {
+		return false
+	}
+	for _, item := range items {
+		if item.Name == "" || len(item.Name) > 100 || item.Unit == "" || len(item.Unit) > 20 {
+			return false
+		}
+		if !models.ValidAmount(&item.Quantity) {
+			return false
+		}
+	}
+	return true
+}
+
+const maxTenderLots = 100
+
+func validLots(lots []models.LotRequest) bool {
+	if len(lots) > maxTenderLots {
+		return false
+	}
+	for _, lot := range lots {
+		if lot.Name == "" || len(lot.Name) > 100 {
+			return false
+		}
+	}
+	return true
+}
+
+func validBudget(budget, maxPrice *decimal.Decimal, currency string) bool {
+	return models.ValidCurrency(currency) && models.ValidAmount(budget) && models.ValidAmount(maxPrice)
+}
+
+var publishAtLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"}
+
+func (u *TenderUsecase) resolvePublication(req *models.PublicationRequest, deadline *time.Time) (*models.Publication, error) {
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, myErrors.ErrBadRequest
+	}
+
+	publishAt, err := time.Parse(time.RFC3339, req.PublishAt)
+	if err != nil {
+		for _, layout := range publishAtLayouts {
+			if publishAt, err = time.ParseInLocation(layout, req.PublishAt, loc); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return nil, myErrors.ErrBadRequest
+		}
+	}
+
+	if !publishAt.After(u.clock.Now()) {
+		return nil, myErrors.ErrBadRequest
+	}
+	if deadline != nil && !publishAt.Before(*deadline) {
+		return nil, myErrors.ErrBadRequest
+	}
+	return &models.Publication{PublishAt: publishAt.In(loc), Timezone: loc.String()}, nil
+}
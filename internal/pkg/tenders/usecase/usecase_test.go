@@ -0,0 +1,303 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satori/uuid"
+	"github.com/shopspring/decimal"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/clock"
+	"zadanie-6105/internal/pkg/tenders"
+)
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeRepo implements just enough of the repository for the tests; any other
+// call panics on the embedded nil interface.
+type fakeRepo struct {
+	tenders.TenderRepoPostgres
+	created *models.TendersRequest
+	due     []*models.ScheduledPublication
+	failed  map[uuid.UUID]*time.Time
+	// responsible answers CheckUsernameTender.
+	responsible bool
+	tender      *models.TendersResponse
+	auction     *models.AuctionRequest
+	// saved answers SelectAuction.
+	saved     *models.Auction
+	lots      []*models.Lot
+	award     *models.Award
+	lotAwards []*models.Award
+}
+
+func (r *fakeRepo) CheckUsernameOrganization(string, uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+func (r *fakeRepo) CreateTender(tenderData *models.TendersRequest, _ *models.Publication,
+	_ func(uuid.UUID) ([]byte, error)) (*models.TendersResponse, error) {
+	r.created = tenderData
+	return &models.TendersResponse{Name: tenderData.Name}, nil
+}
+
+func (r *fakeRepo) SelectDuePublications(time.Time) ([]*models.ScheduledPublication, error) {
+	return r.due, nil
+}
+
+func (r *fakeRepo) CheckUsernameTender(string, uuid.UUID) (bool, error) {
+	return r.responsible, nil
+}
+
+func (r *fakeRepo) SelectTender(uuid.UUID) (*models.TendersResponse, error) {
+	return r.tender, nil
+}
+
+func (r *fakeRepo) SelectTenderItems(uuid.UUID) ([]*models.TenderItem, error) {
+	return nil, nil
+}
+
+func (r *fakeRepo) SelectTenderLots(uuid.UUID) ([]*models.Lot, error) {
+	return r.lots, nil
+}
+
+func (r *fakeRepo) SelectAward(uuid.UUID) (*models.Award, error) {
+	if r.award == nil {
+		return nil, myErrors.ErrAwardNotFound
+	}
+	return r.award, nil
+}
+
+func (r *fakeRepo) SelectLotAwards(uuid.UUID) ([]*models.Award, error) {
+	return r.lotAwards, nil
+}
+
+func (r *fakeRepo) UpsertAuction(tenderId uuid.UUID, auction *models.AuctionRequest, _ time.Time) (*models.Auction, error) {
+	r.auction = auction
+	return &models.Auction{TenderId: tenderId, ExtensionWindow: *auction.ExtensionWindow, Extension: *auction.Extension}, nil
+}
+
+func (r *fakeRepo) SelectAuction(uuid.UUID) (*models.Auction, error) {
+	if r.saved == nil {
+		return nil, myErrors.ErrAuctionNotFound
+	}
+	return r.saved, nil
+}
+
+func (r *fakeRepo) FailPublication(tenderId uuid.UUID, _ string, retryAt *time.Time) error {
+	r.failed[tenderId] = retryAt
+	return nil
+}
+
+func at(d time.Duration) *time.Time {
+	t := now.Add(d)
+	return &t
+}
+
+func TestCreateNewTenderDeadlines(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		deadline      *time.Time
+		clarification *time.Time
+		err           error
+	}{
+		{name: "no deadline"},
+		{name: "future deadline", deadline: at(time.Hour)},
+		{name: "deadline now", deadline: at(0), err: myErrors.ErrBadRequest},
+		{name: "past deadline", deadline: at(-time.Minute), err: myErrors.ErrBadRequest},
+		{name: "clarification before deadline", deadline: at(2 * time.Hour), clarification: at(time.Hour)},
+		{name: "clarification after deadline", deadline: at(time.Hour), clarification: at(2 * time.Hour), err: myErrors.ErrBadRequest},
+		{name: "past clarification", clarification: at(-time.Hour), err: myErrors.ErrBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeRepo{}
+			u := NewUsecase(repo, clock.NewFake(now), nil)
+			_, err := u.CreateNewTender(&models.TendersRequest{
+				Name:                  "tender",
+				SubmissionDeadline:    tc.deadline,
+				ClarificationDeadline: tc.clarification,
+			})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if (repo.created != nil) != (tc.err == nil) {
+				t.Fatalf("tender created = %t", repo.created != nil)
+			}
+		})
+	}
+}
+
+func TestValidOpeningFollowsClock(t *testing.T) {
+	clk := clock.NewFake(now)
+	u := NewUsecase(&fakeRepo{}, clk, nil)
+	openingAt := at(time.Hour)
+	if !u.validOpening(openingAt, at(30*time.Minute)) {
+		t.Fatal("opening after the deadline rejected")
+	}
+	if u.validOpening(openingAt, at(2*time.Hour)) {
+		t.Fatal("opening before the deadline accepted")
+	}
+	clk.Advance(time.Hour)
+	if u.validOpening(openingAt, nil) {
+		t.Fatal("opening in the past accepted")
+	}
+}
+
+func TestValidItems(t *testing.T) {
+	one := decimal.NewFromInt(1)
+	for _, tc := range []struct {
+		name  string
+		items []models.TenderItemRequest
+		ok    bool
+	}{
+		{"empty", nil, true},
+		{"valid", []models.TenderItemRequest{{Name: "cement", Unit: "t", Quantity: decimal.RequireFromString("2.5")}}, true},
+		{"no name", []models.TenderItemRequest{{Unit: "t", Quantity: one}}, false},
+		{"long name", []models.TenderItemRequest{{Name: strings.Repeat("x", 101), Unit: "t", Quantity: one}}, false},
+		{"no unit", []models.TenderItemRequest{{Name: "cement", Quantity: one}}, false},
+		{"zero quantity", []models.TenderItemRequest{{Name: "cement", Unit: "t"}}, false},
+		{"negative quantity", []models.TenderItemRequest{{Name: "cement", Unit: "t", Quantity: one.Neg()}}, false},
+		{"too many", make([]models.TenderItemRequest, maxTenderItems+1), false},
+	} {
+		if got := validItems(tc.items); got != tc.ok {
+			t.Errorf("%s: validItems = %t, want %t", tc.name, got, tc.ok)
+		}
+	}
+}
+
+func TestValidLots(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		lots []models.LotRequest
+		ok   bool
+	}{
+		{"empty", nil, true},
+		{"valid", []models.LotRequest{{Name: "lot 1"}, {Name: "lot 2", Description: "north"}}, true},
+		{"no name", []models.LotRequest{{Description: "north"}}, false},
+		{"long name", []models.LotRequest{{Name: strings.Repeat("x", 101)}}, false},
+		{"too many", make([]models.LotRequest, maxTenderLots+1), false},
+	} {
+		if got := validLots(tc.lots); got != tc.ok {
+			t.Errorf("%s: validLots = %t, want %t", tc.name, got, tc.ok)
+		}
+	}
+}
+
+func TestPublishScheduledTendersBacksOff(t *testing.T) {
+	first, second, last := uuid.NewV4(), uuid.NewV4(), uuid.NewV4()
+	// Every publication fails: the creator left the organization.
+	repo := &fakeRepo{
+		due: []*models.ScheduledPublication{
+			{TenderId: first},
+			{TenderId: second, Attempts: 2},
+			{TenderId: last, Attempts: maxPublishAttempts - 1},
+		},
+		failed: map[uuid.UUID]*time.Time{},
+	}
+	u := NewUsecase(repo, clock.NewFake(now), nil)
+
+	published, err := u.PublishScheduledTenders(now)
+	if len(published) != 0 || !errors.Is(err, myErrors.ErrUserNotFound) {
+		t.Fatalf("published %d, err = %v", len(published), err)
+	}
+	if retryAt := repo.failed[first]; retryAt == nil || !retryAt.Equal(now.Add(publishRetryBackoff)) {
+		t.Fatalf("first retry at %v", retryAt)
+	}
+	if retryAt := repo.failed[second]; retryAt == nil || !retryAt.Equal(now.Add(4*publishRetryBackoff)) {
+		t.Fatalf("second retry at %v", retryAt)
+	}
+	if retryAt, ok := repo.failed[last]; !ok || retryAt != nil {
+		t.Fatalf("last publication was not given up: %v", retryAt)
+	}
+}
+
+func TestConfigureAuction(t *testing.T) {
+	zero := 0
+	for _, tc := range []struct {
+		name      string
+		status    models.TypeStatus
+		deadline  *time.Time
+		extension *int
+		want      int
+		err       error
+	}{
+		{name: "default extension", status: models.StatusCreated, want: models.DefaultAuctionExtension},
+		{name: "extension disabled", status: models.StatusPublished, extension: &zero, want: 0},
+		{name: "closed tender", status: models.StatusClosed, err: myErrors.ErrAuctionNotAllowed},
+		{name: "canceled tender", status: models.StatusCanceled, err: myErrors.ErrTenderCanceled},
+		{name: "ends at deadline", status: models.StatusPublished, deadline: at(2 * time.Hour), want: models.DefaultAuctionExtension},
+		{name: "ends after deadline", status: models.StatusPublished, deadline: at(90 * time.Minute), err: myErrors.ErrAuctionAfterDeadline},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeRepo{responsible: true, tender: &models.TendersResponse{
+				Id: uuid.NewV4(), Status: tc.status, ServiceType: models.ServiceTypeDelivery, SubmissionDeadline: tc.deadline}}
+			u := NewUsecase(repo, clock.NewFake(now), nil)
+			auction, err := u.ConfigureAuction(repo.tender.Id, "user", &models.AuctionRequest{
+				StartsAt:     now.Add(time.Hour),
+				EndsAt:       now.Add(2 * time.Hour),
+				MinDecrement: decimal.NewFromInt(10),
+				Extension:    tc.extension,
+			})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if tc.err != nil {
+				if repo.auction != nil {
+					t.Fatal("auction saved")
+				}
+				return
+			}
+			if auction.Extension != tc.want || auction.ExtensionWindow != models.DefaultAuctionExtensionWindow {
+				t.Fatalf("extension = %d, window = %d", auction.Extension, auction.ExtensionWindow)
+			}
+		})
+	}
+}
+
+func TestEditTenderDeadlineBeforeAuctionEnds(t *testing.T) {
+	repo := &fakeRepo{
+		responsible: true,
+		tender:      &models.TendersResponse{Id: uuid.NewV4(), Status: models.StatusPublished, ServiceType: models.ServiceTypeDelivery},
+		saved:       &models.Auction{StartsAt: now.Add(time.Hour), EndsAt: now.Add(3 * time.Hour)},
+	}
+	u := NewUsecase(repo, clock.NewFake(now), nil)
+	_, err := u.EditTender(repo.tender.Id, "user", &models.TenderEditRequest{SubmissionDeadline: at(2 * time.Hour)})
+	if !errors.Is(err, myErrors.ErrAuctionAfterDeadline) {
+		t.Fatalf("err = %v, want %v", err, myErrors.ErrAuctionAfterDeadline)
+	}
+}
+
+func TestGetAward(t *testing.T) {
+	tenderId, lotId := uuid.NewV4(), uuid.NewV4()
+	lotAward := &models.Award{TenderId: tenderId, LotId: &lotId, BidId: uuid.NewV4()}
+	for _, tc := range []struct {
+		name string
+		repo *fakeRepo
+		want *models.TenderAward
+		err  error
+	}{
+		{name: "tender award", repo: &fakeRepo{award: &models.Award{TenderId: tenderId}},
+			want: &models.TenderAward{Award: &models.Award{TenderId: tenderId}}},
+		{name: "no award", repo: &fakeRepo{}, err: myErrors.ErrAwardNotFound},
+		{name: "lot awards", repo: &fakeRepo{lots: []*models.Lot{{Id: lotId}}, lotAwards: []*models.Award{lotAward}},
+			want: &models.TenderAward{Lots: []*models.Award{lotAward}}},
+		{name: "no lot awarded yet", repo: &fakeRepo{lots: []*models.Lot{{Id: lotId}}}, err: myErrors.ErrAwardNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.repo.tender = &models.TendersResponse{Id: tenderId, Status: models.StatusClosed}
+			award, err := NewUsecase(tc.repo, clock.NewFake(now), nil).GetAward(tenderId, "user")
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("err = %v, want %v", err, tc.err)
+			}
+			if tc.err != nil {
+				return
+			}
+			if (award.Award == nil) != (tc.want.Award == nil) || len(award.Lots) != len(tc.want.Lots) {
+				t.Fatalf("award = %+v, want %+v", award, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package tenders
+
+import (
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+)
+
+// CheckVisible hides invite-only tenders from everyone except the tender's
+// responsible employees and invitees who have not declined. Every tender-scoped
+// read goes through it.
+func CheckVisible(r TenderRepoPostgres, tender *models.TendersResponse, username string) error {
+	if tender.Visibility != models.VisibilityInviteOnly {
+		return nil
+	}
+	if username == "" {
+		return myErrors.ErrNotInvited
+	}
+	ok, err := r.CheckTenderVisible(username, tender.Id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return myErrors.ErrNotInvited
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package tenders
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+)
+
+// visibleRepo implements only CheckTenderVisible; any other call panics on the
+// embedded nil interface.
+type visibleRepo struct {
+	TenderRepoPostgres
+	invitee string
+}
+
+func (r *visibleRepo) CheckTenderVisible(username string, _ uuid.UUID) (bool, error) {
+	return username == r.invitee, nil
+}
+
+func TestCheckVisible(t *testing.T) {
+	repo := &visibleRepo{invitee: "invitee"}
+	public := &models.TendersResponse{Id: uuid.NewV4(), Visibility: models.VisibilityPublic}
+	inviteOnly := &models.TendersResponse{Id: uuid.NewV4(), Visibility: models.VisibilityInviteOnly}
+
+	for _, tc := range []struct {
+		name     string
+		tender   *models.TendersResponse
+		username string
+		err      error
+	}{
+		{"public anonymous", public, "", nil},
+		{"invite-only anonymous", inviteOnly, "", myErrors.ErrNotInvited},
+		{"invite-only stranger", inviteOnly, "stranger", myErrors.ErrNotInvited},
+		{"invite-only invitee", inviteOnly, "invitee", nil},
+	} {
+		if err := CheckVisible(repo, tc.tender, tc.username); !errors.Is(err, tc.err) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.err)
+		}
+	}
+}
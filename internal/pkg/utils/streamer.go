@@ -1,13 +1,26 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"zadanie-6105/internal/myErrors"
 )
 
+var (
+	defaultLimit int32 = 5
+	maxLimit     int32 = 50
+)
+
+func SetPagination(defLimit, maxLim int32) {
+	defaultLimit = defLimit
+	maxLimit = maxLim
+}
+
 type MessageResponse struct {
 	Message string `json:"reason"`
 }
@@ -21,6 +34,17 @@ func WriteError(w http.ResponseWriter, statusCode int, err error) {
 	_, _ = w.Write(resp)
 }
 
+// WriteRequestError answers a body ReadRequestData could not read: a body cut
+// off by the size limit is 413, anything else is a bad request.
+func WriteRequestError(w http.ResponseWriter, err error) {
+	var maxBytes *http.MaxBytesError
+	if errors.As(err, &maxBytes) {
+		WriteError(w, http.StatusRequestEntityTooLarge, myErrors.ErrRequestTooLarge)
+		return
+	}
+	WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+}
+
 func WriteJSON(w http.ResponseWriter, statusCode int, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -37,14 +61,22 @@ func ReadRequestData(r *http.Request, request interface{}) error {
 		return err
 	}
 	defer r.Body.Close()
-	if err := json.Unmarshal(data, &request); err != nil {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return errors.New("request body must not be null")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(request); err != nil {
 		return err
 	}
+	if decoder.More() {
+		return errors.New("request body must contain a single JSON value")
+	}
 	return nil
 }
 
 func ReadLimitOffset(r *http.Request) (int32, int32, error) {
-	var limit int32 = 5
+	limit := defaultLimit
 	var offset int32 = 0
 	limitQuery := r.URL.Query().Get("limit")
 	if limitQuery != "" {
@@ -52,8 +84,8 @@ func ReadLimitOffset(r *http.Request) (int32, int32, error) {
 		if err != nil {
 			return 0, 0, err
 		}
-		if limitInt < 0 || limitInt > 50 {
-			return 0, 0, errors.New("limit must be between 0 and 50")
+		if limitInt < 0 || limitInt > int64(maxLimit) {
+			return 0, 0, fmt.Errorf("limit must be between 0 and %d", maxLimit)
 		}
 		limit = int32(limitInt)
 	}
@@ -0,0 +1,47 @@
+package webhooks
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+)
+
+var ErrPrivateAddress = errors.New("webhook target resolves to a non-public address")
+
+// PublicIP reports whether a webhook may be sent to ip: loopback, private,
+// link-local, multicast and unspecified addresses are internal to the host or
+// its network.
+func PublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// PublicHost rejects targets that are internal without a DNS lookup: IP
+// literals outside public ranges and localhost names. Names that resolve to
+// internal addresses are caught by DialControl at send time.
+func PublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return PublicIP(ip)
+	}
+	return true
+}
+
+// DialControl is a net.Dialer Control hook that refuses connections to
+// non-public addresses. It sees the resolved address, so DNS names pointing
+// inside the network are blocked as well.
+func DialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !PublicIP(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"errors"
+	"github.com/gorilla/mux"
+	"github.com/satori/uuid"
+	"net/http"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/utils"
+	"zadanie-6105/internal/pkg/webhooks"
+)
+
+type WebhookHandler struct {
+	u webhooks.WebhookUsecase
+}
+
+func NewHandler(u webhooks.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{u: u}
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	organizationId, err := uuid.FromString(vars["organizationId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	var webhookData *models.WebhookRequest
+	if err = utils.ReadRequestData(r, &webhookData); err != nil {
+		utils.WriteRequestError(w, err)
+		return
+	}
+	webhook, err := h.u.CreateWebhook(organizationId, username, webhookData)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	organizationId, err := uuid.FromString(vars["organizationId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	list, err := h.u.GetWebhooks(organizationId, username)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, list)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	webhookId, err := uuid.FromString(vars["webhookId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	if err = h.u.DeleteWebhook(webhookId, username); err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		case errors.Is(err, myErrors.ErrWebhookNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrWebhookNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := utils.ReadLimitOffset(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	vars := mux.Vars(r)
+	webhookId, err := uuid.FromString(vars["webhookId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	status := r.URL.Query().Get("status")
+	list, err := h.u.GetDeliveries(limit, offset, webhookId, username, status)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		case errors.Is(err, myErrors.ErrWebhookNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrWebhookNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, list)
+}
+
+func (h *WebhookHandler) GetAttempts(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	deliveryId, err := uuid.FromString(vars["deliveryId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	list, err := h.u.GetAttempts(deliveryId, username)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		case errors.Is(err, myErrors.ErrDeliveryNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrDeliveryNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, list)
+}
+
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	deliveryId, err := uuid.FromString(vars["deliveryId"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+		return
+	}
+	delivery, err := h.u.Redeliver(deliveryId, username)
+	if err != nil {
+		switch {
+		case errors.Is(err, myErrors.ErrBadRequest):
+			utils.WriteError(w, http.StatusBadRequest, myErrors.ErrBadRequest)
+			return
+		case errors.Is(err, myErrors.ErrForbidden):
+			utils.WriteError(w, http.StatusForbidden, myErrors.ErrForbidden)
+			return
+		case errors.Is(err, myErrors.ErrDeliveryNotFound):
+			utils.WriteError(w, http.StatusNotFound, myErrors.ErrDeliveryNotFound)
+			return
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, myErrors.ErrInternal)
+			return
+		}
+	}
+	utils.WriteJSON(w, http.StatusOK, delivery)
+}
@@ -0,0 +1,32 @@
+package webhooks
+
+import (
+	"github.com/satori/uuid"
+	"time"
+	"zadanie-6105/internal/models"
+)
+
+type WebhookRepository interface {
+	CheckOrganizationResponsible(username string, organizationId uuid.UUID) (bool, error)
+	SelectWebhookOrganization(webhookId uuid.UUID) (uuid.UUID, error)
+	SelectDeliveryOrganization(deliveryId uuid.UUID) (uuid.UUID, error)
+	CreateWebhook(organizationId uuid.UUID, username string, webhook *models.WebhookRequest) (*models.WebhookResponse, error)
+	SelectWebhooks(organizationId uuid.UUID) ([]*models.WebhookResponse, error)
+	DeleteWebhook(webhookId uuid.UUID) error
+	SelectDeliveries(limit, offset int32, webhookId uuid.UUID, status string) ([]*models.WebhookDelivery, error)
+	SelectAttempts(deliveryId uuid.UUID) ([]*models.WebhookAttempt, error)
+	Redeliver(deliveryId uuid.UUID) (*models.WebhookDelivery, error)
+
+	EnqueueDeliveries(event *models.Event) error
+	ClaimDeliveries(limit int, lease time.Duration) ([]*models.WebhookJob, error)
+	RecordAttempt(job *models.WebhookJob, attempt *models.WebhookAttempt, nextAttemptAt *time.Time) error
+}
+
+type WebhookUsecase interface {
+	CreateWebhook(organizationId uuid.UUID, username string, webhook *models.WebhookRequest) (*models.WebhookResponse, error)
+	GetWebhooks(organizationId uuid.UUID, username string) ([]*models.WebhookResponse, error)
+	DeleteWebhook(webhookId uuid.UUID, username string) error
+	GetDeliveries(limit, offset int32, webhookId uuid.UUID, username, status string) ([]*models.WebhookDelivery, error)
+	GetAttempts(deliveryId uuid.UUID, username string) ([]*models.WebhookAttempt, error)
+	Redeliver(deliveryId uuid.UUID, username string) (*models.WebhookDelivery, error)
+}
@@ -0,0 +1,325 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+)
+
+type WebhookRepoPostgres struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) *WebhookRepoPostgres {
+	return &WebhookRepoPostgres{
+		db: db,
+	}
+}
+
+func (r *WebhookRepoPostgres) CheckOrganizationResponsible(username string, organizationId uuid.UUID) (bool, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM organization_responsible
+		JOIN employee ON organization_responsible.user_id = employee.id
+		WHERE employee.username = $1 AND organization_responsible.organization_id = $2`
+
+	var count int
+	if err := r.db.QueryRow(query, username, organizationId).Scan(&count); err != nil {
+		return false, myErrors.ErrBadRequest
+	}
+	return count > 0, nil
+}
+
+func (r *WebhookRepoPostgres) SelectWebhookOrganization(webhookId uuid.UUID) (uuid.UUID, error) {
+	var organizationId uuid.UUID
+	err := r.db.QueryRow(`SELECT organization_id FROM webhook_subscription WHERE id = $1`, webhookId).Scan(&organizationId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, myErrors.ErrWebhookNotFound
+		}
+		return uuid.Nil, err
+	}
+	return organizationId, nil
+}
+
+func (r *WebhookRepoPostgres) SelectDeliveryOrganization(deliveryId uuid.UUID) (uuid.UUID, error) {
+	query := `
+		SELECT s.organization_id
+		FROM webhook_delivery AS d
+		JOIN webhook_subscription AS s ON s.id = d.subscription_id
+		WHERE d.id = $1`
+
+	var organizationId uuid.UUID
+	if err := r.db.QueryRow(query, deliveryId).Scan(&organizationId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, myErrors.ErrDeliveryNotFound
+		}
+		return uuid.Nil, err
+	}
+	return organizationId, nil
+}
+
+func (r *WebhookRepoPostgres) CreateWebhook(organizationId uuid.UUID, username string, webhook *models.WebhookRequest) (*models.WebhookResponse, error) {
+	query := `
+		INSERT INTO webhook_subscription (organization_id, url, event_types, secret, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, organization_id, url, event_types, created_at`
+
+	row := r.db.QueryRow(query, organizationId, webhook.Url, pq.Array(eventTypesToStrings(webhook.EventTypes)), webhook.Secret, username)
+	res, err := scanWebhook(row)
+	if err != nil {
+		return nil, myErrors.ErrBadRequest
+	}
+	return res, nil
+}
+
+func (r *WebhookRepoPostgres) SelectWebhooks(organizationId uuid.UUID) ([]*models.WebhookResponse, error) {
+	query := `
+		SELECT id, organization_id, url, event_types, created_at
+		FROM webhook_subscription
+		WHERE organization_id = $1
+		ORDER BY created_at`
+
+	rows, err := r.db.Query(query, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.WebhookResponse
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, webhook)
+	}
+	return list, rows.Err()
+}
+
+func (r *WebhookRepoPostgres) DeleteWebhook(webhookId uuid.UUID) error {
+	res, err := r.db.Exec(`DELETE FROM webhook_subscription WHERE id = $1`, webhookId)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return myErrors.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepoPostgres) SelectDeliveries(limit, offset int32, webhookId uuid.UUID, status string) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT id, subscription_id, event_id, event_type, status, attempts, next_attempt_at,
+		       COALESCE(last_error, ''), created_at, delivered_at
+		FROM webhook_delivery
+		WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(query, webhookId, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, delivery)
+	}
+	return list, rows.Err()
+}
+
+func (r *WebhookRepoPostgres) SelectAttempts(deliveryId uuid.UUID) ([]*models.WebhookAttempt, error) {
+	query := `
+		SELECT id, delivery_id, attempted_at, response_status, COALESCE(error, ''), duration_ms
+		FROM webhook_attempt
+		WHERE delivery_id = $1
+		ORDER BY attempted_at`
+
+	rows, err := r.db.Query(query, deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*models.WebhookAttempt
+	for rows.Next() {
+		var attempt models.WebhookAttempt
+		var status sql.NullInt64
+		if err = rows.Scan(&attempt.Id, &attempt.DeliveryId, &attempt.AttemptedAt, &status, &attempt.Error, &attempt.DurationMs); err != nil {
+			return nil, err
+		}
+		if status.Valid {
+			code := int(status.Int64)
+			attempt.ResponseStatus = &code
+		}
+		list = append(list, &attempt)
+	}
+	return list, rows.Err()
+}
+
+func (r *WebhookRepoPostgres) Redeliver(deliveryId uuid.UUID) (*models.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_delivery
+		SET status = $1, attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, delivered_at = NULL
+		WHERE id = $2
+		RETURNING id, subscription_id, event_id, event_type, status, attempts, next_attempt_at,
+		          COALESCE(last_error, ''), created_at, delivered_at`
+
+	delivery, err := scanDelivery(r.db.QueryRow(query, models.DeliveryPending, deliveryId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, myErrors.ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// EnqueueDeliveries creates one delivery per matching subscription. It is
+// idempotent per event, so redelivered outbox events do not duplicate webhooks.
+func (r *WebhookRepoPostgres) EnqueueDeliveries(event *models.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO webhook_delivery (subscription_id, event_id, event_type, payload, status)
+		SELECT id, $1, $2::text, $3::jsonb, $4
+		FROM webhook_subscription
+		WHERE organization_id = $5 AND $2::text = ANY(event_types)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`
+
+	_, err = r.db.Exec(query, event.Id, string(event.Type), string(payload), models.DeliveryPending, event.OrganizationId)
+	return err
+}
+
+// ClaimDeliveries leases due deliveries by pushing their next attempt forward,
+// so that other replicas skip them while they are being sent.
+func (r *WebhookRepoPostgres) ClaimDeliveries(limit int, lease time.Duration) ([]*models.WebhookJob, error) {
+	query := `
+		UPDATE webhook_delivery AS d
+		SET next_attempt_at = CURRENT_TIMESTAMP + $1::float8 * INTERVAL '1 second'
+		FROM webhook_subscription AS s
+		WHERE s.id = d.subscription_id AND d.id IN (
+			SELECT id FROM webhook_delivery
+			WHERE status = $2 AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING d.id, d.event_type, s.url, s.secret, d.payload, d.attempts`
+
+	rows, err := r.db.Query(query, lease.Seconds(), models.DeliveryPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*models.WebhookJob
+	for rows.Next() {
+		var job models.WebhookJob
+		if err = rows.Scan(&job.DeliveryId, &job.EventType, &job.Url, &job.Secret, &job.Payload, &job.Attempts); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, rows.Err()
+}
+
+// RecordAttempt stores the attempt and moves the delivery on: to Delivered when
+// the attempt succeeded, to DeadLetter when it failed and nextAttemptAt is nil,
+// otherwise it is rescheduled at nextAttemptAt.
+func (r *WebhookRepoPostgres) RecordAttempt(job *models.WebhookJob, attempt *models.WebhookAttempt, nextAttemptAt *time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var responseStatus sql.NullInt64
+	if attempt.ResponseStatus != nil {
+		responseStatus = sql.NullInt64{Int64: int64(*attempt.ResponseStatus), Valid: true}
+	}
+	var attemptError sql.NullString
+	if attempt.Error != "" {
+		attemptError = sql.NullString{String: attempt.Error, Valid: true}
+	}
+	queryAttempt := `
+		INSERT INTO webhook_attempt (delivery_id, attempted_at, response_status, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5)`
+	if _, err = tx.Exec(queryAttempt, job.DeliveryId, attempt.AttemptedAt, responseStatus, attemptError, attempt.DurationMs); err != nil {
+		return err
+	}
+
+	attempts := job.Attempts + 1
+	switch {
+	case !attemptError.Valid:
+		_, err = tx.Exec(`
+			UPDATE webhook_delivery
+			SET status = $1, attempts = $2, last_error = NULL, delivered_at = $3
+			WHERE id = $4`, models.DeliveryDelivered, attempts, attempt.AttemptedAt, job.DeliveryId)
+	case nextAttemptAt == nil:
+		_, err = tx.Exec(`
+			UPDATE webhook_delivery
+			SET status = $1, attempts = $2, last_error = $3
+			WHERE id = $4`, models.DeliveryDeadLetter, attempts, attempt.Error, job.DeliveryId)
+	default:
+		_, err = tx.Exec(`
+			UPDATE webhook_delivery
+			SET attempts = $1, last_error = $2, next_attempt_at = $3
+			WHERE id = $4`, attempts, attempt.Error, *nextAttemptAt, job.DeliveryId)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhook(row scanner) (*models.WebhookResponse, error) {
+	var webhook models.WebhookResponse
+	var eventTypes []string
+	if err := row.Scan(&webhook.Id, &webhook.OrganizationId, &webhook.Url, pq.Array(&eventTypes), &webhook.CreatedAt); err != nil {
+		return nil, err
+	}
+	for _, t := range eventTypes {
+		webhook.EventTypes = append(webhook.EventTypes, models.EventType(t))
+	}
+	return &webhook, nil
+}
+
+func scanDelivery(row scanner) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var deliveredAt sql.NullTime
+	if err := row.Scan(&delivery.Id, &delivery.WebhookId, &delivery.EventId, &delivery.EventType, &delivery.Status,
+		&delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastError, &delivery.CreatedAt, &deliveredAt); err != nil {
+		return nil, err
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return &delivery, nil
+}
+
+func eventTypesToStrings(types []models.EventType) []string {
+	out := make([]string, 0, len(types))
+	for _, t := range types {
+		out = append(out, string(t))
+	}
+	return out
+}
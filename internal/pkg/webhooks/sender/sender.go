@@ -0,0 +1,171 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/pkg/webhooks"
+)
+
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// Sign returns the value of the signature header: an HMAC-SHA256 over
+// "<timestamp>.<body>" keyed with the subscription secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sink turns dispatched outbox events into pending webhook deliveries.
+type Sink struct {
+	r webhooks.WebhookRepository
+}
+
+func NewSink(r webhooks.WebhookRepository) *Sink {
+	return &Sink{r: r}
+}
+
+func (s *Sink) Name() string {
+	return "webhooks"
+}
+
+func (s *Sink) Deliver(_ context.Context, event *models.Event) error {
+	return s.r.EnqueueDeliveries(event)
+}
+
+type Options struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	Timeout        time.Duration
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	AllowPrivate   bool
+}
+
+type Worker struct {
+	r      webhooks.WebhookRepository
+	client *http.Client
+	opts   Options
+	log    *logrus.Logger
+}
+
+func NewWorker(r webhooks.WebhookRepository, opts Options, log *logrus.Logger) *Worker {
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	if !opts.AllowPrivate {
+		dialer.Control = webhooks.DialControl
+	}
+	// No proxy: the address check has to see the real target.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &Worker{
+		r:      r,
+		client: &http.Client{Timeout: opts.Timeout, Transport: transport},
+		opts:   opts,
+		log:    log,
+	}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		w.processDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) processDue(ctx context.Context) {
+	// The lease must outlive one send, otherwise another replica could pick
+	// the same delivery while it is still in flight.
+	lease := 2 * w.opts.Timeout
+	for ctx.Err() == nil {
+		jobs, err := w.r.ClaimDeliveries(w.opts.BatchSize, lease)
+		if err != nil {
+			w.log.Error("webhooks: failed to claim deliveries: ", err.Error())
+			return
+		}
+		for _, job := range jobs {
+			attempt := w.send(ctx, job)
+			if err = w.r.RecordAttempt(job, attempt, w.nextAttempt(job, attempt)); err != nil {
+				w.log.Error("webhooks: failed to record attempt: ", err.Error())
+			}
+		}
+		if len(jobs) < w.opts.BatchSize {
+			return
+		}
+	}
+}
+
+func (w *Worker) nextAttempt(job *models.WebhookJob, attempt *models.WebhookAttempt) *time.Time {
+	if attempt.Error == "" || job.Attempts+1 >= w.opts.MaxAttempts {
+		return nil
+	}
+	backoff := w.opts.InitialBackoff
+	for i := 0; i < job.Attempts && backoff < w.opts.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > w.opts.MaxBackoff {
+		backoff = w.opts.MaxBackoff
+	}
+	next := attempt.AttemptedAt.Add(backoff)
+	return &next
+}
+
+func (w *Worker) send(ctx context.Context, job *models.WebhookJob) *models.WebhookAttempt {
+	started := time.Now()
+	attempt := &models.WebhookAttempt{DeliveryId: job.DeliveryId, AttemptedAt: started}
+	defer func() {
+		attempt.DurationMs = time.Since(started).Milliseconds()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.Url, bytes.NewReader(job.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	timestamp := started.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(job.EventType))
+	req.Header.Set(HeaderDelivery, job.DeliveryId.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(job.Secret, timestamp, job.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.ResponseStatus = &resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("unexpected response status %d", resp.StatusCode)
+	}
+	return attempt
+}
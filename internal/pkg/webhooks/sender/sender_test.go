@@ -0,0 +1,135 @@
+package sender
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/satori/uuid"
+	"github.com/sirupsen/logrus"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/pkg/webhooks"
+)
+
+type attemptRecord struct {
+	attempt *models.WebhookAttempt
+	next    *time.Time
+}
+
+// fakeRepo hands out a single job until an attempt settles it.
+type fakeRepo struct {
+	webhooks.WebhookRepository
+	job      *models.WebhookJob
+	done     bool
+	attempts []attemptRecord
+}
+
+func (r *fakeRepo) ClaimDeliveries(int, time.Duration) ([]*models.WebhookJob, error) {
+	if r.done {
+		return nil, nil
+	}
+	return []*models.WebhookJob{r.job}, nil
+}
+
+func (r *fakeRepo) RecordAttempt(job *models.WebhookJob, attempt *models.WebhookAttempt, next *time.Time) error {
+	r.attempts = append(r.attempts, attemptRecord{attempt: attempt, next: next})
+	job.Attempts++
+	r.done = next == nil
+	return nil
+}
+
+func newTestWorker(r *fakeRepo, allowPrivate bool) *Worker {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return NewWorker(r, Options{
+		PollInterval:   time.Second,
+		BatchSize:      10,
+		Timeout:        time.Second,
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+		AllowPrivate:   allowPrivate,
+	}, log)
+}
+
+func TestWorkerSignsAndRetries(t *testing.T) {
+	const secret = "0123456789abcdef"
+	payload := []byte(`{"type":"TenderCreated"}`)
+
+	var mu sync.Mutex
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if err != nil || r.Header.Get(HeaderSignature) != Sign(secret, timestamp, body) {
+			t.Errorf("bad signature %q for timestamp %q", r.Header.Get(HeaderSignature), r.Header.Get(HeaderTimestamp))
+		}
+		if r.Header.Get(HeaderEvent) != string(models.EventTenderCreated) {
+			t.Errorf("event header = %q", r.Header.Get(HeaderEvent))
+		}
+		mu.Lock()
+		calls++
+		fail := calls <= 3
+		mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	repo := &fakeRepo{job: &models.WebhookJob{
+		DeliveryId: uuid.NewV4(),
+		EventType:  models.EventTenderCreated,
+		Url:        srv.URL,
+		Secret:     secret,
+		Payload:    payload,
+	}}
+	w := newTestWorker(repo, true)
+	for i := 0; i < 10 && !repo.done; i++ {
+		w.processDue(context.Background())
+	}
+
+	if len(repo.attempts) != 4 {
+		t.Fatalf("got %d attempts, want 4", len(repo.attempts))
+	}
+	// Backoff doubles from InitialBackoff and is capped at MaxBackoff.
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		record := repo.attempts[i]
+		if record.attempt.ResponseStatus == nil || *record.attempt.ResponseStatus != http.StatusServiceUnavailable {
+			t.Fatalf("attempt %d: status = %v", i, record.attempt.ResponseStatus)
+		}
+		if record.next == nil || record.next.Sub(record.attempt.AttemptedAt) != want {
+			t.Fatalf("attempt %d: next attempt = %v, want +%v", i, record.next, want)
+		}
+	}
+	last := repo.attempts[3]
+	if last.attempt.Error != "" || last.next != nil {
+		t.Fatalf("last attempt: error %q, next %v", last.attempt.Error, last.next)
+	}
+}
+
+func TestWorkerBlocksPrivateTargets(t *testing.T) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	repo := &fakeRepo{job: &models.WebhookJob{DeliveryId: uuid.NewV4(), Url: srv.URL, Payload: []byte(`{}`)}}
+	newTestWorker(repo, false).processDue(context.Background())
+
+	if called {
+		t.Fatal("request reached a loopback receiver")
+	}
+	if len(repo.attempts) != 1 || !strings.Contains(repo.attempts[0].attempt.Error, webhooks.ErrPrivateAddress.Error()) {
+		t.Fatalf("attempts = %+v", repo.attempts)
+	}
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"net/url"
+
+	"github.com/satori/uuid"
+	"zadanie-6105/internal/models"
+	"zadanie-6105/internal/myErrors"
+	"zadanie-6105/internal/pkg/webhooks"
+)
+
+const minSecretLength = 16
+
+type WebhookUsecase struct {
+	r            webhooks.WebhookRepository
+	allowPrivate bool
+}
+
+func NewUsecase(r webhooks.WebhookRepository, allowPrivate bool) *WebhookUsecase {
+	return &WebhookUsecase{r: r, allowPrivate: allowPrivate}
+}
+
+func (u *WebhookUsecase) CreateWebhook(organizationId uuid.UUID, username string, webhook *models.WebhookRequest) (*models.WebhookResponse, error) {
+	if err := validateWebhook(webhook, u.allowPrivate); err != nil {
+		return nil, err
+	}
+	if err := u.checkResponsible(username, organizationId); err != nil {
+		return nil, err
+	}
+	return u.r.CreateWebhook(organizationId, username, webhook)
+}
+
+func (u *WebhookUsecase) GetWebhooks(organizationId uuid.UUID, username string) ([]*models.WebhookResponse, error) {
+	if err := u.checkResponsible(username, organizationId); err != nil {
+		return nil, err
+	}
+	return u.r.SelectWebhooks(organizationId)
+}
+
+func (u *WebhookUsecase) DeleteWebhook(webhookId uuid.UUID, username string) error {
+	organizationId, err := u.r.SelectWebhookOrganization(webhookId)
+	if err != nil {
+		return err
+	}
+	if err = u.checkResponsible(username, organizationId); err != nil {
+		return err
+	}
+	return u.r.DeleteWebhook(webhookId)
+}
+
+func (u *WebhookUsecase) GetDeliveries(limit, offset int32, webhookId uuid.UUID, username, status string) ([]*models.WebhookDelivery, error) {
+	switch models.TypeDeliveryStatus(status) {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDeadLetter:
+	default:
+		return nil, myErrors.ErrBadRequest
+	}
+	organizationId, err := u.r.SelectWebhookOrganization(webhookId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkResponsible(username, organizationId); err != nil {
+		return nil, err
+	}
+	return u.r.SelectDeliveries(limit, offset, webhookId, status)
+}
+
+func (u *WebhookUsecase) GetAttempts(deliveryId uuid.UUID, username string) ([]*models.WebhookAttempt, error) {
+	organizationId, err := u.r.SelectDeliveryOrganization(deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkResponsible(username, organizationId); err != nil {
+		return nil, err
+	}
+	return u.r.SelectAttempts(deliveryId)
+}
+
+func (u *WebhookUsecase) Redeliver(deliveryId uuid.UUID, username string) (*models.WebhookDelivery, error) {
+	organizationId, err := u.r.SelectDeliveryOrganization(deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkResponsible(username, organizationId); err != nil {
+		return nil, err
+	}
+	return u.r.Redeliver(deliveryId)
+}
+
+func (u *WebhookUsecase) checkResponsible(username string, organizationId uuid.UUID) error {
+	ok, err := u.r.CheckOrganizationResponsible(username, organizationId)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return myErrors.ErrForbidden
+	}
+	return nil
+}
+
+func validateWebhook(webhook *models.WebhookRequest, allowPrivate bool) error {
+	if webhook == nil || len(webhook.Secret) < minSecretLength || len(webhook.EventTypes) == 0 {
+		return myErrors.ErrBadRequest
+	}
+	target, err := url.Parse(webhook.Url)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return myErrors.ErrBadRequest
+	}
+	if !allowPrivate && !webhooks.PublicHost(target.Hostname()) {
+		return myErrors.ErrBadRequest
+	}
+	for _, eventType := range webhook.EventTypes {
+		if !eventType.IsValid() {
+			return myErrors.ErrBadRequest
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"testing"
+
+	"zadanie-6105/internal/models"
+)
+
+func TestValidateWebhookTargets(t *testing.T) {
+	for _, tc := range []struct {
+		url string
+		ok  bool
+	}{
+		{"https://hooks.example.com/tenders", true},
+		{"http://93.184.216.34:8080/hook", true},
+		{"http://localhost:8080/hook", false},
+		{"http://api.localhost/hook", false},
+		{"http://127.0.0.1/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://10.0.0.5/hook", false},
+		{"http://192.168.1.10/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://0.0.0.0/hook", false},
+		{"ftp://hooks.example.com", false},
+	} {
+		webhook := &models.WebhookRequest{
+			Url:        tc.url,
+			Secret:     "0123456789abcdef",
+			EventTypes: []models.EventType{models.EventTenderCreated},
+		}
+		if err := validateWebhook(webhook, false); (err == nil) != tc.ok {
+			t.Errorf("%s: err = %v, want ok %t", tc.url, err, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package seed
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type organization struct {
+	name        string
+	description string
+	orgType     string
+}
+
+type employee struct {
+	username  string
+	firstName string
+	lastName  string
+}
+
+var organizations = []organization{
+	{name: "Стройинвест", description: "Генеральный подрядчик", orgType: "LLC"},
+	{name: "ТрансЛогистик", description: "Грузоперевозки", orgType: "JSC"},
+	{name: "ИП Петров", description: "Производство металлоконструкций", orgType: "IE"},
+}
+
+var employees = []employee{
+	{username: "ivanov", firstName: "Иван", lastName: "Иванов"},
+	{username: "smirnova", firstName: "Анна", lastName: "Смирнова"},
+	{username: "petrov", firstName: "Пётр", lastName: "Петров"},
+	{username: "sidorov", firstName: "Сергей", lastName: "Сидоров"},
+}
+
+// responsibles maps an employee username to the organization it is responsible for.
+var responsibles = map[string]string{
+	"ivanov":   "Стройинвест",
+	"smirnova": "ТрансЛогистик",
+	"petrov":   "ИП Петров",
+}
+
+type Result struct {
+	Organizations int
+	Employees     int
+	Responsibles  int
+}
+
+// Run inserts demo organizations, employees and responsibles. It is idempotent:
+// rows that already exist are left untouched.
+func Run(db *sql.DB) (*Result, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var res Result
+	orgIds := make(map[string]string, len(organizations))
+	for _, o := range organizations {
+		var id string
+		err = tx.QueryRow(`SELECT id FROM organization WHERE name = $1`, o.name).Scan(&id)
+		if err == sql.ErrNoRows {
+			err = tx.QueryRow(`
+				INSERT INTO organization (name, description, type)
+				VALUES ($1, $2, $3)
+				RETURNING id`, o.name, o.description, o.orgType).Scan(&id)
+			res.Organizations++
+		}
+		if err != nil {
+			return nil, fmt.Errorf("seed organization %s: %w", o.name, err)
+		}
+		orgIds[o.name] = id
+	}
+
+	userIds := make(map[string]string, len(employees))
+	for _, e := range employees {
+		var id string
+		err = tx.QueryRow(`SELECT id FROM employee WHERE username = $1`, e.username).Scan(&id)
+		if err == sql.ErrNoRows {
+			err = tx.QueryRow(`
+				INSERT INTO employee (username, first_name, last_name)
+				VALUES ($1, $2, $3)
+				RETURNING id`, e.username, e.firstName, e.lastName).Scan(&id)
+			res.Employees++
+		}
+		if err != nil {
+			return nil, fmt.Errorf("seed employee %s: %w", e.username, err)
+		}
+		userIds[e.username] = id
+	}
+
+	for username, orgName := range responsibles {
+		result, err := tx.Exec(`
+			INSERT INTO organization_responsible (organization_id, user_id)
+			SELECT $1::uuid, $2::uuid
+			WHERE NOT EXISTS (
+				SELECT 1 FROM organization_responsible WHERE organization_id = $1::uuid AND user_id = $2::uuid
+			)`, orgIds[orgName], userIds[username])
+		if err != nil {
+			return nil, fmt.Errorf("seed responsible %s: %w", username, err)
+		}
+		n, _ := result.RowsAffected()
+		res.Responsibles += int(n)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS rate_limit_bucket (
+    key VARCHAR(255) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS rate_limit_bucket_updated_at_idx ON rate_limit_bucket (updated_at);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS outbox_event (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_type VARCHAR(32) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    tender_id UUID NOT NULL,
+    organization_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMPTZ,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS outbox_event_pending_idx ON outbox_event (next_attempt_at, id) WHERE dispatched_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_event_tender_idx ON outbox_event (tender_id, id);
@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS webhook_subscription (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret TEXT NOT NULL,
+    created_by VARCHAR(50) REFERENCES employee(username) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhook_subscription_organization_idx ON webhook_subscription (organization_id);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES webhook_subscription(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMPTZ,
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_delivery_due_idx ON webhook_delivery (next_attempt_at) WHERE status = 'Pending';
+
+CREATE TABLE IF NOT EXISTS webhook_attempt (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    delivery_id UUID NOT NULL REFERENCES webhook_delivery(id) ON DELETE CASCADE,
+    attempted_at TIMESTAMPTZ NOT NULL,
+    response_status INTEGER,
+    error TEXT,
+    duration_ms BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS webhook_attempt_delivery_idx ON webhook_attempt (delivery_id, attempted_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tender_status_deadline_idx;
+
+ALTER TABLE tender DROP COLUMN IF EXISTS submission_deadline;
@@ -0,0 +1,4 @@
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS submission_deadline TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS tender_status_deadline_idx ON tender (status, submission_deadline)
+    WHERE submission_deadline IS NOT NULL;
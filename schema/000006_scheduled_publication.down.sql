@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tender_publish_at_idx;
+
+ALTER TABLE tender DROP COLUMN IF EXISTS publish_timezone;
+ALTER TABLE tender DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,5 @@
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS publish_timezone TEXT;
+
+CREATE INDEX IF NOT EXISTS tender_publish_at_idx ON tender (publish_at)
+    WHERE publish_at IS NOT NULL;
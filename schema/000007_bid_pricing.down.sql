@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS bid_tender_price_idx;
+
+ALTER TABLE bid DROP COLUMN IF EXISTS valid_until;
+ALTER TABLE bid DROP COLUMN IF EXISTS currency;
+ALTER TABLE bid DROP COLUMN IF EXISTS price;
+
+ALTER TABLE tender DROP COLUMN IF EXISTS currency;
+ALTER TABLE tender DROP COLUMN IF EXISTS max_price;
+ALTER TABLE tender DROP COLUMN IF EXISTS estimated_budget;
@@ -0,0 +1,9 @@
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS estimated_budget NUMERIC(19, 4) CHECK (estimated_budget > 0);
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS max_price NUMERIC(19, 4) CHECK (max_price > 0);
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS price NUMERIC(19, 4) CHECK (price > 0);
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS currency CHAR(3);
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS valid_until TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS bid_tender_price_idx ON bid (tender_id, price);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS bid_item;
+DROP TABLE IF EXISTS tender_item;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS tender_item (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    name VARCHAR(100) NOT NULL,
+    quantity NUMERIC(19, 4) NOT NULL CHECK (quantity > 0),
+    unit VARCHAR(20) NOT NULL,
+    mandatory BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE (tender_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS bid_item (
+    bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+    tender_item_id UUID NOT NULL REFERENCES tender_item(id) ON DELETE CASCADE,
+    unit_price NUMERIC(19, 4) NOT NULL CHECK (unit_price > 0),
+    total NUMERIC(19, 4) NOT NULL,
+    PRIMARY KEY (bid_id, tender_item_id)
+);
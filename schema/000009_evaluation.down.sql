@@ -0,0 +1,6 @@
+ALTER TABLE bid DROP COLUMN IF EXISTS decision_rank;
+ALTER TABLE bid DROP COLUMN IF EXISTS decision_ranking_id;
+
+DROP TABLE IF EXISTS tender_ranking;
+DROP TABLE IF EXISTS bid_score;
+DROP TABLE IF EXISTS tender_criterion;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS tender_criterion (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    weight NUMERIC(5, 2) NOT NULL CHECK (weight > 0 AND weight <= 100),
+    UNIQUE (tender_id, position),
+    UNIQUE (tender_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS bid_score (
+    bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+    criterion_id UUID NOT NULL REFERENCES tender_criterion(id) ON DELETE CASCADE,
+    evaluator_id UUID NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    score NUMERIC(5, 2) NOT NULL CHECK (score >= 0 AND score <= 100),
+    comment TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (bid_id, criterion_id, evaluator_id)
+);
+
+CREATE TABLE IF NOT EXISTS tender_ranking (
+    id BIGSERIAL PRIMARY KEY,
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    ranking JSONB NOT NULL,
+    created_by VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS tender_ranking_tender_idx ON tender_ranking (tender_id);
+
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS decision_ranking_id BIGINT REFERENCES tender_ranking(id) ON DELETE SET NULL;
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS decision_rank INTEGER;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS tender_opening_protocol;
+DROP TABLE IF EXISTS bid_envelope;
+ALTER TABLE bid DROP COLUMN IF EXISTS sealed;
+
+DROP TABLE IF EXISTS tender_key;
+DROP INDEX IF EXISTS tender_opening_idx;
+ALTER TABLE tender DROP COLUMN IF EXISTS opened_at;
+ALTER TABLE tender DROP COLUMN IF EXISTS opening_at;
+ALTER TABLE tender DROP COLUMN IF EXISTS sealed;
@@ -0,0 +1,23 @@
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS sealed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS opening_at TIMESTAMPTZ;
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS opened_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS tender_opening_idx ON tender (opening_at) WHERE sealed AND opened_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS tender_key (
+    tender_id UUID PRIMARY KEY REFERENCES tender(id) ON DELETE CASCADE,
+    wrapped_key BYTEA NOT NULL
+);
+
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS sealed BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS bid_envelope (
+    bid_id UUID PRIMARY KEY REFERENCES bid(id) ON DELETE CASCADE,
+    ciphertext BYTEA NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS tender_opening_protocol (
+    tender_id UUID PRIMARY KEY REFERENCES tender(id) ON DELETE CASCADE,
+    opened_at TIMESTAMPTZ NOT NULL,
+    protocol JSONB NOT NULL
+);
@@ -0,0 +1,6 @@
+ALTER TABLE bid DROP COLUMN IF EXISTS technical_reviewed_at;
+ALTER TABLE bid DROP COLUMN IF EXISTS technical_reviewed_by;
+ALTER TABLE bid DROP COLUMN IF EXISTS technical_comment;
+ALTER TABLE bid DROP COLUMN IF EXISTS technical_result;
+
+ALTER TABLE tender DROP COLUMN IF EXISTS evaluation_phase;
@@ -0,0 +1,8 @@
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS evaluation_phase VARCHAR(20)
+    CHECK (evaluation_phase IN ('TechnicalReview', 'FinancialOpening', 'Award'));
+
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS technical_result VARCHAR(20)
+    CHECK (technical_result IN ('Qualified', 'Disqualified'));
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS technical_comment TEXT;
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS technical_reviewed_by VARCHAR(50);
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS technical_reviewed_at TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS auction_price;
+DROP TABLE IF EXISTS tender_auction;
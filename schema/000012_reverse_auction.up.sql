@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS tender_auction (
+    tender_id UUID PRIMARY KEY REFERENCES tender(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    min_decrement NUMERIC(19, 4) NOT NULL CHECK (min_decrement > 0),
+    extension_window INTEGER NOT NULL CHECK (extension_window >= 0),
+    extension INTEGER NOT NULL CHECK (extension >= 0),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE TABLE IF NOT EXISTS auction_price (
+    id BIGSERIAL PRIMARY KEY,
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+    price NUMERIC(19, 4) NOT NULL CHECK (price > 0),
+    placed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS auction_price_bid_idx ON auction_price (bid_id, placed_at);
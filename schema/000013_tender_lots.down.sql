@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS bid_lot;
+DROP TABLE IF EXISTS tender_lot;
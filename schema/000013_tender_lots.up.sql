@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS tender_lot (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'Open' CHECK (status IN ('Open', 'Awarded', 'Cancelled')),
+    awarded_bid_id UUID REFERENCES bid(id) ON DELETE SET NULL,
+    closed_at TIMESTAMPTZ,
+    UNIQUE (tender_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS bid_lot (
+    bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+    lot_id UUID NOT NULL REFERENCES tender_lot(id) ON DELETE CASCADE,
+    decision VARCHAR(20) CHECK (decision IN ('Approved', 'Rejected')),
+    decided_by VARCHAR(50),
+    decided_at TIMESTAMPTZ,
+    PRIMARY KEY (bid_id, lot_id)
+);
+
+CREATE INDEX IF NOT EXISTS bid_lot_lot_idx ON bid_lot (lot_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tender_question;
+ALTER TABLE tender DROP COLUMN IF EXISTS clarification_deadline;
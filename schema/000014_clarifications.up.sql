@@ -0,0 +1,15 @@
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS clarification_deadline TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS tender_question (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    author_username VARCHAR(50) NOT NULL,
+    text TEXT NOT NULL,
+    anonymous BOOLEAN NOT NULL DEFAULT FALSE,
+    asked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    answer TEXT,
+    answered_by VARCHAR(50),
+    answered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS tender_question_tender_idx ON tender_question (tender_id, asked_at);
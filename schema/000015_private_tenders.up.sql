@@ -0,0 +1,20 @@
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS visibility VARCHAR(20) NOT NULL DEFAULT 'Public'
+    CHECK (visibility IN ('Public', 'InviteOnly'));
+
+CREATE TABLE IF NOT EXISTS tender_invitation (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    employee_id UUID REFERENCES employee(id) ON DELETE CASCADE,
+    organization_id UUID REFERENCES organization(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'Pending' CHECK (status IN ('Pending', 'Accepted', 'Declined')),
+    invited_by VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    responded_by VARCHAR(50),
+    responded_at TIMESTAMPTZ,
+    CHECK ((employee_id IS NULL) <> (organization_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tender_invitation_employee_idx ON tender_invitation (tender_id, employee_id)
+    WHERE employee_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS tender_invitation_organization_idx ON tender_invitation (tender_id, organization_id)
+    WHERE organization_id IS NOT NULL;
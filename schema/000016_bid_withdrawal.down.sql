@@ -0,0 +1,6 @@
+-- PostgreSQL cannot drop an enum value, so withdrawn bids fall back to Closed.
+UPDATE bid SET status = 'Closed' WHERE status = 'Canceled';
+
+ALTER TABLE bid
+    DROP COLUMN IF EXISTS withdrawal_reason,
+    DROP COLUMN IF EXISTS withdrawn_at;
@@ -0,0 +1,5 @@
+ALTER TYPE status_type ADD VALUE IF NOT EXISTS 'Canceled';
+
+ALTER TABLE bid
+    ADD COLUMN IF NOT EXISTS withdrawn_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS withdrawal_reason TEXT;
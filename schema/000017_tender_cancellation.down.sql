@@ -0,0 +1,5 @@
+UPDATE tender SET status = 'Closed' WHERE status = 'Canceled';
+
+ALTER TABLE tender
+    DROP COLUMN IF EXISTS canceled_at,
+    DROP COLUMN IF EXISTS cancellation_reason;
@@ -0,0 +1,3 @@
+ALTER TABLE tender
+    ADD COLUMN IF NOT EXISTS cancellation_reason TEXT,
+    ADD COLUMN IF NOT EXISTS canceled_at TIMESTAMPTZ;
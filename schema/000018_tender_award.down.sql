@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS tender_award;
+
+ALTER TABLE bid
+    DROP COLUMN IF EXISTS award_status;
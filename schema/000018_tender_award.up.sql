@@ -0,0 +1,14 @@
+ALTER TABLE bid
+    ADD COLUMN IF NOT EXISTS award_status VARCHAR(20) CHECK (award_status IN ('Awarded', 'NotAwarded'));
+
+CREATE TABLE IF NOT EXISTS tender_award (
+    tender_id UUID PRIMARY KEY REFERENCES tender(id) ON DELETE CASCADE,
+    bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+    author_type type_author NOT NULL,
+    author_id UUID NOT NULL,
+    price NUMERIC(19, 4),
+    currency CHAR(3),
+    awarded_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    awarded_by VARCHAR(50) NOT NULL,
+    ranking_id BIGINT REFERENCES tender_ranking(id) ON DELETE SET NULL
+);
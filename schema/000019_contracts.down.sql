@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS contract_milestone_update;
+DROP TABLE IF EXISTS contract_milestone;
+DROP TABLE IF EXISTS contract;
@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS contract (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL UNIQUE REFERENCES tender(id) ON DELETE CASCADE,
+    bid_id UUID NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    supplier_type type_author NOT NULL,
+    supplier_id UUID NOT NULL,
+    price NUMERIC(19, 4),
+    currency CHAR(3),
+    status VARCHAR(20) NOT NULL DEFAULT 'Active' CHECK (status IN ('Active', 'Completed')),
+    created_by VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS contract_milestone (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    contract_id UUID NOT NULL REFERENCES contract(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    description TEXT NOT NULL,
+    due_date TIMESTAMPTZ NOT NULL,
+    amount NUMERIC(19, 4) CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'Pending'
+        CHECK (status IN ('Pending', 'InProgress', 'Delivered', 'Accepted', 'Rejected')),
+    progress INT NOT NULL DEFAULT 0 CHECK (progress BETWEEN 0 AND 100),
+    overdue_at TIMESTAMPTZ,
+    reviewed_by VARCHAR(50),
+    reviewed_at TIMESTAMPTZ,
+    review_comment TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (contract_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS contract_milestone_due_idx ON contract_milestone (due_date) WHERE overdue_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS contract_milestone_update (
+    id BIGSERIAL PRIMARY KEY,
+    milestone_id UUID NOT NULL REFERENCES contract_milestone(id) ON DELETE CASCADE,
+    progress INT NOT NULL CHECK (progress BETWEEN 0 AND 100),
+    comment TEXT,
+    author_username VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS contract_milestone_update_milestone_idx ON contract_milestone_update (milestone_id, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS prequalification;
+DROP TABLE IF EXISTS tender_requirement;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS tender_requirement (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('Licence', 'Experience')),
+    description TEXT NOT NULL,
+    min_years INT NOT NULL DEFAULT 0 CHECK (min_years >= 0),
+    UNIQUE (tender_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS prequalification (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tender_id UUID NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
+    author_type type_author NOT NULL,
+    author_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'Pending' CHECK (status IN ('Pending', 'Approved', 'Rejected')),
+    evidence JSONB NOT NULL,
+    submitted_by VARCHAR(50) NOT NULL,
+    submitted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    reviewed_by VARCHAR(50),
+    reviewed_at TIMESTAMPTZ,
+    review_comment TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS prequalification_author_idx ON prequalification (tender_id, author_type, author_id)
+    WHERE status <> 'Rejected';
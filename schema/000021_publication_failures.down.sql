@@ -0,0 +1,2 @@
+ALTER TABLE tender DROP COLUMN IF EXISTS publish_error;
+ALTER TABLE tender DROP COLUMN IF EXISTS publish_attempts;
@@ -0,0 +1,2 @@
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS publish_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS publish_error TEXT;
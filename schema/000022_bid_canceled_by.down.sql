@@ -0,0 +1 @@
+ALTER TABLE bid DROP COLUMN IF EXISTS canceled_by;
@@ -0,0 +1,5 @@
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS canceled_by VARCHAR(20);
+
+UPDATE bid
+SET canceled_by = CASE WHEN withdrawn_at IS NOT NULL THEN 'Author' ELSE 'Tender' END
+WHERE status = 'Canceled' AND canceled_by IS NULL;
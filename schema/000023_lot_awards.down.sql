@@ -0,0 +1,11 @@
+DELETE FROM contract WHERE lot_id IS NOT NULL;
+DROP INDEX IF EXISTS contract_lot_idx;
+DROP INDEX IF EXISTS contract_tender_idx;
+ALTER TABLE contract DROP COLUMN IF EXISTS lot_id;
+ALTER TABLE contract ADD CONSTRAINT contract_tender_id_key UNIQUE (tender_id);
+
+DELETE FROM tender_award WHERE lot_id IS NOT NULL;
+DROP INDEX IF EXISTS tender_award_lot_idx;
+DROP INDEX IF EXISTS tender_award_tender_idx;
+ALTER TABLE tender_award DROP COLUMN IF EXISTS lot_id;
+ALTER TABLE tender_award ADD PRIMARY KEY (tender_id);
@@ -0,0 +1,10 @@
+-- Lots are awarded one by one, so a lot tender has an award and a contract per lot.
+ALTER TABLE tender_award ADD COLUMN IF NOT EXISTS lot_id UUID REFERENCES tender_lot(id) ON DELETE CASCADE;
+ALTER TABLE tender_award DROP CONSTRAINT IF EXISTS tender_award_pkey;
+CREATE UNIQUE INDEX IF NOT EXISTS tender_award_tender_idx ON tender_award (tender_id) WHERE lot_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS tender_award_lot_idx ON tender_award (lot_id) WHERE lot_id IS NOT NULL;
+
+ALTER TABLE contract ADD COLUMN IF NOT EXISTS lot_id UUID REFERENCES tender_lot(id) ON DELETE CASCADE;
+ALTER TABLE contract DROP CONSTRAINT IF EXISTS contract_tender_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS contract_tender_idx ON contract (tender_id) WHERE lot_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS contract_lot_idx ON contract (lot_id) WHERE lot_id IS NOT NULL;
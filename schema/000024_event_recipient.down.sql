@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS outbox_event_recipient_idx;
+ALTER TABLE outbox_event DROP COLUMN IF EXISTS recipient_id;
@@ -0,0 +1,4 @@
+-- Events about a bid author or a supplier are addressed to the employee too, so
+-- they reach people who are not responsible for any organization.
+ALTER TABLE outbox_event ADD COLUMN IF NOT EXISTS recipient_id UUID;
+CREATE INDEX IF NOT EXISTS outbox_event_recipient_idx ON outbox_event (recipient_id, id) WHERE recipient_id IS NOT NULL;
@@ -0,0 +1,6 @@
+-- Buckets are swept once they have refilled, which depends on the limit of
+-- each route rather than on a fixed idle time.
+ALTER TABLE rate_limit_bucket ADD COLUMN IF NOT EXISTS full_at TIMESTAMPTZ;
+UPDATE rate_limit_bucket SET full_at = updated_at + INTERVAL '1 hour' WHERE full_at IS NULL;
+ALTER TABLE rate_limit_bucket ALTER COLUMN full_at SET NOT NULL;
+CREATE INDEX IF NOT EXISTS rate_limit_bucket_full_at_idx ON rate_limit_bucket (full_at);